@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qubitquilt/supactl/internal/manifest"
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manifestFile        string
+	manifestPrune       bool
+	manifestShowSecrets bool
+	applyDryRun         bool
+	applyForce          bool
+)
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <file>",
+	Short: "Make instances match a declarative manifest",
+	Long: `Make the instances of the current context match a manifest file.
+
+Missing instances are created, then labels and configuration are written and
+each instance is started or stopped to reach its desired state. Running
+instances whose configuration changed are restarted. Fields omitted from the
+manifest are left untouched.
+
+Instances that exist but are not listed in the manifest are only deleted with
+--prune. You will be asked to confirm deletions unless --force is given.
+
+Manifest format:
+  apiVersion: supactl/v1
+  kind: InstanceList
+  instances:
+    - name: my-project
+      state: running          # running or stopped
+      labels:
+        team: core
+      config:
+        SITE_URL: https://example.com
+        SMTP_HOST: null       # null unsets a key
+
+Examples:
+  supactl apply -f instances.yaml
+  supactl apply -f instances.yaml --dry-run
+  supactl apply -f instances.yaml --prune`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		p, plan := planManifest()
+
+		writePlan(plan)
+		if !plan.HasChanges() || applyDryRun {
+			return
+		}
+
+		if deletions := plan.Count(manifest.ActionDelete); deletions > 0 && !applyForce {
+			if !confirm(fmt.Sprintf("This will permanently delete %d instance(s). Continue?", deletions)) {
+				fmt.Println("Apply cancelled.")
+				cancelAudit()
+				return
+			}
+		}
+
+		fmt.Println()
+		if err := manifest.Apply(p, plan, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Println("\nApply complete.")
+	},
+}
+
+// planManifest loads the manifest given by -f and computes the plan for the current context
+func planManifest() (provider.InstanceProvider, *manifest.Plan) {
+	if manifestFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: A manifest file is required (-f <file>)\n")
+		exit(1)
+	}
+
+	m, err := manifest.Load(manifestFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+
+	p := getProvider()
+
+	observed, err := manifest.Observe(p, m)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+
+	return p, manifest.ComputePlan(m, observed, manifestPrune)
+}
+
+// writePlan prints a plan, masking secret configuration values unless --show-secrets is given
+func writePlan(plan *manifest.Plan) {
+	mask := maskConfigValue
+	if manifestShowSecrets {
+		mask = nil
+	}
+	plan.Write(os.Stdout, mask)
+}
+
+// addManifestFlags registers the flags shared by apply and diff
+func addManifestFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&manifestFile, "filename", "f", "", "Manifest file to use (- for stdin)")
+	cmd.Flags().BoolVar(&manifestPrune, "prune", false, "Delete instances that are not listed in the manifest")
+	cmd.Flags().BoolVar(&manifestShowSecrets, "show-secrets", false, "Show secret configuration values in the plan")
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	addManifestFlags(applyCmd)
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Print the plan without applying it")
+	applyCmd.Flags().BoolVar(&applyForce, "force", false, "Delete pruned instances without confirmation")
+}
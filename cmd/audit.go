@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/audit"
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// auditedCommands are the mutating commands recorded in the audit log, by their path below the root command
+var auditedCommands = map[string]bool{
+	"create":                    true,
+	"delete":                    true,
+	"start":                     true,
+	"stop":                      true,
+	"restart":                   true,
+	"down":                      true,
+	"apply":                     true,
+	"instance config set":       true,
+	"instance config unset":     true,
+	"db exec":                   true,
+	"db reset":                  true,
+	"db migrate up":             true,
+	"db migrate down":           true,
+	"functions deploy":          true,
+	"functions delete":          true,
+	"storage buckets create":    true,
+	"storage buckets delete":    true,
+	"storage cp":                true,
+	"storage rm":                true,
+	"auth users create":         true,
+	"auth users delete":         true,
+	"auth users invite":         true,
+	"auth users reset-password": true,
+	"local add":                 true,
+	"local remove":              true,
+	"local start":               true,
+	"local stop":                true,
+	"local migrate":             true,
+	"local set-resources":       true,
+}
+
+var (
+	// pendingAudit is the record of the running command, written when it finishes
+	pendingAudit *audit.Record
+	auditStarted time.Time
+)
+
+// commandAction returns the path of a command below the root command, e.g. "local remove"
+func commandAction(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// startAudit begins the audit record of a mutating command. The target defaults to the
+// first argument in the current context and is refined when the instance is resolved.
+func startAudit(cmd *cobra.Command, args []string) {
+	action := commandAction(cmd)
+	if !auditedCommands[action] {
+		return
+	}
+
+	record := &audit.Record{User: audit.CurrentUser(), Action: action}
+	if len(args) > 0 {
+		record.Instance = args[0]
+	}
+
+	if strings.HasPrefix(action, "local ") {
+		record.Provider = "local"
+	} else if config, err := auth.LoadConfig(); err == nil {
+		applyProjectContext(config)
+		record.Context = config.CurrentContext
+		if ctx, ok := config.Contexts[config.CurrentContext]; ok {
+			record.Provider = ctx.Provider
+		}
+	}
+
+	pendingAudit, auditStarted = record, time.Now()
+}
+
+// auditTarget records the instance an audited command acts on
+func auditTarget(contextName, providerType, instanceName string) {
+	if pendingAudit == nil {
+		return
+	}
+	pendingAudit.Context = contextName
+	pendingAudit.Provider = providerType
+	pendingAudit.Instance = instanceName
+}
+
+// cancelAudit records that the user declined to go ahead
+func cancelAudit() {
+	if pendingAudit != nil {
+		pendingAudit.Result = audit.ResultCancelled
+	}
+}
+
+// finishAudit writes the record of the running command, if it is audited. A failure
+// to write is reported but does not change the outcome of the command.
+func finishAudit(exitCode int) {
+	record := pendingAudit
+	if record == nil {
+		return
+	}
+	pendingAudit = nil
+
+	switch {
+	case exitCode != 0:
+		record.Result = audit.ResultFailure
+		record.Error = fmt.Sprintf("exit status %d", exitCode)
+	case record.Result == "":
+		record.Result = audit.ResultSuccess
+	}
+	record.Time = auditStarted.UTC()
+	record.DurationMS = time.Since(auditStarted).Milliseconds()
+
+	if err := appendAudit(*record); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// appendAudit adds a record to the local audit log
+func appendAudit(record audit.Record) error {
+	path, err := audit.GetAuditPath()
+	if err != nil {
+		return err
+	}
+	return audit.Append(path, record)
+}
+
+// exit finishes the audit record of the running command and exits with code.
+// Commands call it instead of os.Exit so failures are audited too.
+func exit(code int) {
+	finishAudit(code)
+	os.Exit(code)
+}
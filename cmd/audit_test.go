@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/audit"
+	"github.com/qubitquilt/supactl/internal/auth"
+)
+
+func readAuditLog(t *testing.T) []audit.Record {
+	t.Helper()
+	path, err := audit.GetAuditPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := audit.Read(path, audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestAudit(t *testing.T) {
+	setupCompletionHome(t, "prod", map[string]*auth.ContextConfig{
+		"local": {Provider: "local"},
+		"prod":  {Provider: "remote", ServerURL: "https://prod.example.com", APIKey: "key"},
+	})
+	chdirProject(t, t.TempDir())
+
+	// Read-only commands are not audited
+	startAudit(getCmd, []string{"instances"})
+	finishAudit(0)
+	if records := readAuditLog(t); len(records) != 0 {
+		t.Fatalf("expected no records, got %+v", records)
+	}
+
+	startAudit(deleteCmd, []string{"app"})
+	cancelAudit()
+	finishAudit(0)
+
+	startAudit(localRemoveCmd, []string{"dev"})
+	finishAudit(1)
+
+	// The resolved target replaces the first argument
+	startAudit(startCmd, nil)
+	auditTarget("local", "local", "linked-app")
+	finishAudit(0)
+
+	records := readAuditLog(t)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %+v", records)
+	}
+
+	deleted := records[0]
+	if deleted.Action != "delete" || deleted.Instance != "app" || deleted.Context != "prod" || deleted.Provider != "remote" || deleted.Result != audit.ResultCancelled || deleted.User == "" {
+		t.Errorf("unexpected delete record: %+v", deleted)
+	}
+
+	removed := records[1]
+	if removed.Action != "local remove" || removed.Provider != "local" || removed.Context != "" || removed.Result != audit.ResultFailure || removed.Error != "exit status 1" {
+		t.Errorf("unexpected local remove record: %+v", removed)
+	}
+
+	started := records[2]
+	if started.Action != "start" || started.Context != "local" || started.Instance != "linked-app" || started.Result != audit.ResultSuccess {
+		t.Errorf("unexpected start record: %+v", started)
+	}
+}
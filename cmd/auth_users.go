@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/gotrue"
+	"github.com/spf13/cobra"
+)
+
+var (
+	authUsersOutput     string
+	authUserEmail       string
+	authUserPhone       string
+	authUserPassword    string
+	authUserMetadata    map[string]string
+	authUserAutoConfirm bool
+	authUsersCSV        string
+	authUsersForce      bool
+	authResetPassword   string
+	authResetPrintLink  bool
+)
+
+// authCmd groups commands for an instance's Auth (GoTrue) service
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage an instance's Auth service",
+}
+
+// authUsersCmd groups the user administration commands
+var authUsersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Manage the users of an instance",
+	Long: `Manage the users of an instance through the Auth admin API.
+
+Requests go through the instance's API gateway with the service role key:
+local instances read it from the project's .env file (or sign it with the
+project's JWT secret), remote instances use the key reported by the
+SupaControl server.
+
+Users are identified by ID or email address.
+
+Examples:
+  supactl auth users list my-project -o json
+  supactl auth users create my-project --email dev@example.com --password secret
+  supactl auth users create my-project --from-csv test-users.csv
+  supactl auth users invite my-project new@example.com
+  supactl auth users reset-password my-project dev@example.com --password n3w
+  supactl auth users delete my-project dev@example.com`,
+}
+
+var authUsersListCmd = &cobra.Command{
+	Use:   "list <instance>",
+	Short: "List users",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkAuthOutput()
+		client, _ := getAuthClient(args[0])
+
+		users, err := client.ListUsers()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to list users: %v\n", err)
+			exit(1)
+		}
+
+		if authUsersOutput == "json" {
+			writeJSON(users)
+			return
+		}
+
+		if len(users) == 0 {
+			fmt.Println("No users found.")
+			return
+		}
+		writeUsersTable(users)
+	},
+}
+
+var authUsersCreateCmd = &cobra.Command{
+	Use:   "create <instance>",
+	Short: "Create a user, or bulk-create users from a CSV file",
+	Long: `Create a user without sending any email.
+
+With --from-csv, every row of the file is created (local instances only).
+The CSV needs a header row with an email or phone column; the password and
+email_confirm columns are optional and any other column is stored in the
+user's metadata:
+
+  email,password,name
+  alice@example.com,secret1,Alice
+  bob@example.com,secret2,Bob`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkAuthOutput()
+		client, isLocal := getAuthClient(args[0])
+
+		var requests []gotrue.CreateUserRequest
+		if authUsersCSV != "" {
+			if !isLocal {
+				fmt.Fprintf(os.Stderr, "Error: CSV import is only available for local instances\n")
+				exit(1)
+			}
+
+			f, err := os.Open(authUsersCSV)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to open CSV file: %v\n", err)
+				exit(1)
+			}
+			requests, err = gotrue.ParseUsersCSV(f, authUserAutoConfirm)
+			f.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+		} else {
+			if authUserEmail == "" && authUserPhone == "" {
+				fmt.Fprintf(os.Stderr, "Error: --email or --phone is required (or use --from-csv)\n")
+				exit(1)
+			}
+			requests = []gotrue.CreateUserRequest{{
+				Email:        authUserEmail,
+				Phone:        authUserPhone,
+				Password:     authUserPassword,
+				EmailConfirm: authUserAutoConfirm && authUserEmail != "",
+				PhoneConfirm: authUserAutoConfirm && authUserPhone != "",
+				UserMetadata: stringMetadata(authUserMetadata),
+			}}
+		}
+
+		created := make([]gotrue.User, 0, len(requests))
+		failed := 0
+		for _, req := range requests {
+			user, err := client.CreateUser(req)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to create user '%s': %v\n", userLabel(req.Email, req.Phone), err)
+				failed++
+				continue
+			}
+			created = append(created, *user)
+		}
+
+		if authUsersOutput == "json" {
+			writeJSON(created)
+		} else {
+			for _, user := range created {
+				fmt.Printf("Created user %s (%s)\n", userLabel(user.Email, user.Phone), user.ID)
+			}
+		}
+
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "Error: %d of %d users could not be created\n", failed, len(requests))
+			exit(1)
+		}
+	},
+}
+
+var authUsersDeleteCmd = &cobra.Command{
+	Use:   "delete <instance> <user>",
+	Short: "Delete a user",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, _ := getAuthClient(args[0])
+		user := findUser(client, args[1])
+
+		if !authUsersForce {
+			if !confirm(fmt.Sprintf("Are you sure you want to delete user '%s'?", userLabel(user.Email, user.Phone))) {
+				fmt.Println("Deletion cancelled.")
+				cancelAudit()
+				return
+			}
+		}
+
+		if err := client.DeleteUser(user.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to delete user: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("Deleted user %s (%s)\n", userLabel(user.Email, user.Phone), user.ID)
+	},
+}
+
+var authUsersInviteCmd = &cobra.Command{
+	Use:   "invite <instance> <email>",
+	Short: "Invite a user by email",
+	Long: `Create a user and send them an invitation email. The instance's SMTP
+settings must be configured for the email to be delivered.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkAuthOutput()
+		client, _ := getAuthClient(args[0])
+
+		user, err := client.InviteUser(args[1], stringMetadata(authUserMetadata))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to invite user: %v\n", err)
+			exit(1)
+		}
+
+		if authUsersOutput == "json" {
+			writeJSON(user)
+			return
+		}
+		fmt.Printf("Invited %s (%s)\n", user.Email, user.ID)
+	},
+}
+
+var authUsersResetPasswordCmd = &cobra.Command{
+	Use:   "reset-password <instance> <user>",
+	Short: "Reset a user's password",
+	Long: `Reset a user's password.
+
+By default a recovery email is sent. Use --password to set a new password
+directly, or --link to print a recovery link instead of sending an email
+(useful for local instances without SMTP).`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, _ := getAuthClient(args[0])
+		user := findUser(client, args[1])
+
+		switch {
+		case authResetPassword != "":
+			if err := client.UpdatePassword(user.ID, authResetPassword); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to set password: %v\n", err)
+				exit(1)
+			}
+			fmt.Printf("Password of %s updated\n", userLabel(user.Email, user.Phone))
+		case user.Email == "":
+			fmt.Fprintf(os.Stderr, "Error: User has no email address; use --password to set a new password\n")
+			exit(1)
+		case authResetPrintLink:
+			link, err := client.RecoveryLink(user.Email)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to generate recovery link: %v\n", err)
+				exit(1)
+			}
+			fmt.Println(link)
+		default:
+			if err := client.SendRecovery(user.Email); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to send recovery email: %v\n", err)
+				exit(1)
+			}
+			fmt.Printf("Recovery email sent to %s\n", user.Email)
+		}
+	},
+}
+
+// getAuthClient returns an Auth admin API client for an instance reference
+// and whether the instance is local
+func getAuthClient(ref string) (*gotrue.Client, bool) {
+	instance, key := getGatewayAccess(ref)
+	return gotrue.NewClient(instance.GatewayURL(), key), instance.Directory != ""
+}
+
+// findUser looks up a user by ID or email, exiting on failure
+func findUser(client *gotrue.Client, idOrEmail string) *gotrue.User {
+	user, err := client.FindUser(idOrEmail)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+	return user
+}
+
+// checkAuthOutput validates the --output flag
+func checkAuthOutput() {
+	if authUsersOutput != "table" && authUsersOutput != "json" {
+		fmt.Fprintf(os.Stderr, "Error: Invalid output format '%s' (expected table or json)\n", authUsersOutput)
+		exit(1)
+	}
+}
+
+// writeUsersTable prints users as a table
+func writeUsersTable(users []gotrue.User) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tEMAIL\tPHONE\tCONFIRMED\tLAST SIGN IN\tCREATED")
+	for _, u := range users {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\n", u.ID, orDash(u.Email), orDash(u.Phone),
+			u.EmailConfirmedAt != nil, formatOptionalTime(u.LastSignInAt), u.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+	w.Flush()
+}
+
+// writeJSON prints a value as indented JSON, exiting on failure
+func writeJSON(v interface{}) {
+	if err := writeJSONTo(os.Stdout, v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+}
+
+// writeJSONTo writes a value as indented JSON
+func writeJSONTo(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// stringMetadata converts --metadata flags to user metadata
+func stringMetadata(values map[string]string) map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	metadata := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		metadata[k] = v
+	}
+	return metadata
+}
+
+// userLabel returns the email of a user, or the phone number if there is none
+func userLabel(email, phone string) string {
+	if email != "" {
+		return email
+	}
+	return phone
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authUsersCmd)
+	authUsersCmd.AddCommand(authUsersListCmd, authUsersCreateCmd, authUsersDeleteCmd, authUsersInviteCmd, authUsersResetPasswordCmd)
+
+	for _, c := range []*cobra.Command{authUsersListCmd, authUsersCreateCmd, authUsersInviteCmd} {
+		c.Flags().StringVarP(&authUsersOutput, "output", "o", "table", "Output format: table or json")
+	}
+	for _, c := range []*cobra.Command{authUsersCreateCmd, authUsersInviteCmd} {
+		c.Flags().StringToStringVar(&authUserMetadata, "metadata", nil, "User metadata as key=value pairs")
+	}
+
+	authUsersCreateCmd.Flags().StringVar(&authUserEmail, "email", "", "Email address of the user")
+	authUsersCreateCmd.Flags().StringVar(&authUserPhone, "phone", "", "Phone number of the user")
+	authUsersCreateCmd.Flags().StringVar(&authUserPassword, "password", "", "Password of the user")
+	authUsersCreateCmd.Flags().BoolVar(&authUserAutoConfirm, "auto-confirm", true, "Mark the email address or phone number as confirmed")
+	authUsersCreateCmd.Flags().StringVar(&authUsersCSV, "from-csv", "", "CSV file of users to create (local instances only)")
+	authUsersCreateCmd.MarkFlagsMutuallyExclusive("from-csv", "email")
+	authUsersCreateCmd.MarkFlagsMutuallyExclusive("from-csv", "phone")
+
+	authUsersDeleteCmd.Flags().BoolVar(&authUsersForce, "force", false, "Delete without asking for confirmation")
+
+	authUsersResetPasswordCmd.Flags().StringVar(&authResetPassword, "password", "", "Set this password instead of sending a recovery email")
+	authUsersResetPasswordCmd.Flags().BoolVar(&authResetPrintLink, "link", false, "Print a recovery link instead of sending an email")
+	authUsersResetPasswordCmd.MarkFlagsMutuallyExclusive("password", "link")
+}
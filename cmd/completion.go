@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/cache"
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// completionCacheTTL is how long instance names are reused between completions
+const completionCacheTTL = 30 * time.Second
+
+// getResourceTypes and describeResourceTypes are the resource types accepted by get and describe
+var (
+	getResourceTypes      = []string{"instances"}
+	describeResourceTypes = []string{"instance"}
+)
+
+// completeInstances completes the first argument with instance names
+func completeInstances(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return instanceCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContexts completes the first argument with context names
+func completeContexts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	config, err := auth.LoadConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := config.ListContexts()
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeGet completes the resource type of get
+func completeGet(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return getResourceTypes, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeDescribe completes the resource type, then the instance name of describe
+func completeDescribe(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return describeResourceTypes, cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		return instanceCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// instanceCompletions returns the instance names matching toComplete. References
+// containing a "/" complete the instances of the named context as context/instance.
+func instanceCompletions(toComplete string) []string {
+	config, err := auth.LoadConfig()
+	if err != nil {
+		return nil
+	}
+	applyProjectContext(config)
+
+	contextName, prefix := config.CurrentContext, ""
+	if name, _, found := strings.Cut(toComplete, "/"); found {
+		contextName, prefix = name, name+"/"
+	}
+
+	names, err := cachedInstanceNames(config, contextName)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+
+	completions := make([]string, len(names))
+	for i, name := range names {
+		completions[i] = prefix + name
+	}
+	return completions
+}
+
+// cachedInstanceNames returns the instance names of a context, from the cache if
+// they were listed recently
+func cachedInstanceNames(config *auth.Config, contextName string) ([]string, error) {
+	ctx, exists := config.Contexts[contextName]
+	if !exists {
+		return nil, fmt.Errorf("context '%s' does not exist", contextName)
+	}
+
+	key := "instances-" + contextName
+	store, err := cache.NewStore(completionCacheTTL)
+	if err == nil {
+		var names []string
+		if store.Get(key, &names) {
+			return names, nil
+		}
+	}
+
+	p, err := newProvider(contextName, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := p.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(instances))
+	for i, instance := range instances {
+		names[i] = instance.Name
+	}
+	sort.Strings(names)
+
+	if store != nil {
+		if err := store.Set(key, names); err != nil {
+			cobra.CompDebugln(err.Error(), true)
+		}
+	}
+
+	return names, nil
+}
+
+// invalidateInstanceNames drops the cached instance names of a context after an instance
+// was created or deleted in it. All local contexts share the local database, so a local
+// context drops the names of every local context.
+func invalidateInstanceNames(contextName string) {
+	config, err := auth.LoadConfig()
+	if err != nil {
+		return
+	}
+
+	if ctx, exists := config.Contexts[contextName]; exists && ctx.Provider == provider.ProviderTypeLocal {
+		invalidateLocalInstanceNames()
+		return
+	}
+
+	if store, err := cache.NewStore(completionCacheTTL); err == nil {
+		store.Delete("instances-" + contextName)
+	}
+}
+
+// invalidateLocalInstanceNames drops the cached instance names of every local context
+func invalidateLocalInstanceNames() {
+	config, err := auth.LoadConfig()
+	if err != nil {
+		return
+	}
+
+	store, err := cache.NewStore(completionCacheTTL)
+	if err != nil {
+		return
+	}
+	for name, ctx := range config.Contexts {
+		if ctx.Provider == provider.ProviderTypeLocal {
+			store.Delete("instances-" + name)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/cache"
+)
+
+// setupCompletionHome points HOME at a temporary directory with the given contexts
+func setupCompletionHome(t *testing.T, current string, contexts map[string]*auth.ContextConfig) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	if err := auth.SaveConfig(&auth.Config{CurrentContext: current, Contexts: contexts}); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+}
+
+func TestCompleteContexts(t *testing.T) {
+	setupCompletionHome(t, "local", map[string]*auth.ContextConfig{
+		"local":   {Provider: "local"},
+		"staging": {Provider: "remote", ServerURL: "https://staging.example.com", APIKey: "key"},
+		"prod":    {Provider: "remote", ServerURL: "https://prod.example.com", APIKey: "key"},
+	})
+
+	got, _ := completeContexts(configUseContextCmd, nil, "")
+	want := []string{"local", "prod", "staging"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("completeContexts() = %v, want %v", got, want)
+	}
+
+	if got, _ := completeContexts(configUseContextCmd, []string{"local"}, ""); len(got) != 0 {
+		t.Errorf("expected no completions after the first argument, got %v", got)
+	}
+}
+
+func TestCompleteResourceTypes(t *testing.T) {
+	if got, _ := completeGet(getCmd, nil, ""); !reflect.DeepEqual(got, []string{"instances"}) {
+		t.Errorf("completeGet() = %v", got)
+	}
+	if got, _ := completeDescribe(describeCmd, nil, ""); !reflect.DeepEqual(got, []string{"instance"}) {
+		t.Errorf("completeDescribe() = %v", got)
+	}
+}
+
+func TestCompleteInstances_Cache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"instances": [{"name": "beta"}, {"name": "alpha"}]}`))
+	}))
+	defer server.Close()
+
+	setupCompletionHome(t, "prod", map[string]*auth.ContextConfig{
+		"local": {Provider: "local"},
+		"prod":  {Provider: "remote", ServerURL: server.URL, APIKey: "key"},
+	})
+
+	for i := 0; i < 2; i++ {
+		got, _ := completeInstances(startCmd, nil, "")
+		if !reflect.DeepEqual(got, []string{"alpha", "beta"}) {
+			t.Errorf("completeInstances() = %v", got)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request thanks to the cache, got %d", requests)
+	}
+
+	// A context prefix completes that context's instances
+	store, err := cache.NewStore(completionCacheTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("instances-local", []string{"my-project"}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := completeInstances(startCmd, nil, "local/")
+	if !reflect.DeepEqual(got, []string{"local/my-project"}) {
+		t.Errorf("completeInstances(local/) = %v", got)
+	}
+}
+
+func TestInvalidateInstanceNames(t *testing.T) {
+	setupCompletionHome(t, "prod", map[string]*auth.ContextConfig{
+		"local":     {Provider: "local"},
+		"local-api": {Provider: "local", DockerRunner: "api"},
+		"prod":      {Provider: "remote", ServerURL: "https://prod.example.com", APIKey: "key"},
+		"staging":   {Provider: "remote", ServerURL: "https://staging.example.com", APIKey: "key"},
+	})
+
+	store, err := cache.NewStore(completionCacheTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill := func() {
+		for _, name := range []string{"local", "local-api", "prod", "staging"} {
+			if err := store.Set("instances-"+name, []string{"old"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	cached := func() []string {
+		var names []string
+		for _, name := range []string{"local", "local-api", "prod", "staging"} {
+			var v []string
+			if store.Get("instances-"+name, &v) {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	fill()
+	invalidateInstanceNames("prod")
+	if got := cached(); !reflect.DeepEqual(got, []string{"local", "local-api", "staging"}) {
+		t.Errorf("after a remote change, cached = %v", got)
+	}
+
+	fill()
+	invalidateInstanceNames("local")
+	if got := cached(); !reflect.DeepEqual(got, []string{"prod", "staging"}) {
+		t.Errorf("after a local change, cached = %v", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/local"
 	"github.com/qubitquilt/supactl/internal/provider"
 	"github.com/spf13/cobra"
 )
@@ -36,7 +37,7 @@ var configGetContextsCmd = &cobra.Command{
 		config, err := auth.LoadConfig()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to load config: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if len(config.Contexts) == 0 {
@@ -79,24 +80,25 @@ var configUseContextCmd = &cobra.Command{
 Example:
   supactl config use-context local
   supactl config use-context production`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContexts,
 	Run: func(cmd *cobra.Command, args []string) {
 		contextName := args[0]
 
 		config, err := auth.LoadConfig()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to load config: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if err := config.SetCurrentContext(contextName); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if err := auth.SaveConfig(config); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to save config: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("Switched to context '%s'\n", contextName)
@@ -112,7 +114,7 @@ var configCurrentContextCmd = &cobra.Command{
 		config, err := auth.LoadConfig()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to load config: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Println(config.CurrentContext)
@@ -124,6 +126,8 @@ var (
 	setContextProvider string
 	setContextServer   string
 	setContextAPIKey   string
+	setContextRunner   string
+	setContextSocket   string
 )
 
 var configSetContextCmd = &cobra.Command{
@@ -135,6 +139,9 @@ Examples:
   # Set local context
   supactl config set-context local --provider=local
 
+  # Set local context that talks to the Docker Engine API instead of the docker CLI
+  supactl config set-context local --provider=local --docker-runner=api --docker-socket=/var/run/docker.sock
+
   # Set remote context
   supactl config set-context prod --provider=remote --server=https://api.example.com --api-key=sk_...`,
 	Args: cobra.ExactArgs(1),
@@ -144,19 +151,33 @@ Examples:
 		// Validate provider
 		if setContextProvider != provider.ProviderTypeLocal && setContextProvider != provider.ProviderTypeRemote {
 			fmt.Fprintf(os.Stderr, "Error: Provider must be '%s' or '%s'\n", provider.ProviderTypeLocal, provider.ProviderTypeRemote)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Validate remote context has required fields
 		if setContextProvider == provider.ProviderTypeRemote && (setContextServer == "" || setContextAPIKey == "") {
 			fmt.Fprintf(os.Stderr, "Error: Remote contexts require --server and --api-key flags\n")
-			os.Exit(1)
+			exit(1)
+		}
+
+		// Validate the docker runner of local contexts
+		if setContextProvider != provider.ProviderTypeLocal && (setContextRunner != "" || setContextSocket != "") {
+			fmt.Fprintf(os.Stderr, "Error: --docker-runner and --docker-socket only apply to local contexts\n")
+			exit(1)
+		}
+		if setContextRunner != "" && setContextRunner != local.RunnerCLI && setContextRunner != local.RunnerAPI {
+			fmt.Fprintf(os.Stderr, "Error: Docker runner must be '%s' or '%s'\n", local.RunnerCLI, local.RunnerAPI)
+			exit(1)
+		}
+		if setContextSocket != "" && setContextRunner != local.RunnerAPI {
+			fmt.Fprintf(os.Stderr, "Error: --docker-socket requires --docker-runner=%s\n", local.RunnerAPI)
+			exit(1)
 		}
 
 		config, err := auth.LoadConfig()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to load config: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Create or update context
@@ -167,13 +188,16 @@ Examples:
 		if setContextProvider == provider.ProviderTypeRemote {
 			ctx.ServerURL = setContextServer
 			ctx.APIKey = setContextAPIKey
+		} else {
+			ctx.DockerRunner = setContextRunner
+			ctx.DockerSocket = setContextSocket
 		}
 
 		config.AddContext(contextName, ctx)
 
 		if err := auth.SaveConfig(config); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to save config: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("Context '%s' created/updated\n", contextName)
@@ -187,24 +211,25 @@ var configDeleteContextCmd = &cobra.Command{
 	Long: `Delete a context from the configuration.
 
 Cannot delete the 'local' context or the current context.`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContexts,
 	Run: func(cmd *cobra.Command, args []string) {
 		contextName := args[0]
 
 		config, err := auth.LoadConfig()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to load config: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if err := config.RemoveContext(contextName); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if err := auth.SaveConfig(config); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to save config: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("Context '%s' deleted\n", contextName)
@@ -224,5 +249,7 @@ func init() {
 	configSetContextCmd.Flags().StringVar(&setContextProvider, "provider", "", "Provider type (local or remote)")
 	configSetContextCmd.Flags().StringVar(&setContextServer, "server", "", "Server URL (for remote provider)")
 	configSetContextCmd.Flags().StringVar(&setContextAPIKey, "api-key", "", "API key (for remote provider)")
+	configSetContextCmd.Flags().StringVar(&setContextRunner, "docker-runner", "", "How to manage containers: cli or api (for local provider, default cli)")
+	configSetContextCmd.Flags().StringVar(&setContextSocket, "docker-socket", "", "Docker Engine socket for the api runner (default DOCKER_HOST or /var/run/docker.sock)")
 	configSetContextCmd.MarkFlagRequired("provider")
 }
@@ -17,7 +17,8 @@ var createCmd = &cobra.Command{
 	Short: "Create a new Supabase instance",
 	Long: `Create a new Supabase instance.
 
-This command works with remote contexts only. For local instances, use 'supactl local add'.
+In a local context this is equivalent to 'supactl local add' and creates the
+project in ~/<instance-name>.
 The instance name must be lowercase, alphanumeric, and may contain hyphens.
 It must start and end with an alphanumeric character.`,
 	Args: cobra.ExactArgs(1),
@@ -29,7 +30,7 @@ It must start and end with an alphanumeric character.`,
 			fmt.Fprintf(os.Stderr, "Error: Instance name '%s' is invalid.\n", instanceName)
 			fmt.Fprintf(os.Stderr, "Name must be lowercase, alphanumeric, and may contain hyphens.\n")
 			fmt.Fprintf(os.Stderr, "It must start and end with an alphanumeric character.\n")
-			os.Exit(1)
+			exit(1)
 		}
 
 		provider := getProvider()
@@ -39,8 +40,11 @@ It must start and end with an alphanumeric character.`,
 		instance, err := provider.CreateInstance(instanceName)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to create instance: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
+		invalidateInstanceNames(currentContextName())
+
+		applyProjectEnv(provider, instance.Name)
 
 		fmt.Printf("\nSuccessfully created instance '%s'\n\n", instance.Name)
 		fmt.Printf("  Status:     %s\n", instance.Status)
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/database"
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// dbCmd groups commands that work with an instance's Postgres database
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Work with an instance's Postgres database",
+	Long: `Work with an instance's Postgres database.
+
+Credentials are resolved automatically: local instances read them from the
+project's .env file, remote instances use the database URL reported by the
+SupaControl server.
+
+Instances are looked up in the current context. Use <context>/<instance> to
+refer to an instance in another context.
+
+Examples:
+  supactl db shell my-project
+  supactl db exec my-project -c "select count(*) from auth.users"
+  supactl db diff local/my-project production/my-project`,
+}
+
+// getConnInfo resolves the database connection of an instance reference ("instance" or "context/instance")
+func getConnInfo(ref string) *database.ConnInfo {
+	p, instanceName := resolveInstanceRef(ref)
+
+	instance, err := p.GetInstance(instanceName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to get instance details: %v\n", err)
+		exit(1)
+	}
+
+	info, err := database.ForInstance(instance)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+
+	return info
+}
+
+// resolveInstanceRef splits an "instance" or "context/instance" reference and returns
+// the provider of the referenced context (the current one if none is given)
+func resolveInstanceRef(ref string) (provider.InstanceProvider, string) {
+	contextName, instanceName, found := strings.Cut(strings.TrimSpace(ref), "/")
+	if !found {
+		p := getProvider()
+		auditTarget(currentContextName(), p.ProviderType(), contextName)
+		return p, contextName
+	}
+
+	config, err := auth.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to load configuration: %v\n", err)
+		exit(1)
+	}
+
+	ctx, exists := config.Contexts[contextName]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "Error: Context '%s' does not exist\n", contextName)
+		exit(1)
+	}
+
+	p, err := newProvider(contextName, ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Context '%s': %v\n", contextName, err)
+		exit(1)
+	}
+
+	auditTarget(contextName, p.ProviderType(), instanceName)
+	return p, instanceName
+}
+
+// openDatabase connects to an instance's database, exiting on failure
+func openDatabase(ref string) *sql.DB {
+	conn, err := database.Open(getConnInfo(ref))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+	return conn
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+}
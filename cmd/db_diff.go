@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/migrate"
+	"github.com/qubitquilt/supactl/internal/pgschema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbDiffSchemas []string
+	dbDiffOutput  string
+)
+
+var dbDiffCmd = &cobra.Command{
+	Use:   "diff <instance-a> <instance-b>",
+	Short: "Compare the schemas of two instances",
+	Long: `Compare the database schemas of two instances and generate a migration.
+
+Tables, columns, constraints, indexes, RLS policies, functions and triggers are
+read from each instance's pg_catalog. The diff shows what changed going from
+instance A to instance B, and the generated SQL turns A's schema into B's. To
+promote changes from staging to production, pass production first.
+
+Each instance can be given as <instance> (current context) or
+<context>/<instance>.
+
+The migration is written to supabase/migrations/<timestamp>_diff_<a>_<b>.sql
+unless --output is given.
+
+Examples:
+  supactl db diff production/my-project staging/my-project
+  supactl db diff my-project other-project --schema public --schema storage
+  supactl db diff local/my-project production/my-project -o promote.sql`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		from := introspectInstance(args[0])
+		to := introspectInstance(args[1])
+
+		diff := pgschema.Compare(from, to)
+		if diff.Empty() {
+			fmt.Printf("No differences in schema(s) %s.\n", strings.Join(dbDiffSchemas, ", "))
+			return
+		}
+
+		diff.WriteText(os.Stdout)
+		fmt.Println()
+
+		header := fmt.Sprintf("-- Schema diff from %s to %s (schemas: %s)\n\n", args[0], args[1], strings.Join(dbDiffSchemas, ", "))
+		script := header + diff.SQL()
+
+		if dbDiffOutput != "" {
+			if err := os.WriteFile(dbDiffOutput, []byte(script), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to write migration: %v\n", err)
+				exit(1)
+			}
+			fmt.Printf("Migration written to %s\n", dbDiffOutput)
+			return
+		}
+
+		name := fmt.Sprintf("diff_%s_%s", refSlug(args[0]), refSlug(args[1]))
+		m, err := migrate.Create(migrationsDir(), name, script, "", time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Migration written to %s\n", m.UpPath)
+	},
+}
+
+// introspectInstance reads the selected schemas of an instance reference
+func introspectInstance(ref string) *pgschema.Schema {
+	conn := openDatabase(ref)
+	defer conn.Close()
+
+	schema, err := pgschema.Introspect(conn, dbDiffSchemas)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to read schema of '%s': %v\n", ref, err)
+		exit(1)
+	}
+	return schema
+}
+
+// refSlug turns an instance reference into a file name fragment
+func refSlug(ref string) string {
+	return regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(ref), "_")
+}
+
+func init() {
+	dbCmd.AddCommand(dbDiffCmd)
+	dbDiffCmd.Flags().StringSliceVar(&dbDiffSchemas, "schema", []string{"public"}, "Schema to compare (repeatable)")
+	dbDiffCmd.Flags().StringVarP(&dbDiffOutput, "output", "o", "", "File to write the migration SQL to")
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/qubitquilt/supactl/internal/database"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbExecCommand string
+	dbExecFile    string
+	dbExecOutput  string
+)
+
+var dbExecCmd = &cobra.Command{
+	Use:   "exec <instance-name> (-c <sql> | -f <file>)",
+	Short: "Run SQL against an instance's database",
+	Long: `Run SQL against an instance's database and print the results.
+
+Statements are given with -c or read from a file with -f (- for stdin). When
+several statements are given, each one's result is printed. Results are shown
+as tables, or as JSON with -o json.
+
+Examples:
+  supactl db exec my-project -c "select id, email from auth.users"
+  supactl db exec my-project -f seed.sql
+  supactl db exec my-project -c "select * from todos" -o json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		instanceName := strings.TrimSpace(args[0])
+
+		if (dbExecCommand == "") == (dbExecFile == "") {
+			fmt.Fprintf(os.Stderr, "Error: Specify exactly one of -c or -f\n")
+			exit(1)
+		}
+		if dbExecOutput != "table" && dbExecOutput != "json" {
+			fmt.Fprintf(os.Stderr, "Error: Invalid output format '%s' (expected table or json)\n", dbExecOutput)
+			exit(1)
+		}
+
+		query := dbExecCommand
+		if dbExecFile != "" {
+			var data []byte
+			var err error
+			if dbExecFile == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(dbExecFile)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to read SQL file: %v\n", err)
+				exit(1)
+			}
+			query = string(data)
+		}
+
+		conn := openDatabase(instanceName)
+		defer conn.Close()
+
+		results, err := database.Query(conn, query)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		if dbExecOutput == "json" {
+			err = database.WriteJSON(os.Stdout, results)
+		} else {
+			err = database.WriteTable(os.Stdout, results)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	dbCmd.AddCommand(dbExecCmd)
+	dbExecCmd.Flags().StringVarP(&dbExecCommand, "command", "c", "", "SQL to run")
+	dbExecCmd.Flags().StringVarP(&dbExecFile, "file", "f", "", "File of SQL statements to run (- for stdin)")
+	dbExecCmd.Flags().StringVarP(&dbExecOutput, "output", "o", "table", "Output format: table or json")
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/migrate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateDryRun bool
+	migrateSteps  int
+)
+
+// dbMigrateCmd groups the migration commands
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Run SQL migrations from the linked directory",
+	Long: `Run SQL migrations against the linked instance.
+
+Migrations are read from supabase/migrations in the current (linked) directory,
+or from the paths.migrations of supactl.yaml.
+Each migration is a file named <timestamp>_<name>.sql with an optional
+<timestamp>_<name>.down.sql to revert it. Applied versions are recorded in
+supabase_migrations.schema_migrations on the instance (the same table the
+Supabase CLI uses), and every migration runs in its own transaction.
+
+Run 'supactl link' first to choose the target instance.
+
+Examples:
+  supactl db migrate new create_todos
+  supactl db migrate status
+  supactl db migrate up --dry-run
+  supactl db migrate up
+  supactl db migrate down --steps 2`,
+}
+
+var dbMigrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply all pending migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		instanceName, migrations := loadLinkedMigrations()
+
+		conn := openDatabase(instanceName)
+		defer conn.Close()
+
+		count, err := migrate.Up(migrate.NewSQLStore(conn), migrations, migrateDryRun, os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		switch {
+		case count == 0:
+			fmt.Println("No pending migrations.")
+		case migrateDryRun:
+			fmt.Printf("%d pending migration(s) would be applied to '%s'.\n", count, instanceName)
+		default:
+			fmt.Printf("Applied %d migration(s) to '%s'.\n", count, instanceName)
+		}
+	},
+}
+
+var dbMigrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Revert the most recently applied migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if migrateSteps < 1 {
+			fmt.Fprintf(os.Stderr, "Error: --steps must be at least 1\n")
+			exit(1)
+		}
+
+		instanceName, migrations := loadLinkedMigrations()
+
+		conn := openDatabase(instanceName)
+		defer conn.Close()
+
+		count, err := migrate.Down(migrate.NewSQLStore(conn), migrations, migrateSteps, migrateDryRun, os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		switch {
+		case count == 0:
+			fmt.Println("No applied migrations to revert.")
+		case migrateDryRun:
+			fmt.Printf("%d migration(s) would be reverted on '%s'.\n", count, instanceName)
+		default:
+			fmt.Printf("Reverted %d migration(s) on '%s'.\n", count, instanceName)
+		}
+	},
+}
+
+var dbMigrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which migrations have been applied",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		instanceName, migrations := loadLinkedMigrations()
+
+		conn := openDatabase(instanceName)
+		defer conn.Close()
+
+		applied, err := migrate.NewSQLStore(conn).Applied()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		entries := migrate.Status(migrations, applied)
+		if len(entries) == 0 {
+			fmt.Println("No migrations found.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+		for _, entry := range entries {
+			status := "pending"
+			switch {
+			case entry.Missing:
+				status = "applied (no local file)"
+			case entry.Applied:
+				status = "applied"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Version, entry.Name, status)
+		}
+		w.Flush()
+	},
+}
+
+var dbMigrateNewCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a new empty migration",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		m, err := migrate.New(migrationsDir(), args[0], time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("Created %s\n", m.UpPath)
+		fmt.Printf("Created %s\n", m.DownPath)
+	},
+}
+
+// loadLinkedMigrations returns the linked instance reference and the migrations of the current directory
+func loadLinkedMigrations() (string, []migrate.Migration) {
+	ref := instanceArg(nil)
+
+	migrations, err := migrate.LoadDir(migrationsDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+
+	return ref, migrations
+}
+
+func init() {
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbMigrateCmd.AddCommand(dbMigrateUpCmd, dbMigrateDownCmd, dbMigrateStatusCmd, dbMigrateNewCmd)
+
+	for _, c := range []*cobra.Command{dbMigrateUpCmd, dbMigrateDownCmd} {
+		c.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Print the SQL without running it")
+	}
+	dbMigrateDownCmd.Flags().IntVar(&migrateSteps, "steps", 1, "Number of migrations to revert")
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/qubitquilt/supactl/internal/database"
+	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/qubitquilt/supactl/internal/migrate"
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// defaultSeedFile is loaded after a reset when no --seed is given
+var defaultSeedFile = filepath.Join("supabase", "seed.sql")
+
+var (
+	resetSeeds   []string
+	resetNoSeed  bool
+	resetForce   bool
+	resetConfirm string
+)
+
+// dbResetCmd drops and recreates an instance's application schemas
+var dbResetCmd = &cobra.Command{
+	Use:   "reset <instance>",
+	Short: "Reset an instance's database to a clean state",
+	Long: `Reset an instance's database to a clean state.
+
+The reset:
+  1. Stops the running services that connect to the database (local instances only)
+  2. Drops every application schema, keeping the Supabase system schemas
+     (auth, storage, realtime, ...) intact, and recreates an empty public schema
+  3. Applies the migrations in supabase/migrations (or paths.migrations of supactl.yaml)
+  4. Loads the seed files (paths.seed of supactl.yaml, or supabase/seed.sql if it exists)
+  5. Starts the stopped services again
+
+All data in the application schemas is lost. Remote instances are only reset
+when both --force and --confirm <instance-name> are given.
+
+Examples:
+  supactl db reset my-project
+  supactl db reset my-project --seed supabase/seed.sql --seed fixtures.sql
+  supactl db reset production/my-project --force --confirm my-project`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, instanceName := resolveInstanceRef(args[0])
+
+		instance, err := p.GetInstance(instanceName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to get instance details: %v\n", err)
+			exit(1)
+		}
+
+		isLocal := p.ProviderType() == provider.ProviderTypeLocal && instance.Directory != ""
+		if !isLocal && (!resetForce || resetConfirm != instanceName) {
+			fmt.Fprintf(os.Stderr, "Error: '%s' is a remote instance. Resetting it deletes its data; pass --force --confirm %s to continue\n", instanceName, instanceName)
+			exit(1)
+		}
+
+		migrations, err := loadResetMigrations()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		seeds, err := resolveSeedFiles()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		info, err := database.ForInstance(instance)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		var stopped []string
+		if isLocal {
+			stopped, err = runningDatabaseClients(getServiceProvider(p, "db reset"), instanceName, instance.Directory)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+			if len(stopped) > 0 {
+				fmt.Printf("Stopping %s...\n", strings.Join(stopped, ", "))
+				if err := getServiceProvider(p, "db reset").StopServices(instanceName, stopped); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					exit(1)
+				}
+			}
+		}
+
+		resetErr := resetDatabase(info, migrations, seeds)
+
+		// Bring the services back even if the reset failed; only those that were running were stopped
+		if len(stopped) > 0 {
+			fmt.Printf("Starting %s...\n", strings.Join(stopped, ", "))
+			if err := getServiceProvider(p, "db reset").StartServices(instanceName, stopped); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+		}
+
+		if resetErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", resetErr)
+			exit(1)
+		}
+
+		fmt.Printf("Database of '%s' has been reset.\n", instanceName)
+	},
+}
+
+// runningDatabaseClients returns the services of a local instance that connect to the
+// database and are running, which a reset stops and starts again
+func runningDatabaseClients(sp provider.ServiceProvider, instanceName, directory string) ([]string, error) {
+	clients, err := local.DatabaseClientServices(directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read services: %w", err)
+	}
+
+	running, err := sp.RunningServices(instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list running services: %w", err)
+	}
+	isRunning := make(map[string]bool, len(running))
+	for _, service := range running {
+		isRunning[service] = true
+	}
+
+	var services []string
+	for _, service := range clients {
+		if isRunning[service] {
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
+
+// resetDatabase drops the application schemas, then applies migrations and seed files
+func resetDatabase(info *database.ConnInfo, migrations []migrate.Migration, seeds []string) error {
+	conn, err := database.Open(info)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	dropped, err := database.Reset(conn)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Dropped schemas: %s\n", strings.Join(dropped, ", "))
+
+	if len(migrations) > 0 {
+		count, err := migrate.Up(migrate.NewSQLStore(conn), migrations, false, os.Stdout)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s).\n", count)
+	}
+
+	for _, seed := range seeds {
+		script, err := os.ReadFile(seed)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file: %w", err)
+		}
+		if err := database.ExecFile(conn, string(script)); err != nil {
+			return fmt.Errorf("failed to load seed file %s: %w", seed, err)
+		}
+		fmt.Printf("Loaded seed file %s\n", seed)
+	}
+
+	return nil
+}
+
+// loadResetMigrations returns the migrations of the current directory, if it has any
+func loadResetMigrations() ([]migrate.Migration, error) {
+	if _, err := os.Stat(migrationsDir()); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return migrate.LoadDir(migrationsDir())
+}
+
+// resolveSeedFiles returns the seed files to load, checking that they exist
+func resolveSeedFiles() ([]string, error) {
+	if resetNoSeed {
+		return nil, nil
+	}
+
+	seeds := resetSeeds
+	if len(seeds) == 0 {
+		project := getProjectFile()
+		if project == nil || len(project.Paths.Seed) == 0 {
+			seed := defaultSeedFile
+			if project != nil {
+				seed = project.SeedFiles()[0]
+			}
+			if _, err := os.Stat(seed); err == nil {
+				return []string{seed}, nil
+			}
+			return nil, nil
+		}
+		seeds = project.SeedFiles()
+	}
+
+	for _, seed := range seeds {
+		if _, err := os.Stat(seed); err != nil {
+			return nil, fmt.Errorf("seed file not found: %s", seed)
+		}
+	}
+	return seeds, nil
+}
+
+func init() {
+	dbCmd.AddCommand(dbResetCmd)
+	dbResetCmd.Flags().StringSliceVar(&resetSeeds, "seed", nil, "SQL file to load after migrations (repeatable, default supabase/seed.sql)")
+	dbResetCmd.Flags().BoolVar(&resetNoSeed, "no-seed", false, "Do not load any seed files")
+	dbResetCmd.Flags().BoolVar(&resetForce, "force", false, "Allow resetting a remote instance")
+	dbResetCmd.Flags().StringVar(&resetConfirm, "confirm", "", "Name of the remote instance being reset, required with --force")
+	dbResetCmd.MarkFlagsMutuallyExclusive("seed", "no-seed")
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/qubitquilt/supactl/internal/database"
+	"github.com/spf13/cobra"
+)
+
+var dbShellCmd = &cobra.Command{
+	Use:   "shell <instance-name> [-- psql-args...]",
+	Short: "Open a psql session on an instance's database",
+	Long: `Open an interactive psql session on an instance's database.
+
+For local instances the host psql is used if it is installed; otherwise psql
+is run inside the instance's db container with 'docker exec'. Remote instances
+require psql on the host.
+
+Arguments after -- are passed to psql.
+
+Examples:
+  supactl db shell my-project
+  supactl db shell my-project -- -c "\dt"
+  supactl psql my-project`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		instanceName := strings.TrimSpace(args[0])
+		info := getConnInfo(instanceName)
+
+		psql := psqlCommand(info, args[1:])
+		if psql == nil {
+			fmt.Fprintf(os.Stderr, "Error: psql is not installed. Install the PostgreSQL client to connect to remote instances.\n")
+			exit(1)
+		}
+
+		if err := commandExecutor.RunInteractive(psql.env, psql.name, psql.args...); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exit(exitErr.ExitCode())
+			}
+			fmt.Fprintf(os.Stderr, "Error: Failed to run psql: %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+// psqlCmd is a top-level shortcut for 'db shell'
+var psqlCmd = &cobra.Command{
+	Use:   "psql <instance-name> [-- psql-args...]",
+	Short: "Open a psql session on an instance's database (alias for 'db shell')",
+	Long:  dbShellCmd.Long,
+	Args:  cobra.MinimumNArgs(1),
+	Run:   dbShellCmd.Run,
+}
+
+// psqlInvocation is a psql command line and the environment it needs
+type psqlInvocation struct {
+	name string
+	args []string
+	env  []string
+}
+
+// psqlCommand builds the psql invocation for a connection: the host psql if available,
+// otherwise docker exec into the db container of a local instance. It returns nil if
+// neither is possible. Credentials are passed through the environment, not the command line.
+func psqlCommand(info *database.ConnInfo, extraArgs []string) *psqlInvocation {
+	if path, err := exec.LookPath("psql"); err == nil {
+		env := []string{
+			"PGHOST=" + info.Host,
+			"PGPORT=" + strconv.Itoa(info.Port),
+			"PGUSER=" + info.User,
+			"PGPASSWORD=" + info.Password,
+			"PGDATABASE=" + info.Database,
+		}
+		if info.SSLMode != "" {
+			env = append(env, "PGSSLMODE="+info.SSLMode)
+		}
+		return &psqlInvocation{name: path, args: extraArgs, env: env}
+	}
+
+	if info.Container == "" {
+		return nil
+	}
+
+	// Inside the container psql connects to Postgres directly, bypassing the pooler,
+	// so the plain postgres user is used
+	dockerArgs := []string{"exec", "-i"}
+	if stdinIsTerminal() {
+		dockerArgs = append(dockerArgs, "-t")
+	}
+	dockerArgs = append(dockerArgs,
+		"-e", "PGPASSWORD",
+		info.Container,
+		"psql", "-h", "localhost", "-U", "postgres", "-d", info.Database,
+	)
+	return &psqlInvocation{
+		name: "docker",
+		args: append(dockerArgs, extraArgs...),
+		env:  []string{"PGPASSWORD=" + info.Password},
+	}
+}
+
+// stdinIsTerminal reports whether standard input is an interactive terminal
+func stdinIsTerminal() bool {
+	stat, err := os.Stdin.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	dbCmd.AddCommand(dbShellCmd)
+	rootCmd.AddCommand(psqlCmd)
+}
@@ -5,7 +5,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 )
 
@@ -19,26 +18,17 @@ WARNING: This action may be irreversible depending on your provider.
 For remote instances, all data will be permanently deleted.
 For local instances, only the database entry is removed (files remain).
 
-You will be asked to confirm before the deletion proceeds.`,
-	Args: cobra.ExactArgs(1),
+You will be asked to confirm before the deletion proceeds, unless --yes is given.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeInstances,
 	Run: func(cmd *cobra.Command, args []string) {
 		instanceName := strings.TrimSpace(args[0])
 		provider := getProvider()
 
 		// Ask for confirmation
-		var confirmed bool
-		prompt := &survey.Confirm{
-			Message: fmt.Sprintf("Are you sure you want to delete '%s'?", instanceName),
-			Default: false,
-		}
-
-		if err := survey.AskOne(prompt, &confirmed); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-
-		if !confirmed {
+		if !confirm(fmt.Sprintf("Are you sure you want to delete '%s'?", instanceName)) {
 			fmt.Println("Deletion cancelled.")
+			cancelAudit()
 			return
 		}
 
@@ -46,8 +36,9 @@ You will be asked to confirm before the deletion proceeds.`,
 
 		if err := provider.DeleteInstance(instanceName); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to delete instance: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
+		invalidateInstanceNames(currentContextName())
 
 		fmt.Printf("Successfully deleted instance '%s'\n", instanceName)
 	},
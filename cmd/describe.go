@@ -20,14 +20,15 @@ Works with both remote and local instances based on your current context.
 
 Examples:
   supactl describe instance my-project`,
-	Args: cobra.MinimumNArgs(2),
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeDescribe,
 	Run: func(cmd *cobra.Command, args []string) {
 		resourceType := args[0]
 		instanceName := strings.TrimSpace(args[1])
 
 		if resourceType != "instance" {
 			fmt.Fprintf(os.Stderr, "Error: Unknown resource type '%s'. Only 'instance' is supported.\n", resourceType)
-			os.Exit(1)
+			exit(1)
 		}
 
 		provider := getProvider()
@@ -36,7 +37,7 @@ Examples:
 		instance, err := provider.GetInstance(instanceName)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to get instance: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Display instance information in kubectl describe style
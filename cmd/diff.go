@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// diffExitDrift is the exit code of 'supactl diff' when instances differ from the manifest.
+// Errors exit with 1, so scripts can tell drift and failures apart.
+const diffExitDrift = 2
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff -f <file>",
+	Short: "Show what 'apply' would change",
+	Long: `Compare the instances of the current context with a manifest file and print
+the changes 'supactl apply' would make, without making them.
+
+Exit codes:
+  0  instances match the manifest
+  1  an error occurred
+  2  drift detected
+
+Examples:
+  supactl diff -f instances.yaml
+  supactl diff -f instances.yaml --prune`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, plan := planManifest()
+
+		writePlan(plan)
+		if plan.HasChanges() {
+			exit(diffExitDrift)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	addManifestFlags(diffCmd)
+}
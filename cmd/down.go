@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+var downVolumes bool
+
+// downCmd removes the containers of an instance
+var downCmd = &cobra.Command{
+	Use:   "down [instance-name]",
+	Short: "Stop a local instance and remove its containers",
+	Long: `Stop a local Supabase instance and remove its containers and networks.
+
+Unlike 'supactl stop', which only stops the containers, down removes them; they
+are recreated by the next 'supactl start'. The instance's data lives in Docker
+volumes and is kept unless --volumes is given, which deletes the database and
+storage volumes after asking for confirmation (--yes skips it).
+
+Without an instance name, the instance linked to the current directory is used.
+
+Examples:
+  supactl down my-project
+  supactl down my-project --volumes`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstances,
+	Run: func(cmd *cobra.Command, args []string) {
+		p, instanceName := resolveInstanceRef(instanceArg(args))
+		sp := getServiceProvider(p, "down")
+
+		if downVolumes && !confirmVolumeRemoval(instanceName, "Down") {
+			return
+		}
+
+		fmt.Printf("Removing the containers of '%s'...\n", instanceName)
+
+		if err := sp.DownInstance(instanceName, downVolumes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to take down instance: %v\n", err)
+			exit(1)
+		}
+
+		if downVolumes {
+			fmt.Printf("Removed the containers and volumes of '%s'\n", instanceName)
+		} else {
+			fmt.Printf("Removed the containers of '%s'; its data was kept\n", instanceName)
+		}
+	},
+}
+
+// confirmVolumeRemoval asks before deleting an instance's data volumes. If the user declines,
+// it reports that the command (verb, e.g. "Down") was cancelled and records that in the audit trail.
+func confirmVolumeRemoval(name, verb string) bool {
+	if confirm(fmt.Sprintf("Remove the volumes of '%s'? Its database and storage data will be permanently deleted.", name)) {
+		return true
+	}
+	fmt.Printf("%s cancelled.\n", verb)
+	cancelAudit()
+	return false
+}
+
+// getServiceProvider returns p as a provider that can act on individual services, for the
+// command or flag named by what
+func getServiceProvider(p provider.InstanceProvider, what string) provider.ServiceProvider {
+	sp, ok := p.(provider.ServiceProvider)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: the %s provider does not support %s\n", p.ProviderType(), what)
+		exit(1)
+	}
+	return sp
+}
+
+func init() {
+	rootCmd.AddCommand(downCmd)
+	downCmd.Flags().BoolVar(&downVolumes, "volumes", false, "Also remove the instance's volumes, deleting its data")
+}
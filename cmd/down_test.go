@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/audit"
+	"github.com/qubitquilt/supactl/internal/prompt"
+)
+
+func TestConfirmVolumeRemoval(t *testing.T) {
+	scripted := &prompt.Scripted{Answers: []interface{}{true, false}}
+	withPrompter(t, scripted)
+	old := pendingAudit
+	t.Cleanup(func() { pendingAudit = old })
+	pendingAudit = &audit.Record{Result: audit.ResultSuccess}
+
+	if !confirmVolumeRemoval("app", "Down") {
+		t.Error("confirmVolumeRemoval should report true when confirmed")
+	}
+	if pendingAudit.Result != audit.ResultSuccess {
+		t.Errorf("audit result = %q after confirming", pendingAudit.Result)
+	}
+
+	if confirmVolumeRemoval("app", "Down") {
+		t.Error("confirmVolumeRemoval should report false when declined")
+	}
+	if pendingAudit.Result != audit.ResultCancelled {
+		t.Errorf("audit result = %q, want cancelled", pendingAudit.Result)
+	}
+	if len(scripted.Asked) != 2 {
+		t.Errorf("expected two prompts, got %v", scripted.Asked)
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/qubitquilt/supactl/internal/functions"
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+var functionsForce bool
+
+// functionsCmd groups the edge function commands
+var functionsCmd = &cobra.Command{
+	Use:   "functions",
+	Short: "Manage edge functions",
+	Long: `Create edge functions in the current directory and deploy them to instances.
+
+Functions live in supabase/functions/<name>, with an index.ts entrypoint.
+Directories starting with "_" (such as _shared) hold shared code and are
+deployed alongside every function.
+
+Local instances get the functions copied into the project's
+volumes/functions directory, after which only the functions service is
+restarted. Remote instances receive them through the SupaControl server.
+
+Examples:
+  supactl functions new hello-world
+  supactl functions deploy my-project
+  supactl functions deploy my-project hello-world
+  supactl functions list my-project
+  supactl functions delete my-project hello-world`,
+}
+
+var functionsNewCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a new function in supabase/functions",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := functions.New(functionsDir(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("Created %s\n", path)
+	},
+}
+
+var functionsDeployCmd = &cobra.Command{
+	Use:   "deploy <instance> [function...]",
+	Short: "Deploy functions to an instance",
+	Long: `Deploy the functions in supabase/functions of the current directory to an
+instance. Without function names, every function is deployed. Deployed
+functions replace their previous versions.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		sources, err := functions.Load(functionsDir(), args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		fp, instanceName := getFunctionsProvider(args[0])
+
+		if err := fp.DeployFunctions(instanceName, sources); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to deploy functions: %v\n", err)
+			exit(1)
+		}
+
+		for _, source := range sources {
+			fmt.Printf("Deployed function '%s'\n", source.Name)
+		}
+	},
+}
+
+var functionsListCmd = &cobra.Command{
+	Use:   "list <instance>",
+	Short: "List the functions deployed to an instance",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fp, instanceName := getFunctionsProvider(args[0])
+
+		infos, err := fp.ListFunctions(instanceName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to list functions: %v\n", err)
+			exit(1)
+		}
+
+		if len(infos) == 0 {
+			fmt.Println("No functions deployed.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tUPDATED")
+		for _, info := range infos {
+			updated := "-"
+			if !info.UpdatedAt.IsZero() {
+				updated = info.UpdatedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\n", info.Name, updated)
+		}
+		w.Flush()
+	},
+}
+
+var functionsDeleteCmd = &cobra.Command{
+	Use:   "delete <instance> <function>",
+	Short: "Delete a function from an instance",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		fp, instanceName := getFunctionsProvider(args[0])
+		function := args[1]
+
+		if !functionsForce {
+			if !confirm(fmt.Sprintf("Are you sure you want to delete function '%s' from '%s'?", function, instanceName)) {
+				fmt.Println("Deletion cancelled.")
+				cancelAudit()
+				return
+			}
+		}
+
+		if err := fp.DeleteFunction(instanceName, function); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to delete function: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("Deleted function '%s'\n", function)
+	},
+}
+
+// getFunctionsProvider resolves an instance reference to a provider that can deploy functions
+func getFunctionsProvider(ref string) (provider.FunctionsProvider, string) {
+	p, instanceName := resolveInstanceRef(ref)
+
+	fp, ok := p.(provider.FunctionsProvider)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: the %s provider does not support edge functions\n", p.ProviderType())
+		exit(1)
+	}
+
+	return fp, instanceName
+}
+
+func init() {
+	rootCmd.AddCommand(functionsCmd)
+	functionsCmd.AddCommand(functionsNewCmd, functionsDeployCmd, functionsListCmd, functionsDeleteCmd)
+
+	functionsDeleteCmd.Flags().BoolVar(&functionsForce, "force", false, "Delete without asking for confirmation")
+}
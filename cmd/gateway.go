@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/qubitquilt/supactl/internal/provider"
+)
+
+// getGatewayAccess returns an instance and its service role key for calls through its API gateway
+func getGatewayAccess(ref string) (*provider.Instance, string) {
+	p, instanceName := resolveInstanceRef(ref)
+
+	instance, err := p.GetInstance(instanceName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to get instance details: %v\n", err)
+		exit(1)
+	}
+
+	key, err := serviceRoleKey(instance)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+
+	return instance, key
+}
+
+// serviceRoleKey returns the service role key reported for an instance,
+// falling back to the local project's .env file
+func serviceRoleKey(instance *provider.Instance) (string, error) {
+	if instance.ServiceKey != "" {
+		return instance.ServiceKey, nil
+	}
+	if instance.Directory != "" {
+		return local.GetServiceRoleKey(instance.Directory)
+	}
+	return "", fmt.Errorf("no service role key is available for instance '%s'", instance.Name)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qubitquilt/supactl/internal/pgschema"
+	"github.com/qubitquilt/supactl/internal/typegen"
+	"github.com/spf13/cobra"
+)
+
+var (
+	genLang    string
+	genSchemas []string
+	genPackage string
+)
+
+// genCmd groups code generation commands
+var genCmd = &cobra.Command{
+	Use:   "gen",
+	Short: "Generate code from an instance",
+}
+
+// genTypesCmd generates TypeScript or Go types from an instance's database schema
+var genTypesCmd = &cobra.Command{
+	Use:   "types <instance>",
+	Short: "Generate TypeScript or Go types from an instance's database schema",
+	Long: `Generate TypeScript or Go types for the tables, views, enums and functions
+of an instance's database. The types are written to standard output.
+
+TypeScript output is a Database type in the shape supabase-js expects
+(createClient<Database>). Go output has a struct per table row, insert and
+update, a struct per view row, and typed string constants per enum.
+
+Use <context>/<instance> to read the schema of an instance in another context.
+
+Examples:
+  supactl gen types my-project > types.ts
+  supactl gen types my-project --schema public --schema billing > types.ts
+  supactl gen types production/my-project --lang go --package db > db/types.go`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		conn := openDatabase(args[0])
+		defer conn.Close()
+
+		schema, err := pgschema.Introspect(conn, genSchemas)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to read schema: %v\n", err)
+			exit(1)
+		}
+
+		opts := typegen.Options{Lang: genLang, Schemas: genSchemas, Package: genPackage}
+		if err := typegen.Generate(os.Stdout, schema, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(genCmd)
+	genCmd.AddCommand(genTypesCmd)
+	genTypesCmd.Flags().StringVar(&genLang, "lang", typegen.LangTypeScript, "Output language (typescript or go)")
+	genTypesCmd.Flags().StringSliceVar(&genSchemas, "schema", []string{"public"}, "Schema to generate types for (repeatable)")
+	genTypesCmd.Flags().StringVar(&genPackage, "package", "database", "Package name of the generated Go code")
+}
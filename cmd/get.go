@@ -3,11 +3,21 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"text/tabwriter"
+	"time"
 
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/provider"
 	"github.com/spf13/cobra"
 )
 
+var (
+	getContexts    []string
+	getAllContexts bool
+	getTimeout     time.Duration
+)
+
 // getCmd represents the get command (kubectl-style)
 var getCmd = &cobra.Command{
 	Use:   "get instances",
@@ -17,13 +27,26 @@ var getCmd = &cobra.Command{
 This command provides a kubectl-style interface for listing instances.
 Works with both remote and local instances based on your current context.
 
+Use --all-contexts or --context to list the instances of several contexts at
+once. Contexts are queried concurrently; a context that fails or does not
+answer within --timeout is shown as an error row instead of aborting the
+listing.
+
 Examples:
-  supactl get instances`,
-	Args: cobra.ExactArgs(1),
+  supactl get instances
+  supactl get instances --all-contexts
+  supactl get instances --context local,production`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeGet,
 	Run: func(cmd *cobra.Command, args []string) {
 		if args[0] != "instances" {
 			fmt.Fprintf(os.Stderr, "Error: Unknown resource type '%s'. Only 'instances' is supported.\n", args[0])
-			os.Exit(1)
+			exit(1)
+		}
+
+		if getAllContexts || len(getContexts) > 0 {
+			listInstancesAcrossContexts()
+			return
 		}
 
 		provider := getProvider()
@@ -31,7 +54,7 @@ Examples:
 		instances, err := provider.ListInstances()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to list instances: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if len(instances) == 0 {
@@ -55,6 +78,70 @@ Examples:
 	},
 }
 
+// listInstancesAcrossContexts prints the instances of the selected contexts with a CONTEXT column
+func listInstancesAcrossContexts() {
+	if getAllContexts && len(getContexts) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: --all-contexts and --context cannot be used together\n")
+		exit(1)
+	}
+
+	config, err := auth.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to load configuration: %v\n", err)
+		exit(1)
+	}
+
+	contexts := getContexts
+	if getAllContexts {
+		contexts = config.ListContexts()
+		sort.Strings(contexts)
+	}
+	for _, name := range contexts {
+		if _, exists := config.Contexts[name]; !exists {
+			fmt.Fprintf(os.Stderr, "Error: Context '%s' does not exist\n", name)
+			exit(1)
+		}
+	}
+
+	// Providers are opened concurrently, so supactl.yaml is read up front
+	version := supabaseVersion()
+	open := func(name string) (provider.InstanceProvider, error) {
+		return newProviderWithExecutor(name, config.Contexts[name], commandExecutor, version)
+	}
+	results := provider.ListAcrossContexts(contexts, open, getTimeout)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tNAME\tSTATUS\tSTUDIO-URL")
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Fprintf(w, "%s\t-\terror\t%v\n", result.Context, result.Err)
+			continue
+		}
+
+		for _, instance := range result.Instances {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				result.Context,
+				instance.Name,
+				instance.Status,
+				instance.StudioURL,
+			)
+		}
+	}
+
+	w.Flush()
+
+	// Partial results are still useful; only fail if no context could be listed
+	if failed > 0 && failed == len(results) {
+		exit(1)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(getCmd)
+	getCmd.Flags().StringSliceVar(&getContexts, "context", nil, "Comma-separated contexts to list instances from")
+	getCmd.Flags().BoolVar(&getAllContexts, "all-contexts", false, "List instances from every configured context")
+	getCmd.Flags().DurationVar(&getTimeout, "timeout", 10*time.Second, "Per-context timeout when listing several contexts")
 }
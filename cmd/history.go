@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/audit"
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyInstance string
+	historyContext  string
+	historySince    string
+	historyLimit    int
+	historyServer   bool
+	historyOutput   string
+)
+
+// historyCmd queries the audit log
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the audit log of mutating operations",
+	Long: `Show who ran which mutating command, against what, and how it ended.
+
+Every mutating command (create, delete, start, stop, local remove, db reset,
+functions deploy, ...) and every action taken in 'supactl ui' appends a record
+to ~/.supacontrol/audit.jsonl with the time, OS user, context, provider,
+instance, action, result and duration.
+
+Use --server to read the audit trail kept by the SupaControl server of a
+remote context instead, which includes operations run from other machines.
+
+--since takes a duration (90m, 24h, 7d), a date (2026-01-02) or an RFC 3339
+timestamp.
+
+Examples:
+  supactl history
+  supactl history --instance my-project --since 7d
+  supactl history --server --context production -o json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if historyOutput != "table" && historyOutput != "json" {
+			fmt.Fprintf(os.Stderr, "Error: Unknown output format '%s' (use table or json)\n", historyOutput)
+			exit(1)
+		}
+
+		filter := audit.Filter{Instance: historyInstance, Context: historyContext, Limit: historyLimit}
+		if historySince != "" {
+			since, err := audit.ParseSince(historySince, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --since: %v\n", err)
+				exit(1)
+			}
+			filter.Since = since
+		}
+
+		var records []audit.Record
+		if historyServer {
+			records = serverHistory(filter)
+		} else {
+			path, err := audit.GetAuditPath()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+			records, err = audit.Read(path, filter)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+		}
+
+		if historyOutput == "json" {
+			if records == nil {
+				records = []audit.Record{}
+			}
+			writeJSON(records)
+			return
+		}
+
+		if len(records) == 0 {
+			fmt.Println("No operations recorded.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "TIME\tUSER\tCONTEXT\tINSTANCE\tACTION\tRESULT\tDURATION")
+		for _, r := range records {
+			result := r.Result
+			if r.Error != "" {
+				result = fmt.Sprintf("%s (%s)", r.Result, r.Error)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.Time.Local().Format("2006-01-02 15:04:05"),
+				orDash(r.User),
+				orDash(r.Context),
+				orDash(r.Instance),
+				r.Action,
+				result,
+				r.Duration().Round(time.Millisecond),
+			)
+		}
+		w.Flush()
+	},
+}
+
+// serverHistory reads the audit trail of the SupaControl server of --context, or the current context
+func serverHistory(filter audit.Filter) []audit.Record {
+	config, err := auth.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to load configuration: %v\n", err)
+		exit(1)
+	}
+	applyProjectContext(config)
+
+	contextName := filter.Context
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+	ctx, exists := config.Contexts[contextName]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "Error: Context '%s' does not exist\n", contextName)
+		exit(1)
+	}
+
+	p, err := newProvider(contextName, ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Context '%s': %v\n", contextName, err)
+		exit(1)
+	}
+	auditProvider, ok := p.(provider.AuditProvider)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Context '%s' has no server-side audit trail; omit --server to read the local log\n", contextName)
+		exit(1)
+	}
+
+	// The server does not know context names
+	filter.Context = ""
+	records, err := auditProvider.ListAuditEvents(filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to fetch the audit trail: %v\n", err)
+		exit(1)
+	}
+	for i := range records {
+		records[i].Context = contextName
+	}
+
+	return records
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVar(&historyInstance, "instance", "", "Only show operations on this instance")
+	historyCmd.Flags().StringVar(&historyContext, "context", "", "Only show operations in this context (with --server: the context to query)")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Only show operations after this time (e.g. 24h, 7d, 2026-01-02)")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 50, "Show at most this many of the most recent operations (0 for all)")
+	historyCmd.Flags().BoolVar(&historyServer, "server", false, "Read the audit trail of the SupaControl server instead of the local log")
+	historyCmd.Flags().StringVarP(&historyOutput, "output", "o", "table", "Output format: table or json")
+}
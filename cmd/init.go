@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/link"
+	"github.com/qubitquilt/supactl/internal/projectfile"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initContext         string
+	initInstance        string
+	initSupabaseVersion string
+	initForce           bool
+)
+
+// initCmd scaffolds a supactl.yaml in the current directory
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a supactl.yaml with per-repository defaults",
+	Long: `Create a supactl.yaml in the current directory.
+
+The file declares defaults for everything run inside the repository: the
+context, the instance used when a command's instance argument is omitted, the
+Supabase version for new local instances, the migrations, functions and seed
+paths, and env overrides applied when the instance is created or started.
+Commands find it by walking up from the current directory, like git.
+
+Precedence: command-line flags and arguments, then the directory link and
+supactl.yaml, then the global configuration.
+
+Without flags, the context defaults to the current one and the instance to
+the linked instance, if any.
+
+Examples:
+  supactl init
+  supactl init --context local --instance my-app --supabase-version master`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c := &projectfile.Config{
+			Context:         initContext,
+			Instance:        initInstance,
+			SupabaseVersion: initSupabaseVersion,
+		}
+
+		if c.Context == "" {
+			if config, err := auth.LoadConfig(); err == nil {
+				c.Context = config.CurrentContext
+			}
+		}
+		if c.Instance == "" {
+			if l, err := link.Load(); err == nil && (l.Context == "" || l.Context == c.Context) {
+				c.Instance = l.Instance
+			}
+		}
+
+		path, err := projectfile.Scaffold(".", c, initForce)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("Created %s\n", path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().StringVar(&initContext, "context", "", "Context for commands in this repository (default: the current context)")
+	initCmd.Flags().StringVar(&initInstance, "instance", "", "Default instance (default: the linked instance)")
+	initCmd.Flags().StringVar(&initSupabaseVersion, "supabase-version", "", "Supabase branch or tag for new local instances")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite an existing supactl.yaml")
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// instanceCmd groups commands that operate on a single instance's settings
+var instanceCmd = &cobra.Command{
+	Use:   "instance",
+	Short: "Manage settings of a Supabase instance",
+	Long: `Manage settings of a Supabase instance.
+
+These commands work with both remote and local instances based on your current context.
+
+Examples:
+  supactl instance config list my-project
+  supactl instance config set my-project SITE_URL=https://app.example.com`,
+}
+
+func init() {
+	rootCmd.AddCommand(instanceCmd)
+}
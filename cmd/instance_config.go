@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configShowSecrets bool
+	configForce       bool
+	configRestart     bool
+	configNoRestart   bool
+)
+
+// secretKeyMarkers identify configuration keys whose values are masked in listings
+var secretKeyMarkers = []string{"PASS", "SECRET", "KEY", "TOKEN"}
+
+// instanceConfigCmd groups the per-instance configuration commands
+var instanceConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change an instance's configuration",
+	Long: `View and change an instance's environment configuration, such as SMTP
+settings, SITE_URL, JWT_EXPIRY, ENABLE_EMAIL_SIGNUP or OAuth providers.
+
+For local instances the values live in the project's supabase/docker/.env file
+and keys are validated against .env.example. For remote instances the values
+are managed by the SupaControl server.
+
+Examples:
+  supactl instance config list my-project
+  supactl instance config get my-project SITE_URL
+  supactl instance config set my-project SMTP_HOST=smtp.example.com SMTP_PORT=587
+  supactl instance config unset my-project GOTRUE_EXTERNAL_GITHUB_ENABLED`,
+}
+
+var instanceConfigListCmd = &cobra.Command{
+	Use:   "list <instance-name>",
+	Short: "List an instance's configuration",
+	Long: `List all configuration keys of an instance.
+
+Values of keys that look like secrets (passwords, keys, tokens) are masked
+unless --show-secrets is given.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		instanceName := strings.TrimSpace(args[0])
+		configProvider := getConfigProvider()
+
+		values, err := configProvider.GetConfig(instanceName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to get configuration: %v\n", err)
+			exit(1)
+		}
+
+		if len(values) == 0 {
+			fmt.Println("No configuration found.")
+			return
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE")
+		for _, key := range keys {
+			value := values[key]
+			if !configShowSecrets {
+				value = maskConfigValue(key, value)
+			}
+			fmt.Fprintf(w, "%s\t%s\n", key, value)
+		}
+		w.Flush()
+	},
+}
+
+var instanceConfigGetCmd = &cobra.Command{
+	Use:   "get <instance-name> <KEY>",
+	Short: "Print a configuration value",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		instanceName := strings.TrimSpace(args[0])
+		key := strings.TrimSpace(args[1])
+		configProvider := getConfigProvider()
+
+		values, err := configProvider.GetConfig(instanceName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to get configuration: %v\n", err)
+			exit(1)
+		}
+
+		value, ok := values[key]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Key '%s' is not set on instance '%s'\n", key, instanceName)
+			exit(1)
+		}
+
+		fmt.Println(value)
+	},
+}
+
+var instanceConfigSetCmd = &cobra.Command{
+	Use:   "set <instance-name> <KEY=VALUE>...",
+	Short: "Set configuration values",
+	Long: `Set one or more configuration values.
+
+For local instances keys must exist in the project's .env.example (use --force
+to set other keys). After the change you are offered to restart the services
+that use the changed keys; use --restart or --no-restart to skip the question.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		instanceName := strings.TrimSpace(args[0])
+		configProvider := getConfigProvider()
+
+		set := make(map[string]string, len(args)-1)
+		for _, arg := range args[1:] {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: Invalid argument '%s'. Expected KEY=VALUE\n", arg)
+				exit(1)
+			}
+			key = strings.TrimSpace(key)
+			if err := local.ValidateEnvKey(key); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+			set[key] = value
+		}
+
+		keys := make([]string, 0, len(set))
+		for key := range set {
+			keys = append(keys, key)
+		}
+		validateConfigKeys(configProvider, instanceName, keys)
+
+		services, err := configProvider.UpdateConfig(instanceName, set, nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to update configuration: %v\n", err)
+			exit(1)
+		}
+
+		sort.Strings(keys)
+		fmt.Printf("Updated %s on instance '%s'\n", strings.Join(keys, ", "), instanceName)
+		offerConfigRestart(configProvider, instanceName, services)
+	},
+}
+
+var instanceConfigUnsetCmd = &cobra.Command{
+	Use:   "unset <instance-name> <KEY>...",
+	Short: "Remove configuration values",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		instanceName := strings.TrimSpace(args[0])
+		configProvider := getConfigProvider()
+
+		keys := make([]string, 0, len(args)-1)
+		for _, arg := range args[1:] {
+			keys = append(keys, strings.TrimSpace(arg))
+		}
+
+		services, err := configProvider.UpdateConfig(instanceName, nil, keys)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to update configuration: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("Removed %s from instance '%s'\n", strings.Join(keys, ", "), instanceName)
+		offerConfigRestart(configProvider, instanceName, services)
+	},
+}
+
+func init() {
+	instanceCmd.AddCommand(instanceConfigCmd)
+
+	instanceConfigCmd.AddCommand(instanceConfigListCmd)
+	instanceConfigCmd.AddCommand(instanceConfigGetCmd)
+	instanceConfigCmd.AddCommand(instanceConfigSetCmd)
+	instanceConfigCmd.AddCommand(instanceConfigUnsetCmd)
+
+	instanceConfigListCmd.Flags().BoolVar(&configShowSecrets, "show-secrets", false, "Show secret values instead of masking them")
+
+	instanceConfigSetCmd.Flags().BoolVar(&configForce, "force", false, "Allow keys that are not declared in .env.example")
+	for _, c := range []*cobra.Command{instanceConfigSetCmd, instanceConfigUnsetCmd} {
+		c.Flags().BoolVar(&configRestart, "restart", false, "Restart affected services without asking")
+		c.Flags().BoolVar(&configNoRestart, "no-restart", false, "Do not restart affected services")
+	}
+}
+
+// getConfigProvider returns the current provider if it supports instance configuration, or exits
+func getConfigProvider() provider.ConfigProvider {
+	p := getProvider()
+
+	configProvider, ok := p.(provider.ConfigProvider)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: The %s provider does not support instance configuration\n", p.ProviderType())
+		exit(1)
+	}
+
+	return configProvider
+}
+
+// validateConfigKeys exits if any key is unknown to the instance, unless --force is set
+func validateConfigKeys(configProvider provider.ConfigProvider, instanceName string, keys []string) {
+	if configForce {
+		return
+	}
+
+	known, err := configProvider.KnownConfigKeys(instanceName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to read known configuration keys: %v\n", err)
+		exit(1)
+	}
+	if known == nil {
+		return
+	}
+
+	var unknown []string
+	for _, key := range keys {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		fmt.Fprintf(os.Stderr, "Error: Unknown configuration key(s): %s\n", strings.Join(unknown, ", "))
+		fmt.Fprintf(os.Stderr, "Keys must be declared in the project's .env.example. Use --force to set them anyway.\n")
+		exit(1)
+	}
+}
+
+// offerConfigRestart restarts the services affected by a configuration change if the user agrees
+func offerConfigRestart(configProvider provider.ConfigProvider, instanceName string, services []string) {
+	if configNoRestart {
+		return
+	}
+
+	if services != nil && len(services) == 0 {
+		fmt.Println("No services use the changed keys; nothing to restart.")
+		return
+	}
+
+	instance, err := getProvider().GetInstance(instanceName)
+	if err == nil && instance.Status != "running" {
+		fmt.Println("The instance is not running; changes will apply on next start.")
+		return
+	}
+
+	target := fmt.Sprintf("instance '%s'", instanceName)
+	if len(services) > 0 {
+		target = fmt.Sprintf("services %s", strings.Join(services, ", "))
+	}
+
+	if !configRestart {
+		if !confirm(fmt.Sprintf("Restart %s to apply the changes?", target)) {
+			fmt.Println("Changes will apply on the next restart.")
+			return
+		}
+	}
+
+	fmt.Printf("Restarting %s...\n", target)
+	if err := configProvider.ApplyConfig(instanceName, services); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to restart: %v\n", err)
+		exit(1)
+	}
+	fmt.Println("Configuration applied.")
+}
+
+// maskConfigValue hides the value of keys that look like secrets
+func maskConfigValue(key, value string) string {
+	if value == "" {
+		return value
+	}
+	upper := strings.ToUpper(key)
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(upper, marker) {
+			return "********"
+		}
+	}
+	return value
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import "testing"
+
+func TestMaskConfigValue(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"SITE_URL", "http://localhost:3000", "http://localhost:3000"},
+		{"POSTGRES_PASSWORD", "secret", "********"},
+		{"JWT_SECRET", "secret", "********"},
+		{"ANON_KEY", "eyJ...", "********"},
+		{"GOTRUE_EXTERNAL_GITHUB_SECRET", "abc", "********"},
+		{"SMTP_PASS", "abc", "********"},
+		{"SERVICE_ROLE_KEY", "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := maskConfigValue(tt.key, tt.value); got != tt.want {
+			t.Errorf("maskConfigValue(%q, %q) = %q, want %q", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
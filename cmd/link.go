@@ -1,79 +1,186 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/AlecAivazis/survey/v2"
+	"github.com/qubitquilt/supactl/internal/auth"
 	"github.com/qubitquilt/supactl/internal/link"
+	"github.com/qubitquilt/supactl/internal/projectfile"
+	"github.com/qubitquilt/supactl/internal/prompt"
+	"github.com/qubitquilt/supactl/internal/provider"
 	"github.com/spf13/cobra"
 )
 
 // linkCmd represents the link command
 var linkCmd = &cobra.Command{
-	Use:   "link",
-	Short: "Link current directory to a remote instance",
-	Long: `Link the current directory to a remote Supabase instance.
+	Use:   "link [instance]",
+	Short: "Link current directory to an instance",
+	Long: `Link the current directory to a Supabase instance in any context, local or remote.
 
-This command will present a list of your available instances and allow you
-to select one to link to the current directory. A .supacontrol/project file
-will be created to store the link.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client := getAPIClient()
+Without an argument, this command presents a list of the current context's
+instances and lets you select one to link to the current directory. Pass the
+instance name (or <context>/<instance>) to link it directly, e.g. in scripts.
 
+The link records the context along with the instance, so commands such as
+'status', 'logs', 'start' and 'db migrate' run against that context even after
+you switch to another one. A .supacontrol/project file will be created to
+store the link.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstances,
+	Run: func(cmd *cobra.Command, args []string) {
 		// Check if already linked
-		if link.IsLinked() {
-			existingProject, _ := link.GetLink()
-			fmt.Printf("This directory is already linked to '%s'\n", existingProject)
+		if existing, err := link.Load(); err == nil {
+			fmt.Printf("This directory is already linked to '%s'\n", existing.Ref())
 			fmt.Println("Run 'supactl unlink' first to unlink it.")
 			return
 		}
 
-		// Get list of instances
-		fmt.Println("Fetching your instances...")
-		instances, err := client.ListInstances()
+		config, err := auth.LoadConfig()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Failed to list instances: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error: Failed to load configuration: %v\n", err)
+			exit(1)
 		}
+		applyProjectContext(config)
 
-		if len(instances) == 0 {
-			fmt.Println("No instances found.")
-			fmt.Println("Create your first instance with: supactl create <project-name>")
-			return
+		contextName, instanceName := config.CurrentContext, ""
+		if len(args) == 1 {
+			instanceName = strings.TrimSpace(args[0])
+			if name, instance, found := strings.Cut(instanceName, "/"); found {
+				contextName, instanceName = name, instance
+			}
 		}
 
-		// Build options for the selector
-		options := make([]string, len(instances))
-		for i, instance := range instances {
-			options[i] = fmt.Sprintf("%s (%s)", instance.Name, instance.Status)
+		ctx, exists := config.Contexts[contextName]
+		if !exists {
+			fmt.Fprintf(os.Stderr, "Error: Context '%s' does not exist\n", contextName)
+			exit(1)
 		}
 
-		// Present interactive selector
-		var selectedIndex int
-		prompt := &survey.Select{
-			Message: "Select a project to link:",
-			Options: options,
+		p, err := newProvider(contextName, ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Context '%s': %v\n", contextName, err)
+			exit(1)
 		}
 
-		if err := survey.AskOne(prompt, &selectedIndex); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+		if instanceName != "" {
+			instance, err := p.GetInstance(instanceName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to get instance: %v\n", err)
+				exit(1)
+			}
+			instanceName = instance.Name
+		} else {
+			instanceName = selectInstance(p)
+			if instanceName == "" {
+				return
+			}
 		}
 
-		selectedProject := instances[selectedIndex].Name
-
 		// Save the link
-		if err := link.SaveLink(selectedProject); err != nil {
+		l := &link.Link{
+			Context:   contextName,
+			Provider:  ctx.Provider,
+			Instance:  instanceName,
+			ServerURL: ctx.ServerURL,
+		}
+		if err := link.Save(l); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to save link: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
-		fmt.Printf("\nSuccessfully linked to '%s'\n", selectedProject)
+		fmt.Printf("\nSuccessfully linked to '%s' in context '%s'\n", instanceName, contextName)
 		fmt.Println("Run 'supactl status' to see project details.")
 	},
 }
 
+// selectInstance asks the user to pick one of a provider's instances. It returns
+// an empty name if there are no instances.
+func selectInstance(p provider.InstanceProvider) string {
+	// Get list of instances
+	fmt.Println("Fetching your instances...")
+	instances, err := p.ListInstances()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to list instances: %v\n", err)
+		exit(1)
+	}
+
+	if len(instances) == 0 {
+		fmt.Println("No instances found.")
+		fmt.Println("Create your first instance with: supactl create <project-name>")
+		return ""
+	}
+
+	// Build options for the selector
+	options := make([]string, len(instances))
+	for i, instance := range instances {
+		options[i] = fmt.Sprintf("%s (%s)", instance.Name, instance.Status)
+	}
+
+	// Present interactive selector
+	selectedIndex, err := getPrompter().Select("Select a project to link:", options)
+	if errors.Is(err, prompt.ErrNonInteractive) {
+		fmt.Fprintf(os.Stderr, "Error: %v; pass the instance name: supactl link <instance>\n", err)
+		exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+
+	return instances[selectedIndex].Name
+}
+
+// instanceArg returns the instance reference given as the first argument or,
+// without arguments, the instance the current directory is linked to, falling
+// back to the instance declared in supactl.yaml
+func instanceArg(args []string) string {
+	if len(args) > 0 {
+		return strings.TrimSpace(args[0])
+	}
+
+	l, err := link.Load()
+	if err == nil {
+		warnLinkContext(l)
+		return l.Ref()
+	}
+
+	if project := getProjectFile(); project != nil && project.Instance != "" {
+		return project.InstanceRef()
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: No instance given and %v\n", err)
+	fmt.Fprintf(os.Stderr, "Alternatively, set 'instance' in %s (see 'supactl init').\n", projectfile.FileName)
+	exit(1)
+	return ""
+}
+
+// warnLinkContext warns when a link was made in another context than the current
+// one, or its context no longer points at the server the link was made against
+func warnLinkContext(l *link.Link) {
+	if l.Context == "" {
+		return
+	}
+
+	config, err := auth.LoadConfig()
+	if err != nil {
+		return
+	}
+	applyProjectContext(config)
+
+	if config.CurrentContext != l.Context {
+		fmt.Fprintf(os.Stderr, "Warning: This directory is linked to '%s' in context '%s', but the current context is '%s'. Using context '%s'.\n",
+			l.Instance, l.Context, config.CurrentContext, l.Context)
+	}
+
+	if ctx, exists := config.Contexts[l.Context]; exists && l.ServerURL != "" && ctx.ServerURL != l.ServerURL {
+		fmt.Fprintf(os.Stderr, "Warning: Context '%s' now points to %s, but the link was made against %s.\n",
+			l.Context, ctx.ServerURL, l.ServerURL)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(linkCmd)
 }
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/link"
+	"github.com/qubitquilt/supactl/internal/projectfile"
+)
+
+// captureStderr returns what f writes to stderr
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = old }()
+
+	f()
+	w.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// chdirProject changes to dir for the duration of a test and forgets the loaded supactl.yaml
+func chdirProject(t *testing.T, dir string) {
+	t.Helper()
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	projectFile, projectFileOnce = nil, sync.Once{}
+	t.Cleanup(func() {
+		os.Chdir(oldWd)
+		projectFile, projectFileOnce = nil, sync.Once{}
+	})
+}
+
+func TestInstanceArg(t *testing.T) {
+	setupCompletionHome(t, "local", map[string]*auth.ContextConfig{
+		"local": {Provider: "local"},
+		"prod":  {Provider: "remote", ServerURL: "https://new.example.com", APIKey: "key"},
+	})
+
+	chdirProject(t, t.TempDir())
+
+	if got := instanceArg([]string{" explicit "}); got != "explicit" {
+		t.Errorf("instanceArg(explicit) = %q", got)
+	}
+
+	// A link in the current context resolves silently
+	if err := link.Save(&link.Link{Context: "local", Provider: "local", Instance: "my-project"}); err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	stderr := captureStderr(t, func() { got = instanceArg(nil) })
+	if got != "local/my-project" || stderr != "" {
+		t.Errorf("instanceArg(nil) = %q, stderr %q", got, stderr)
+	}
+
+	// A link in another context resolves against that context with warnings
+	if err := link.Save(&link.Link{Context: "prod", Provider: "remote", Instance: "app", ServerURL: "https://old.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	stderr = captureStderr(t, func() { got = instanceArg(nil) })
+	if got != "prod/app" {
+		t.Errorf("instanceArg(nil) = %q, want prod/app", got)
+	}
+	if !strings.Contains(stderr, "current context is 'local'") || !strings.Contains(stderr, "https://old.example.com") {
+		t.Errorf("expected context and server warnings, got %q", stderr)
+	}
+
+	// Legacy links hold only the instance name
+	if err := os.WriteFile(link.GetLinkPath(), []byte("legacy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := instanceArg(nil); got != "legacy" {
+		t.Errorf("instanceArg(nil) = %q, want legacy", got)
+	}
+}
+
+func TestInstanceArg_ProjectFile(t *testing.T) {
+	setupCompletionHome(t, "local", map[string]*auth.ContextConfig{
+		"local": {Provider: "local"},
+		"prod":  {Provider: "remote", ServerURL: "https://prod.example.com", APIKey: "key"},
+	})
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, projectfile.FileName), []byte("context: prod\ninstance: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, "web", "src")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	chdirProject(t, nested)
+
+	// supactl.yaml supplies the instance and overrides the global context
+	if got := instanceArg(nil); got != "prod/app" {
+		t.Errorf("instanceArg(nil) = %q, want prod/app", got)
+	}
+	if got := currentContextName(); got != "prod" {
+		t.Errorf("currentContextName() = %q, want prod", got)
+	}
+	if got := migrationsDir(); got != filepath.Join(root, "supabase", "migrations") {
+		t.Errorf("migrationsDir() = %q", got)
+	}
+
+	// Arguments override it
+	if got := instanceArg([]string{"other"}); got != "other" {
+		t.Errorf("instanceArg(other) = %q", got)
+	}
+}
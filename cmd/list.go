@@ -22,7 +22,7 @@ Works with both remote and local instances based on your current context.`,
 		instances, err := provider.ListInstances()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to list instances: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if len(instances) == 0 {
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
+	"github.com/qubitquilt/supactl/internal/auth"
 	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/qubitquilt/supactl/internal/provider"
 	"github.com/spf13/cobra"
 )
 
@@ -26,7 +30,9 @@ Examples:
   supactl local list                 # List all local instances
   supactl local start my-project     # Start an instance
   supactl local stop my-project      # Stop an instance
-  supactl local remove my-project    # Remove an instance`,
+  supactl local remove my-project    # Remove an instance
+  supactl local migrate my-project   # Move an older instance to the override layout
+  supactl local set-resources my-project --service db --memory 1g --cpus 2`,
 }
 
 func init() {
@@ -42,13 +48,34 @@ func getLocalDatabase() (*local.Database, error) {
 	return db, nil
 }
 
+// localRunner returns the compose runner of the current context, so the local commands
+// honour its docker_runner and docker_socket. When the current context is not a local one
+// the docker CLI is used.
+func localRunner() local.ComposeRunner {
+	config, err := auth.LoadConfig()
+	if err == nil {
+		applyProjectContext(config)
+		if ctx, err := config.GetCurrentContext(); err == nil && ctx.Provider == provider.ProviderTypeLocal {
+			runner, err := local.NewComposeRunner(ctx.DockerRunner, ctx.DockerSocket, commandExecutor)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Invalid docker runner in context '%s': %v\n", config.CurrentContext, err)
+				exit(1)
+			}
+			return runner
+		}
+	}
+	return local.NewCLIRunner(commandExecutor)
+}
+
 // checkDockerRequirements ensures Docker and Docker Compose are available
 func checkDockerRequirements() error {
-	if err := local.CheckDockerAvailable(); err != nil {
+	if err := local.CheckDockerAvailable(commandExecutor); err != nil {
 		return fmt.Errorf("Docker is required but not available.\nPlease install Docker and ensure it's running.\nVisit https://docs.docker.com/get-docker/ for installation instructions")
 	}
 
-	if err := local.CheckDockerComposeAvailable(); err != nil {
+	if err := local.CheckDockerComposeAvailable(commandExecutor); errors.Is(err, local.ErrDockerComposeTooOld) {
+		return fmt.Errorf("%v.\nPlease upgrade Docker Compose.\nVisit https://docs.docker.com/compose/install/ for installation instructions", err)
+	} else if err != nil {
 		return fmt.Errorf("Docker Compose is required but not available.\nPlease install Docker Compose.\nVisit https://docs.docker.com/compose/install/ for installation instructions")
 	}
 
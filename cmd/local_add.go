@@ -9,6 +9,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	localAddSupabaseVersion string
+	localAddProfile         string
+)
+
 var localAddCmd = &cobra.Command{
 	Use:   "add <project-id>",
 	Short: "Add a new local Supabase instance",
@@ -22,36 +27,52 @@ The project ID must:
 
 This command will:
   1. Create a new directory for the project
-  2. Clone the Supabase repository
+  2. Clone the Supabase repository (--supabase-version, or supabase_version
+     from supactl.yaml, selects a branch or tag)
   3. Generate secure passwords and JWT tokens
   4. Configure .env file with generated secrets
-  5. Update docker-compose.yml with unique ports
+  5. Generate docker-compose.supactl.yml with unique ports (the upstream
+     docker-compose.yml is left untouched)
   6. Save project configuration to the local database
 
-Example:
-  supactl local add my-project`,
+--profile chooses which services start:
+  minimal   without analytics, vector, imgproxy and functions (no Studio
+            logs, image transformations or edge functions)
+  standard  without analytics and vector (no Studio logs)
+  full      every service (default)
+
+Per-service memory and CPU limits are set with 'supactl local set-resources'.
+
+Examples:
+  supactl local add my-project
+  supactl local add my-project --profile minimal`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		projectID := args[0]
 
+		if err := local.ValidateProfile(localAddProfile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
 		// Check Docker requirements
 		if err := checkDockerRequirements(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Load database
 		db, err := getLocalDatabase()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Determine project directory
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to get home directory: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 		directory := filepath.Join(homeDir, projectID)
 
@@ -59,17 +80,23 @@ Example:
 		fmt.Printf("Creating local Supabase instance '%s'...\n", projectID)
 		fmt.Printf("Directory: %s\n\n", directory)
 
-		secrets, err := local.SetupProject(projectID, directory, db)
+		version := localAddSupabaseVersion
+		if version == "" {
+			version = supabaseVersion()
+		}
+
+		secrets, err := local.SetupProject(projectID, directory, version, localAddProfile, db)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
+		invalidateLocalInstanceNames()
 
 		// Get the project details for port information
 		project, err := db.GetProject(projectID)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Print success message
@@ -97,6 +124,8 @@ Example:
 		fmt.Printf("  DB Port:       %d\n", project.Ports.DB)
 		fmt.Printf("  Studio Port:   %d\n", project.Ports.Studio)
 		fmt.Printf("  Inbucket Port: %d\n", project.Ports.Inbucket)
+		fmt.Println()
+		fmt.Printf("Profile: %s\n", project.Profile)
 		fmt.Println("----------------------------------------------------------------------")
 		fmt.Println()
 		fmt.Println("Configuration complete! Start your instance with:")
@@ -106,4 +135,6 @@ Example:
 
 func init() {
 	localCmd.AddCommand(localAddCmd)
+	localAddCmd.Flags().StringVar(&localAddSupabaseVersion, "supabase-version", "", "Supabase branch or tag to check out (default: supabase_version from supactl.yaml, else the default branch)")
+	localAddCmd.Flags().StringVar(&localAddProfile, "profile", local.ProfileFull, "Services to run: minimal, standard or full")
 }
@@ -19,7 +19,7 @@ Example:
 		db, err := getLocalDatabase()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if len(db.Projects) == 0 {
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/spf13/cobra"
+)
+
+var localMigrateAll bool
+
+var localMigrateCmd = &cobra.Command{
+	Use:   "migrate [project-id]",
+	Short: "Move a local instance to the docker-compose.supactl.yml override layout",
+	Long: `Move a local instance created by an older supactl version to the override layout.
+
+Older versions rewrote the upstream docker-compose.yml and config.toml in place,
+which makes every 'git pull' of the supabase checkout conflict. This command:
+  1. Restores the upstream files from the git checkout (if they were modified),
+     asking first when they carry edits supactl did not make
+  2. Generates docker-compose.supactl.yml with the project's ports, container
+     names and labels
+
+Restart the instance afterwards to apply the override.
+
+Examples:
+  supactl local migrate my-project
+  supactl local migrate --all`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 && !localMigrateAll {
+			fmt.Fprintf(os.Stderr, "Error: Specify a project ID or use --all\n")
+			exit(1)
+		}
+
+		db, err := getLocalDatabase()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		var projectIDs []string
+		if localMigrateAll {
+			for id := range db.Projects {
+				projectIDs = append(projectIDs, id)
+			}
+			sort.Strings(projectIDs)
+		} else {
+			if _, err := db.GetProject(args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+			projectIDs = []string{args[0]}
+		}
+
+		failed := false
+		for _, projectID := range projectIDs {
+			project := db.Projects[projectID]
+
+			restored, missing, err := local.MigrateProject(projectID, &project, false)
+			if errors.Is(err, local.ErrUpstreamEdited) {
+				fmt.Fprintf(os.Stderr, "Warning: '%s': %v\n", projectID, err)
+				if !confirm("Restoring the upstream files will discard these changes. Continue?") {
+					fmt.Printf("Skipped '%s'\n", projectID)
+					failed = true
+					continue
+				}
+				restored, missing, err = local.MigrateProject(projectID, &project, true)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to migrate '%s': %v\n", projectID, err)
+				failed = true
+				continue
+			}
+
+			for _, service := range missing {
+				fmt.Printf("Warning: expected service %s not found in %s\n", service, local.ComposeFileName)
+			}
+			if restored {
+				fmt.Printf("Restored upstream files for '%s'\n", projectID)
+			}
+			fmt.Printf("Generated %s for '%s'\n", local.ComposeOverrideFileName, projectID)
+		}
+
+		if failed {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	localCmd.AddCommand(localMigrateCmd)
+	localMigrateCmd.Flags().BoolVar(&localMigrateAll, "all", false, "Migrate all local projects")
+}
@@ -4,11 +4,12 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/qubitquilt/supactl/internal/local"
 	"github.com/spf13/cobra"
 )
 
+var localRemoveVolumes bool
+
 var localRemoveCmd = &cobra.Command{
 	Use:   "remove <project-id>",
 	Short: "Remove a local Supabase instance from configuration",
@@ -18,12 +19,15 @@ This command will:
   1. Stop the instance if it's running
   2. Remove the project from the local database
 
-Note: This does NOT delete the project directory or Docker images.
-To completely remove all data, you'll need to manually delete the directory
-and run 'docker system prune' to clean up unused Docker resources.
+Note: This does NOT delete the project directory or Docker images, and keeps
+the instance's Docker volumes unless --volumes is given (which asks for a
+second confirmation). To completely remove all data, you'll need to manually
+delete the directory and run 'docker system prune' to clean up unused Docker
+resources.
 
-Example:
-  supactl local remove my-project`,
+Examples:
+  supactl local remove my-project
+  supactl local remove my-project --volumes`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		projectID := args[0]
@@ -31,14 +35,14 @@ Example:
 		// Check Docker requirements
 		if err := checkDockerRequirements(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Load database
 		db, err := getLocalDatabase()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Get project to ensure it exists
@@ -54,28 +58,22 @@ Example:
 					fmt.Fprintf(os.Stderr, "  - %s\n", id)
 				}
 			}
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Confirm removal
-		var confirm bool
-		prompt := &survey.Confirm{
-			Message: fmt.Sprintf("Are you sure you want to remove project '%s'?", projectID),
-			Default: false,
-		}
-		if err := survey.AskOne(prompt, &confirm); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-
-		if !confirm {
+		if !confirm(fmt.Sprintf("Are you sure you want to remove project '%s'?", projectID)) {
 			fmt.Println("Removal cancelled.")
+			cancelAudit()
+			return
+		}
+		if localRemoveVolumes && !confirmVolumeRemoval(projectID, "Removal") {
 			return
 		}
 
 		// Stop the instance first
 		fmt.Printf("Stopping Supabase instance '%s'...\n", projectID)
-		if err := local.DockerComposeDown(projectID, project.Directory); err != nil {
+		if err := localRunner().Down(projectID, project.Directory, localRemoveVolumes); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: Failed to stop instance: %v\n", err)
 			fmt.Fprintf(os.Stderr, "Continuing with removal...\n\n")
 		}
@@ -83,14 +81,15 @@ Example:
 		// Remove from database
 		if err := db.RemoveProject(projectID); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Save database
 		if err := local.SaveDatabase(db); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to save database: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
+		invalidateLocalInstanceNames()
 
 		fmt.Printf("\nProject '%s' has been removed from the configuration.\n", projectID)
 		fmt.Printf("\nNote: The project directory has NOT been deleted:\n")
@@ -104,4 +103,5 @@ Example:
 
 func init() {
 	localCmd.AddCommand(localRemoveCmd)
+	localRemoveCmd.Flags().BoolVar(&localRemoveVolumes, "volumes", false, "Also remove the instance's volumes, deleting its data")
 }
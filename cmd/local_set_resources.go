@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/spf13/cobra"
+)
+
+var (
+	localSetResourcesService string
+	localSetResourcesMemory  string
+	localSetResourcesCPUs    string
+	localSetResourcesClear   bool
+)
+
+var localSetResourcesCmd = &cobra.Command{
+	Use:   "set-resources <project-id>",
+	Short: "Set memory and CPU limits of a local instance's service",
+	Long: `Set the memory and CPU limits of one service of a local Supabase instance.
+
+Limits are recorded with the project and written into
+docker-compose.supactl.yml as mem_limit and cpus. Flags that are not given
+keep their current value; --clear removes both limits of the service.
+
+Limits take effect the next time 'supactl local start' runs, which recreates
+the containers whose configuration changed.
+
+Examples:
+  supactl local set-resources my-project --service db --memory 1g --cpus 2
+  supactl local set-resources my-project --service realtime --memory 256m
+  supactl local set-resources my-project --service db --clear`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectID := args[0]
+
+		if localSetResourcesClear && (localSetResourcesMemory != "" || localSetResourcesCPUs != "") {
+			fmt.Fprintf(os.Stderr, "Error: --clear cannot be combined with --memory or --cpus\n")
+			exit(1)
+		}
+		if !localSetResourcesClear && localSetResourcesMemory == "" && localSetResourcesCPUs == "" {
+			fmt.Fprintf(os.Stderr, "Error: Specify --memory, --cpus or --clear\n")
+			exit(1)
+		}
+		if localSetResourcesMemory != "" {
+			if err := local.ValidateMemoryLimit(localSetResourcesMemory); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+		}
+		if localSetResourcesCPUs != "" {
+			if err := local.ValidateCPULimit(localSetResourcesCPUs); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+		}
+
+		db, err := getLocalDatabase()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		project, err := db.GetProject(projectID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		if !local.HasComposeOverride(project.Directory) {
+			fmt.Fprintf(os.Stderr, "Error: '%s' still uses a modified docker-compose.yml, which cannot carry resource limits\n", projectID)
+			fmt.Fprintf(os.Stderr, "Run 'supactl local migrate %s' first.\n", projectID)
+			exit(1)
+		}
+
+		upstream, err := local.LoadComposeFile(filepath.Join(local.GetDockerDir(project.Directory), local.ComposeFileName))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		service := localSetResourcesService
+		if !upstream.HasService(service) {
+			fmt.Fprintf(os.Stderr, "Error: Service '%s' not found in %s\n", service, local.ComposeFileName)
+			fmt.Fprintf(os.Stderr, "\nAvailable services:\n")
+			for _, name := range upstream.Services() {
+				fmt.Fprintf(os.Stderr, "  - %s\n", name)
+			}
+			exit(1)
+		}
+
+		resources := project.Resources[service]
+		if localSetResourcesClear {
+			resources = local.ServiceResources{}
+		}
+		if localSetResourcesMemory != "" {
+			resources.Memory = localSetResourcesMemory
+		}
+		if localSetResourcesCPUs != "" {
+			resources.CPUs = localSetResourcesCPUs
+		}
+
+		if project.Resources == nil {
+			project.Resources = make(map[string]local.ServiceResources)
+		}
+		if resources == (local.ServiceResources{}) {
+			delete(project.Resources, service)
+		} else {
+			project.Resources[service] = resources
+		}
+		if len(project.Resources) == 0 {
+			project.Resources = nil
+		}
+		db.Projects[projectID] = *project
+
+		if err := local.SaveDatabase(db); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to save database: %v\n", err)
+			exit(1)
+		}
+
+		if err := local.SyncComposeOverride(projectID, project); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		if localSetResourcesClear {
+			fmt.Printf("Cleared the resource limits of '%s' in '%s'\n", service, projectID)
+		} else {
+			fmt.Printf("Set the resource limits of '%s' in '%s': memory %s, cpus %s\n", service, projectID, orDash(resources.Memory), orDash(resources.CPUs))
+		}
+
+		for _, disabled := range local.DisabledServices(project.Profile) {
+			if disabled == service {
+				fmt.Printf("Note: '%s' is not started by the '%s' profile.\n", service, project.Profile)
+			}
+		}
+		fmt.Printf("Run 'supactl local start %s' to apply.\n", projectID)
+	},
+}
+
+func init() {
+	localCmd.AddCommand(localSetResourcesCmd)
+	localSetResourcesCmd.Flags().StringVar(&localSetResourcesService, "service", "", "Compose service to limit (e.g. db, realtime)")
+	localSetResourcesCmd.Flags().StringVar(&localSetResourcesMemory, "memory", "", "Memory limit (e.g. 512m, 1g)")
+	localSetResourcesCmd.Flags().StringVar(&localSetResourcesCPUs, "cpus", "", "CPU limit in CPUs (e.g. 0.5, 2)")
+	localSetResourcesCmd.Flags().BoolVar(&localSetResourcesClear, "clear", false, "Remove the limits of the service")
+	localSetResourcesCmd.MarkFlagRequired("service")
+}
@@ -14,8 +14,10 @@ var localStartCmd = &cobra.Command{
 	Short: "Start a local Supabase instance",
 	Long: `Start a local Supabase instance using Docker Compose.
 
-This command will start all Supabase services (PostgreSQL, Kong, GoTrue, etc.)
-in Docker containers with the configured ports.
+This command will start the Supabase services of the project's profile
+(PostgreSQL, Kong, GoTrue, etc.) in Docker containers with the configured
+ports and resource limits. Containers whose configuration changed since the
+last start are recreated.
 
 Example:
   supactl local start my-project`,
@@ -26,14 +28,14 @@ Example:
 		// Check Docker requirements
 		if err := checkDockerRequirements(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Load database
 		db, err := getLocalDatabase()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Get project
@@ -49,16 +51,27 @@ Example:
 					fmt.Fprintf(os.Stderr, "  - %s\n", id)
 				}
 			}
-			os.Exit(1)
+			exit(1)
+		}
+
+		if !local.HasComposeOverride(project.Directory) {
+			fmt.Printf("Note: '%s' still uses a modified docker-compose.yml. Run 'supactl local migrate %s'\n", projectID, projectID)
+			fmt.Printf("to restore the upstream files and generate %s.\n\n", local.ComposeOverrideFileName)
+		}
+
+		// Pick up profile and resource limit changes
+		if err := local.SyncComposeOverride(projectID, project); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
 		}
 
 		// Start the instance
 		fmt.Printf("Starting Supabase instance '%s'...\n", projectID)
 		fmt.Printf("Directory: %s/supabase/docker\n\n", project.Directory)
 
-		if err := local.DockerComposeUp(projectID, project.Directory); err != nil {
+		if err := localRunner().Up(projectID, project.Directory); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Get host IP for display
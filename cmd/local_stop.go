@@ -4,20 +4,24 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/qubitquilt/supactl/internal/local"
 	"github.com/spf13/cobra"
 )
 
+var localStopVolumes bool
+
 var localStopCmd = &cobra.Command{
 	Use:   "stop <project-id>",
 	Short: "Stop a local Supabase instance",
 	Long: `Stop a local Supabase instance and remove all containers.
 
 This command will stop all running Supabase services for the specified project
-and clean up Docker resources (containers, volumes, networks).
+and clean up its containers and networks. The Docker volumes holding the
+database and storage data are kept unless --volumes is given, which deletes
+them after asking for confirmation (--yes skips it).
 
-Example:
-  supactl local stop my-project`,
+Examples:
+  supactl local stop my-project
+  supactl local stop my-project --volumes`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		projectID := args[0]
@@ -25,14 +29,14 @@ Example:
 		// Check Docker requirements
 		if err := checkDockerRequirements(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Load database
 		db, err := getLocalDatabase()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Get project
@@ -48,16 +52,20 @@ Example:
 					fmt.Fprintf(os.Stderr, "  - %s\n", id)
 				}
 			}
-			os.Exit(1)
+			exit(1)
+		}
+
+		if localStopVolumes && !confirmVolumeRemoval(projectID, "Stop") {
+			return
 		}
 
 		// Stop the instance
 		fmt.Printf("Stopping Supabase instance '%s'...\n", projectID)
 		fmt.Printf("Directory: %s/supabase/docker\n\n", project.Directory)
 
-		if err := local.DockerComposeDown(projectID, project.Directory); err != nil {
+		if err := localRunner().Down(projectID, project.Directory, localStopVolumes); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("\nSupabase instance '%s' has been stopped.\n", projectID)
@@ -66,4 +74,5 @@ Example:
 
 func init() {
 	localCmd.AddCommand(localStopCmd)
+	localStopCmd.Flags().BoolVar(&localStopVolumes, "volumes", false, "Also remove the instance's volumes, deleting its data")
 }
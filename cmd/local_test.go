@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/local"
+)
+
+func TestLocalRunner_FollowsCurrentContext(t *testing.T) {
+	contexts := map[string]*auth.ContextConfig{
+		"local":     {Provider: "local"},
+		"local-api": {Provider: "local", DockerRunner: local.RunnerAPI, DockerSocket: "/tmp/docker.sock"},
+		"prod":      {Provider: "remote", ServerURL: "https://prod.example.com", APIKey: "key"},
+	}
+
+	tests := map[string]string{
+		"local":     "*local.CLIRunner",
+		"local-api": "*local.EngineRunner",
+		"prod":      "*local.CLIRunner",
+	}
+	for current, want := range tests {
+		setupCompletionHome(t, current, contexts)
+		if got := fmt.Sprintf("%T", localRunner()); got != want {
+			t.Errorf("localRunner() in context %s = %s, want %s", current, got, want)
+		}
+	}
+}
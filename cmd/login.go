@@ -1,17 +1,21 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/qubitquilt/supactl/internal/api"
 	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/prompt"
 	"github.com/qubitquilt/supactl/internal/provider"
 	"github.com/spf13/cobra"
 )
 
+var loginAPIKeyStdin bool
+
 // loginCmd represents the login command
 var loginCmd = &cobra.Command{
 	Use:   "login <server_url>",
@@ -19,7 +23,10 @@ var loginCmd = &cobra.Command{
 	Long: `Login to your SupaControl server by providing your server URL and API key.
 
 The API key can be obtained from your SupaControl dashboard.
-Your credentials will be stored securely in ~/.supacontrol/config.json as the 'default' context.`,
+Your credentials will be stored securely in ~/.supacontrol/config.json as the 'default' context.
+
+In scripts, pipe the API key in with --api-key-stdin:
+  echo "$SUPACONTROL_API_KEY" | supactl login https://supacontrol.example.com --api-key-stdin`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		serverURL := strings.TrimRight(args[0], "/")
@@ -27,17 +34,13 @@ Your credentials will be stored securely in ~/.supacontrol/config.json as the 'd
 		// Validate URL format
 		if !strings.HasPrefix(serverURL, "http://") && !strings.HasPrefix(serverURL, "https://") {
 			fmt.Fprintf(os.Stderr, "Error: Server URL must start with http:// or https://\n")
-			os.Exit(1)
+			exit(1)
 		}
 
-		// Prompt for API key (no echo)
-		var apiKey string
-		prompt := &survey.Password{
-			Message: "Enter your API key:",
-		}
-		if err := survey.AskOne(prompt, &apiKey, survey.WithValidator(survey.Required)); err != nil {
+		apiKey, err := readAPIKey()
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Test the credentials
@@ -45,7 +48,7 @@ Your credentials will be stored securely in ~/.supacontrol/config.json as the 'd
 		client := api.NewClient(serverURL, apiKey)
 		if err := client.LoginTest(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Authentication failed: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Save the configuration using new context management functions
@@ -73,12 +76,12 @@ Your credentials will be stored securely in ~/.supacontrol/config.json as the 'd
 		// Set current context to default
 		if err := config.SetCurrentContext("default"); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to set current context: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if err := auth.SaveConfig(config); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to save credentials: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("Successfully logged in to %s\n", serverURL)
@@ -86,6 +89,29 @@ Your credentials will be stored securely in ~/.supacontrol/config.json as the 'd
 	},
 }
 
+// readAPIKey reads the API key from stdin with --api-key-stdin, or prompts for it without echo
+func readAPIKey() (string, error) {
+	if loginAPIKeyStdin {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read API key from stdin: %w", err)
+		}
+		apiKey := strings.TrimSpace(string(data))
+		if apiKey == "" {
+			return "", fmt.Errorf("no API key on stdin")
+		}
+		return apiKey, nil
+	}
+
+	apiKey, err := getPrompter().Password("Enter your API key:")
+	if errors.Is(err, prompt.ErrNonInteractive) {
+		return "", fmt.Errorf("%w; pass the key with --api-key-stdin", err)
+	}
+	return apiKey, err
+}
+
 func init() {
 	rootCmd.AddCommand(loginCmd)
+
+	loginCmd.Flags().BoolVar(&loginAPIKeyStdin, "api-key-stdin", false, "Read the API key from stdin instead of prompting")
 }
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/prompt"
+)
+
+// withPrompter replaces the prompter for the duration of a test
+func withPrompter(t *testing.T, p prompt.Prompter) {
+	t.Helper()
+	old := prompter
+	prompter = p
+	t.Cleanup(func() { prompter = old })
+}
+
+func TestReadAPIKey_Prompt(t *testing.T) {
+	scripted := &prompt.Scripted{Answers: []interface{}{"sk-test"}}
+	withPrompter(t, scripted)
+
+	apiKey, err := readAPIKey()
+	if err != nil || apiKey != "sk-test" {
+		t.Errorf("readAPIKey() = %q, %v", apiKey, err)
+	}
+	if len(scripted.Asked) != 1 {
+		t.Errorf("expected one prompt, got %v", scripted.Asked)
+	}
+}
+
+func TestReadAPIKey_NonInteractive(t *testing.T) {
+	withPrompter(t, &prompt.Terminal{Interactive: false})
+
+	_, err := readAPIKey()
+	if !errors.Is(err, prompt.ErrNonInteractive) || !strings.Contains(err.Error(), "--api-key-stdin") {
+		t.Errorf("expected a non-interactive error suggesting --api-key-stdin, got %v", err)
+	}
+}
+
+func TestReadAPIKey_Stdin(t *testing.T) {
+	scripted := &prompt.Scripted{}
+	withPrompter(t, scripted)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStdin := os.Stdin
+	os.Stdin = r
+	loginAPIKeyStdin = true
+	defer func() {
+		os.Stdin = oldStdin
+		loginAPIKeyStdin = false
+	}()
+
+	w.WriteString("  sk-from-stdin\n")
+	w.Close()
+
+	apiKey, err := readAPIKey()
+	if err != nil || apiKey != "sk-from-stdin" {
+		t.Errorf("readAPIKey() = %q, %v", apiKey, err)
+	}
+	if len(scripted.Asked) != 0 {
+		t.Errorf("expected no prompts, got %v", scripted.Asked)
+	}
+}
@@ -23,7 +23,7 @@ This will delete the configuration file at ~/.supacontrol/config.json.`,
 
 		if err := auth.ClearConfig(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to logout: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Println("Successfully logged out.")
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -14,24 +13,26 @@ var (
 
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
-	Use:   "logs <instance-name>",
+	Use:   "logs [instance-name]",
 	Short: "View logs for a Supabase instance",
 	Long: `View logs for a Supabase instance.
 
 This command retrieves and displays the recent logs from the instance containers.
 Works with both remote and local instances based on your current context.
-Use the --lines flag to control how many lines to display.`,
-	Args: cobra.ExactArgs(1),
+Use the --lines flag to control how many lines to display.
+
+Without an instance name, the instance linked to the current directory is used.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstances,
 	Run: func(cmd *cobra.Command, args []string) {
-		instanceName := strings.TrimSpace(args[0])
-		provider := getProvider()
+		provider, instanceName := resolveInstanceRef(instanceArg(args))
 
 		fmt.Printf("Fetching logs for instance '%s'...\n\n", instanceName)
 
 		logs, err := provider.GetLogs(instanceName, logLines)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to fetch logs: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Println(logs)
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/functions"
+	"github.com/qubitquilt/supactl/internal/migrate"
+	"github.com/qubitquilt/supactl/internal/projectfile"
+	"github.com/qubitquilt/supactl/internal/provider"
+)
+
+var (
+	// projectFile is the supactl.yaml found for the current directory, loaded once per run
+	projectFile     *projectfile.Config
+	projectFileOnce sync.Once
+)
+
+// getProjectFile returns the supactl.yaml of the current directory or a parent,
+// nil if there is none. An invalid file is a fatal error, so commands that fan out to
+// goroutines load it before starting them.
+func getProjectFile() *projectfile.Config {
+	projectFileOnce.Do(func() {
+		c, err := projectfile.Discover()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		projectFile = c
+	})
+	return projectFile
+}
+
+// applyProjectContext makes the project file's context the current one for this run.
+// The global configuration on disk is not changed.
+func applyProjectContext(config *auth.Config) {
+	if p := getProjectFile(); p != nil && p.Context != "" {
+		config.CurrentContext = p.Context
+	}
+}
+
+// migrationsDir returns the migrations directory from supactl.yaml, or the default
+func migrationsDir() string {
+	if p := getProjectFile(); p != nil {
+		return p.MigrationsDir()
+	}
+	return migrate.DefaultDir
+}
+
+// functionsDir returns the edge functions directory from supactl.yaml, or the default
+func functionsDir() string {
+	if p := getProjectFile(); p != nil {
+		return p.FunctionsDir()
+	}
+	return functions.DefaultDir
+}
+
+// supabaseVersion returns the Supabase checkout ref from supactl.yaml, or the default branch
+func supabaseVersion() string {
+	if p := getProjectFile(); p != nil {
+		return p.SupabaseVersion
+	}
+	return ""
+}
+
+// applyProjectEnv sets the env overrides of supactl.yaml on the project's instance.
+// Other instances are left alone, as are providers without configuration support.
+func applyProjectEnv(p provider.InstanceProvider, ref string) {
+	project := getProjectFile()
+	if project == nil || len(project.Env) == 0 {
+		return
+	}
+
+	contextName, instanceName, found := strings.Cut(ref, "/")
+	if !found {
+		contextName, instanceName = currentContextName(), ref
+	}
+	if project.Instance != instanceName || (project.Context != "" && project.Context != contextName) {
+		return
+	}
+
+	configProvider, ok := p.(provider.ConfigProvider)
+	if !ok {
+		return
+	}
+
+	if _, err := configProvider.UpdateConfig(instanceName, project.Env, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to apply env from %s: %v\n", projectfile.FileName, err)
+		exit(1)
+	}
+	fmt.Printf("Applied %d env override(s) from %s\n", len(project.Env), projectfile.FileName)
+}
+
+// currentContextName returns the context commands run against, after supactl.yaml is applied
+func currentContextName() string {
+	config, err := auth.LoadConfig()
+	if err != nil {
+		return ""
+	}
+	applyProjectContext(config)
+	return config.CurrentContext
+}
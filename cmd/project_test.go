@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/projectfile"
+)
+
+// Regression test: 'get instances --all-contexts' and 'ui' open providers from several
+// goroutines, which used to load supactl.yaml concurrently without synchronisation
+func TestGetProjectFile_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, projectfile.FileName), []byte("supabase_version: v1.2.3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdirProject(t, dir)
+
+	var wg sync.WaitGroup
+	versions := make([]string, 8)
+	for i := range versions {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			versions[i] = supabaseVersion()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, version := range versions {
+		if version != "v1.2.3" {
+			t.Errorf("goroutine %d read version %q, want v1.2.3", i, version)
+		}
+	}
+}
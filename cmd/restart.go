@@ -8,24 +8,47 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var restartServices []string
+
 // restartCmd represents the restart command
 var restartCmd = &cobra.Command{
-	Use:   "restart <instance-name>",
+	Use:   "restart [instance-name]",
 	Short: "Restart a Supabase instance",
 	Long: `Restart a Supabase instance.
 
 This command works with both remote and local instances based on your current context.
-Useful for applying configuration changes or recovering from issues.`,
-	Args: cobra.ExactArgs(1),
+Useful for applying configuration changes or recovering from issues.
+
+Without an instance name, the instance linked to the current directory is used.
+
+For local instances, --service restarts only the given services.
+
+Examples:
+  supactl restart my-project
+  supactl restart my-project --service auth`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstances,
 	Run: func(cmd *cobra.Command, args []string) {
-		instanceName := strings.TrimSpace(args[0])
-		provider := getProvider()
+		provider, instanceName := resolveInstanceRef(instanceArg(args))
+
+		if len(restartServices) > 0 {
+			sp := getServiceProvider(provider, "--service")
+			fmt.Printf("Restarting %s of instance '%s'...\n", strings.Join(restartServices, ", "), instanceName)
+
+			if err := sp.RestartServices(instanceName, restartServices); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to restart services: %v\n", err)
+				exit(1)
+			}
+
+			fmt.Printf("Successfully restarted %s of instance '%s'\n", strings.Join(restartServices, ", "), instanceName)
+			return
+		}
 
 		fmt.Printf("Restarting instance '%s'...\n", instanceName)
 
 		if err := provider.RestartInstance(instanceName); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to restart instance: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("Successfully restarted instance '%s'\n", instanceName)
@@ -34,4 +57,5 @@ Useful for applying configuration changes or recovering from issues.`,
 
 func init() {
 	rootCmd.AddCommand(restartCmd)
+	restartCmd.Flags().StringSliceVar(&restartServices, "service", nil, "Only restart these services of a local instance (comma-separated or repeatable)")
 }
@@ -1,17 +1,28 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/qubitquilt/supactl/internal/api"
 	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/qubitquilt/supactl/internal/projectfile"
+	"github.com/qubitquilt/supactl/internal/prompt"
 	"github.com/qubitquilt/supactl/internal/provider"
 	"github.com/spf13/cobra"
 )
 
 var (
 	version = "1.0.0"
+
+	assumeYes      bool
+	nonInteractive bool
+
+	// prompter answers interactive questions; tests replace it with a scripted one
+	prompter prompt.Prompter
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -26,55 +37,117 @@ instances in two modes:
 
 Use contexts to switch between different providers (local or remote servers).`,
 	Version: version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		startAudit(cmd, args)
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		finishAudit(0)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		exit(1)
 	}
 }
 
 func init() {
 	rootCmd.SetVersionTemplate("supactl version {{.Version}}\n")
+
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Answer yes to all confirmation prompts")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Never prompt; fail if input is missing (the default when stdin is not a terminal)")
 }
 
+// getPrompter returns the prompter configured by --yes and --non-interactive
+func getPrompter() prompt.Prompter {
+	if prompter == nil {
+		prompter = prompt.NewTerminal(assumeYes, nonInteractive)
+	}
+	return prompter
+}
+
+// confirm asks a yes/no question defaulting to no, exiting if it cannot be asked
+func confirm(message string) bool {
+	confirmed, err := getPrompter().Confirm(message, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+	return confirmed
+}
+
+// errMissingCredentials is returned by newProvider for remote contexts without a server URL or API key
+var errMissingCredentials = errors.New("remote context is missing credentials")
+
 // getProvider creates and returns the appropriate provider based on the current context
 func getProvider() provider.InstanceProvider {
 	config, err := auth.LoadConfig()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to load configuration: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
+	applyProjectContext(config)
+
 	ctx, err := config.GetCurrentContext()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Run 'supactl config use-context <name>' to set a context.\n")
-		os.Exit(1)
+		if project := getProjectFile(); project != nil && project.Context == config.CurrentContext {
+			fmt.Fprintf(os.Stderr, "The context is set in %s.\n", filepath.Join(project.Dir, projectfile.FileName))
+		} else {
+			fmt.Fprintf(os.Stderr, "Run 'supactl config use-context <name>' to set a context.\n")
+		}
+		exit(1)
 	}
 
+	p, err := newProvider(config.CurrentContext, ctx)
+	if errors.Is(err, errMissingCredentials) {
+		fmt.Fprintf(os.Stderr, "Error: Current context '%s' is a remote context but is missing credentials.\n", config.CurrentContext)
+		fmt.Fprintf(os.Stderr, "Run 'supactl login <server_url>' or 'supactl config set-context %s --server=<url> --api-key=<key>'\n", config.CurrentContext)
+		exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+
+	return p
+}
+
+// commandExecutor runs the external commands of local instances (docker, psql)
+var commandExecutor local.CommandExecutor = local.OSExecutor{}
+
+// newProvider creates the provider for a named context
+func newProvider(name string, ctx *auth.ContextConfig) (provider.InstanceProvider, error) {
+	return newProviderWithExecutor(name, ctx, commandExecutor, supabaseVersion())
+}
+
+// newProviderWithExecutor creates the provider for a named context, running the docker
+// commands of local contexts through executor and checking out version for new local instances.
+// It does not read supactl.yaml, so it is safe to call from several goroutines.
+func newProviderWithExecutor(name string, ctx *auth.ContextConfig, executor local.CommandExecutor, version string) (provider.InstanceProvider, error) {
 	switch ctx.Provider {
 	case provider.ProviderTypeRemote:
 		if ctx.ServerURL == "" || ctx.APIKey == "" {
-			fmt.Fprintf(os.Stderr, "Error: Current context '%s' is a remote context but is missing credentials.\n", config.CurrentContext)
-			fmt.Fprintf(os.Stderr, "Run 'supactl login <server_url>' or 'supactl config set-context %s --server=<url> --api-key=<key>'\n", config.CurrentContext)
-			os.Exit(1)
+			return nil, errMissingCredentials
 		}
-		return provider.NewRemoteProvider(ctx.ServerURL, ctx.APIKey)
+		return provider.NewRemoteProvider(ctx.ServerURL, ctx.APIKey), nil
 
 	case provider.ProviderTypeLocal:
 		localProvider, err := provider.NewLocalProvider()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Failed to initialize local provider: %v\n", err)
-			os.Exit(1)
+			return nil, fmt.Errorf("failed to initialize local provider: %w", err)
+		}
+		localProvider.SupabaseVersion = version
+		localProvider.Runner, err = local.NewComposeRunner(ctx.DockerRunner, ctx.DockerSocket, executor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid docker runner in context '%s': %w", name, err)
 		}
-		return localProvider
+		return localProvider, nil
 
 	default:
-		fmt.Fprintf(os.Stderr, "Error: Unknown provider type '%s' in context '%s'\n", ctx.Provider, config.CurrentContext)
-		os.Exit(1)
-		return nil
+		return nil, fmt.Errorf("unknown provider type '%s' in context '%s'", ctx.Provider, name)
 	}
 }
 
@@ -84,13 +157,13 @@ func getAPIClient() *api.Client {
 	config, err := auth.LoadConfig()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: You are not logged in. Please run 'supactl login <server_url>' first.\n")
-		os.Exit(1)
+		exit(1)
 	}
 
 	ctx, err := config.GetCurrentContext()
 	if err != nil || ctx.Provider != provider.ProviderTypeRemote {
 		fmt.Fprintf(os.Stderr, "Error: Current context is not a remote context. Please run 'supactl login <server_url>' first.\n")
-		os.Exit(1)
+		exit(1)
 	}
 
 	return api.NewClient(ctx.ServerURL, ctx.APIKey)
@@ -8,24 +8,50 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var startServices []string
+
 // startCmd represents the start command
 var startCmd = &cobra.Command{
-	Use:   "start <instance-name>",
+	Use:   "start [instance-name]",
 	Short: "Start a Supabase instance",
 	Long: `Start a stopped Supabase instance.
 
 This command works with both remote and local instances based on your current context.
-Use 'supactl config use-context <name>' to switch between contexts.`,
-	Args: cobra.ExactArgs(1),
+Use 'supactl config use-context <name>' to switch between contexts.
+
+Without an instance name, the instance linked to the current directory is used.
+
+For local instances, --service starts only the given services (and the
+services they depend on), e.g. one stopped with 'supactl stop --service'.
+
+Examples:
+  supactl start my-project
+  supactl start my-project --service studio,kong`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstances,
 	Run: func(cmd *cobra.Command, args []string) {
-		instanceName := strings.TrimSpace(args[0])
-		provider := getProvider()
+		ref := instanceArg(args)
+		provider, instanceName := resolveInstanceRef(ref)
+		applyProjectEnv(provider, ref)
+
+		if len(startServices) > 0 {
+			sp := getServiceProvider(provider, "--service")
+			fmt.Printf("Starting %s of instance '%s'...\n", strings.Join(startServices, ", "), instanceName)
+
+			if err := sp.StartServices(instanceName, startServices); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to start services: %v\n", err)
+				exit(1)
+			}
+
+			fmt.Printf("Successfully started %s of instance '%s'\n", strings.Join(startServices, ", "), instanceName)
+			return
+		}
 
 		fmt.Printf("Starting instance '%s'...\n", instanceName)
 
 		if err := provider.StartInstance(instanceName); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to start instance: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("Successfully started instance '%s'\n", instanceName)
@@ -34,4 +60,5 @@ Use 'supactl config use-context <name>' to switch between contexts.`,
 
 func init() {
 	rootCmd.AddCommand(startCmd)
+	startCmd.Flags().StringSliceVar(&startServices, "service", nil, "Only start these services of a local instance (comma-separated or repeatable)")
 }
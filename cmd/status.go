@@ -4,35 +4,29 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/qubitquilt/supactl/internal/link"
 	"github.com/spf13/cobra"
 )
 
 // statusCmd represents the status command
 var statusCmd = &cobra.Command{
-	Use:   "status",
+	Use:   "status [instance-name]",
 	Short: "Show status of linked project",
 	Long: `Show detailed status and information about the linked project.
 
-This command requires the current directory to be linked to a project (remote mode only).
-Run 'supactl link' first if you haven't already.
+Without an instance name, this command requires the current directory to be
+linked to a project. Run 'supactl link' first if you haven't already.
 
 Note: For a kubectl-style alternative, use 'supactl describe <instance-name>' instead.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstances,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Get the linked project name
-		projectName, err := link.GetLink()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-
-		provider := getProvider()
+		provider, projectName := resolveInstanceRef(instanceArg(args))
 
 		// Fetch instance details
 		instance, err := provider.GetInstance(projectName)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to get instance details: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Display instance information
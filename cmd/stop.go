@@ -8,24 +8,48 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var stopServices []string
+
 // stopCmd represents the stop command
 var stopCmd = &cobra.Command{
-	Use:   "stop <instance-name>",
+	Use:   "stop [instance-name]",
 	Short: "Stop a running Supabase instance",
 	Long: `Stop a running Supabase instance.
 
 This command works with both remote and local instances based on your current context.
-The instance data will be preserved and can be started again later.`,
-	Args: cobra.ExactArgs(1),
+The instance data will be preserved and can be started again later. Local
+containers are stopped but not removed; use 'supactl down' to remove them.
+
+Without an instance name, the instance linked to the current directory is used.
+
+For local instances, --service stops only the given services.
+
+Examples:
+  supactl stop my-project
+  supactl stop my-project --service realtime`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstances,
 	Run: func(cmd *cobra.Command, args []string) {
-		instanceName := strings.TrimSpace(args[0])
-		provider := getProvider()
+		provider, instanceName := resolveInstanceRef(instanceArg(args))
+
+		if len(stopServices) > 0 {
+			sp := getServiceProvider(provider, "--service")
+			fmt.Printf("Stopping %s of instance '%s'...\n", strings.Join(stopServices, ", "), instanceName)
+
+			if err := sp.StopServices(instanceName, stopServices); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to stop services: %v\n", err)
+				exit(1)
+			}
+
+			fmt.Printf("Successfully stopped %s of instance '%s'\n", strings.Join(stopServices, ", "), instanceName)
+			return
+		}
 
 		fmt.Printf("Stopping instance '%s'...\n", instanceName)
 
 		if err := provider.StopInstance(instanceName); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to stop instance: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("Successfully stopped instance '%s'\n", instanceName)
@@ -34,4 +58,5 @@ The instance data will be preserved and can be started again later.`,
 
 func init() {
 	rootCmd.AddCommand(stopCmd)
+	stopCmd.Flags().StringSliceVar(&stopServices, "service", nil, "Only stop these services of a local instance (comma-separated or repeatable)")
 }
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/qubitquilt/supactl/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bucketPublic           bool
+	bucketFileSizeLimit    int64
+	bucketAllowedMimeTypes []string
+	bucketForce            bool
+	storageRecursive       bool
+	storageJobs            int
+)
+
+// storageCmd groups the Supabase Storage commands
+var storageCmd = &cobra.Command{
+	Use:   "storage",
+	Short: "Manage Supabase Storage buckets and objects",
+	Long: `Manage the Storage buckets and objects of an instance.
+
+Requests go through the instance's API gateway with the service role key:
+local instances read it from the project's .env file, remote instances use
+the key reported by the SupaControl server.
+
+Objects are addressed as <bucket>/<path>. In 'storage cp', prefix storage
+paths with ss:/// to tell them apart from local paths.
+
+Examples:
+  supactl storage buckets create my-project avatars --public
+  supactl storage cp my-project ./logo.png ss:///avatars/
+  supactl storage cp my-project ./public ss:///assets/site -r -j 8
+  supactl storage ls my-project avatars/users
+  supactl storage rm my-project avatars/users -r`,
+}
+
+var storageBucketsCmd = &cobra.Command{
+	Use:   "buckets",
+	Short: "Manage storage buckets",
+}
+
+var storageBucketsListCmd = &cobra.Command{
+	Use:   "list <instance>",
+	Short: "List the buckets of an instance",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getStorageClient(args[0])
+
+		buckets, err := client.ListBuckets()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to list buckets: %v\n", err)
+			exit(1)
+		}
+
+		if len(buckets) == 0 {
+			fmt.Println("No buckets found.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tPUBLIC\tFILE SIZE LIMIT\tCREATED")
+		for _, b := range buckets {
+			limit := "-"
+			if b.FileSizeLimit != nil {
+				limit = formatBytes(*b.FileSizeLimit)
+			}
+			fmt.Fprintf(w, "%s\t%t\t%s\t%s\n", b.Name, b.Public, limit, b.CreatedAt.Format("2006-01-02 15:04:05"))
+		}
+		w.Flush()
+	},
+}
+
+var storageBucketsCreateCmd = &cobra.Command{
+	Use:   "create <instance> <bucket>",
+	Short: "Create a bucket",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getStorageClient(args[0])
+
+		req := storage.CreateBucketRequest{Name: args[1], Public: bucketPublic, AllowedMimeTypes: bucketAllowedMimeTypes}
+		if bucketFileSizeLimit > 0 {
+			req.FileSizeLimit = &bucketFileSizeLimit
+		}
+
+		if err := client.CreateBucket(req); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to create bucket: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("Bucket '%s' created\n", args[1])
+	},
+}
+
+var storageBucketsDeleteCmd = &cobra.Command{
+	Use:   "delete <instance> <bucket>",
+	Short: "Delete a bucket",
+	Long: `Delete a bucket. Buckets must be empty unless --force is given,
+in which case all of their objects are deleted first.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getStorageClient(args[0])
+		bucket := args[1]
+
+		if bucketForce {
+			if err := client.EmptyBucket(bucket); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to empty bucket: %v\n", err)
+				exit(1)
+			}
+		}
+
+		if err := client.DeleteBucket(bucket); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to delete bucket: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("Bucket '%s' deleted\n", bucket)
+	},
+}
+
+var storageLsCmd = &cobra.Command{
+	Use:   "ls <instance> [bucket[/path]]",
+	Short: "List buckets, or the objects under a path",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getStorageClient(args[0])
+
+		if len(args) == 1 {
+			buckets, err := client.ListBuckets()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to list buckets: %v\n", err)
+				exit(1)
+			}
+			for _, b := range buckets {
+				fmt.Printf("%s/\n", b.Name)
+			}
+			return
+		}
+
+		bucket, prefix := parseStoragePath(args[1])
+
+		if storageRecursive {
+			paths, err := client.ListRecursive(bucket, prefix)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to list objects: %v\n", err)
+				exit(1)
+			}
+			for _, p := range paths {
+				fmt.Println(p)
+			}
+			return
+		}
+
+		objects, err := client.List(bucket, prefix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to list objects: %v\n", err)
+			exit(1)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		for _, o := range objects {
+			if o.IsFolder() {
+				fmt.Fprintf(w, "%s/\t\t\n", o.Name)
+				continue
+			}
+			updated := ""
+			if o.UpdatedAt != nil {
+				updated = o.UpdatedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", o.Name, formatBytes(o.Size()), updated)
+		}
+		w.Flush()
+	},
+}
+
+var storageCpCmd = &cobra.Command{
+	Use:   "cp <instance> <source> <destination>",
+	Short: "Copy files to or from storage",
+	Long: `Copy files between the local disk and storage. Exactly one of source and
+destination must be a storage path starting with ss:///.
+
+A destination ending in / keeps the source file name. With --recursive,
+directories are uploaded or downloaded with up to --jobs files in parallel.`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		src, dst := args[1], args[2]
+		if storage.IsRemote(src) == storage.IsRemote(dst) {
+			fmt.Fprintf(os.Stderr, "Error: exactly one of source and destination must start with %s\n", storage.RemotePrefix)
+			exit(1)
+		}
+
+		client := getStorageClient(args[0])
+
+		var tasks []storage.Task
+		var err error
+		if storage.IsRemote(dst) {
+			bucket, objectPath := parseStoragePath(dst)
+			if strings.HasSuffix(dst, "/") && objectPath != "" {
+				objectPath += "/"
+			}
+			tasks, err = storage.PlanUpload(src, bucket, objectPath, storageRecursive)
+		} else {
+			bucket, objectPath := parseStoragePath(src)
+			tasks, err = client.PlanDownload(bucket, objectPath, dst, storageRecursive)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		if len(tasks) == 0 {
+			fmt.Println("Nothing to copy.")
+			return
+		}
+
+		progress := func(done, total int, task storage.Task, err error) {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s: %v\n", done, total, task, err)
+				return
+			}
+			fmt.Printf("[%d/%d] %s\n", done, total, task)
+		}
+
+		if err := client.Transfer(tasks, storageJobs, progress); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+var storageRmCmd = &cobra.Command{
+	Use:   "rm <instance> <bucket/path>...",
+	Short: "Delete objects from storage",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getStorageClient(args[0])
+
+		// Group paths by bucket so each bucket takes one request
+		byBucket := make(map[string][]string)
+		var buckets []string
+		for _, arg := range args[1:] {
+			bucket, objectPath := parseStoragePath(arg)
+			if objectPath == "" && !storageRecursive {
+				fmt.Fprintf(os.Stderr, "Error: '%s' is a bucket; use --recursive to delete all of its objects\n", arg)
+				exit(1)
+			}
+
+			paths := []string{objectPath}
+			if storageRecursive {
+				nested, err := client.ListRecursive(bucket, objectPath)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: Failed to list objects: %v\n", err)
+					exit(1)
+				}
+				// A path without nested objects may be a single file
+				if len(nested) > 0 || objectPath == "" {
+					paths = nested
+				}
+			}
+			if len(paths) == 0 {
+				continue
+			}
+
+			if _, seen := byBucket[bucket]; !seen {
+				buckets = append(buckets, bucket)
+			}
+			byBucket[bucket] = append(byBucket[bucket], paths...)
+		}
+
+		for _, bucket := range buckets {
+			if err := client.Remove(bucket, byBucket[bucket]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to delete objects from '%s': %v\n", bucket, err)
+				exit(1)
+			}
+			for _, p := range byBucket[bucket] {
+				fmt.Printf("Deleted %s/%s\n", bucket, p)
+			}
+		}
+	},
+}
+
+// getStorageClient returns a Storage API client for an instance reference
+func getStorageClient(ref string) *storage.Client {
+	instance, key := getGatewayAccess(ref)
+	return storage.NewClient(instance.GatewayURL(), key)
+}
+
+// parseStoragePath splits a storage path into bucket and object path, exiting on failure
+func parseStoragePath(arg string) (string, string) {
+	bucket, objectPath, err := storage.ParsePath(arg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+	return bucket, objectPath
+}
+
+// formatBytes formats a size in bytes with a binary unit
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	rootCmd.AddCommand(storageCmd)
+	storageCmd.AddCommand(storageBucketsCmd, storageLsCmd, storageCpCmd, storageRmCmd)
+	storageBucketsCmd.AddCommand(storageBucketsListCmd, storageBucketsCreateCmd, storageBucketsDeleteCmd)
+
+	storageBucketsCreateCmd.Flags().BoolVar(&bucketPublic, "public", false, "Allow public read access without a token")
+	storageBucketsCreateCmd.Flags().Int64Var(&bucketFileSizeLimit, "file-size-limit", 0, "Maximum object size in bytes (0 for no limit)")
+	storageBucketsCreateCmd.Flags().StringSliceVar(&bucketAllowedMimeTypes, "allowed-mime-types", nil, "MIME types that may be uploaded (e.g. image/png,image/*)")
+	storageBucketsDeleteCmd.Flags().BoolVar(&bucketForce, "force", false, "Delete all objects of the bucket first")
+
+	for _, c := range []*cobra.Command{storageLsCmd, storageCpCmd, storageRmCmd} {
+		c.Flags().BoolVarP(&storageRecursive, "recursive", "r", false, "Include everything under the path")
+	}
+	storageCpCmd.Flags().IntVarP(&storageJobs, "jobs", "j", 4, "Number of files to copy in parallel")
+}
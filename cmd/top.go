@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/database"
+	"github.com/qubitquilt/supactl/internal/metrics"
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topWatch    bool
+	topInterval time.Duration
+	topOutput   string
+	topServices bool
+)
+
+// instanceUsage is a row of 'top instances': the metrics of an instance, or why they are missing
+type instanceUsage struct {
+	Name    string            `json:"name"`
+	Status  string            `json:"status"`
+	Metrics *metrics.Instance `json:"metrics,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// topCmd groups the resource usage commands (kubectl-style)
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Display CPU, memory, database size and connection usage",
+	Long: `Display the resource usage of instances (kubectl-style).
+
+Local instances aggregate 'docker stats' for the containers of the compose
+project, plus the Postgres database size and client connections. Remote
+instances read the metrics reported by the SupaControl server.
+
+Use --watch to refresh the display every --interval until interrupted.
+
+Examples:
+  supactl top instances
+  supactl top instance my-project --services
+  supactl top instances --watch --interval 5s`,
+}
+
+var topInstancesCmd = &cobra.Command{
+	Use:   "instances",
+	Short: "Display the resource usage of every running instance",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTopOutput()
+		p := getProvider()
+		metricsProvider := getMetricsProvider(p)
+
+		watchTop(func(w io.Writer) error {
+			instances, err := p.ListInstances()
+			if err != nil {
+				return fmt.Errorf("failed to list instances: %w", err)
+			}
+
+			rows := collectUsage(p, metricsProvider, instances)
+			if topOutput == "json" {
+				return writeJSONTo(w, rows)
+			}
+			renderTopInstances(w, rows)
+			return nil
+		})
+	},
+}
+
+var topInstanceCmd = &cobra.Command{
+	Use:   "instance [instance-name]",
+	Short: "Display the resource usage of an instance",
+	Long: `Display the resource usage of an instance.
+
+Use --services for a breakdown per service (db, kong, auth, ...).
+Without an instance name, the instance linked to the current directory is used.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstances,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTopOutput()
+		p, instanceName := resolveInstanceRef(instanceArg(args))
+		metricsProvider := getMetricsProvider(p)
+
+		watchTop(func(w io.Writer) error {
+			instance, err := p.GetInstance(instanceName)
+			if err != nil {
+				return fmt.Errorf("failed to get instance details: %w", err)
+			}
+
+			row := collectUsage(p, metricsProvider, []provider.Instance{*instance})[0]
+			if topOutput == "json" {
+				return writeJSONTo(w, row)
+			}
+			renderTopInstance(w, row, topServices)
+			return nil
+		})
+	},
+}
+
+// checkTopOutput validates the --output and --interval flags
+func checkTopOutput() {
+	if topOutput != "table" && topOutput != "json" {
+		fmt.Fprintf(os.Stderr, "Error: Unknown output format '%s' (use table or json)\n", topOutput)
+		exit(1)
+	}
+	if topWatch && topInterval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --interval must be positive\n")
+		exit(1)
+	}
+}
+
+// getMetricsProvider returns p as a MetricsProvider, exiting if it cannot report metrics
+func getMetricsProvider(p provider.InstanceProvider) provider.MetricsProvider {
+	metricsProvider, ok := p.(provider.MetricsProvider)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: The %s provider does not support metrics\n", p.ProviderType())
+		exit(1)
+	}
+	return metricsProvider
+}
+
+// watchTop renders once, or with --watch every --interval on a cleared screen until interrupted.
+// Each frame is rendered to a buffer first so the screen does not flicker.
+func watchTop(render func(w io.Writer) error) {
+	for {
+		var buf bytes.Buffer
+		err := render(&buf)
+		if err != nil && !topWatch {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+
+		if topWatch && topOutput == "table" {
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("Every %s: supactl %s    %s\n\n", topInterval, strings.Join(os.Args[1:], " "), time.Now().Format("15:04:05"))
+		}
+		if err != nil {
+			fmt.Fprintf(&buf, "Error: %v\n", err)
+		}
+		os.Stdout.Write(buf.Bytes())
+
+		if !topWatch {
+			return
+		}
+		time.Sleep(topInterval)
+	}
+}
+
+// collectUsage fetches the metrics of the running instances concurrently. Database usage
+// of local instances is queried directly, since docker stats does not report it.
+func collectUsage(p provider.InstanceProvider, metricsProvider provider.MetricsProvider, instances []provider.Instance) []instanceUsage {
+	rows := make([]instanceUsage, len(instances))
+
+	var wg sync.WaitGroup
+	for i := range instances {
+		instance := instances[i]
+		rows[i] = instanceUsage{Name: instance.Name, Status: instance.Status}
+		if !strings.EqualFold(instance.Status, "running") {
+			continue
+		}
+
+		wg.Add(1)
+		go func(row *instanceUsage) {
+			defer wg.Done()
+
+			m, err := metricsProvider.GetMetrics(instance.Name)
+			if err != nil {
+				row.Error = err.Error()
+				return
+			}
+			if m.Database == nil && p.ProviderType() == provider.ProviderTypeLocal {
+				m.Database = queryDatabaseStats(&instance)
+			}
+			row.Metrics = m
+		}(&rows[i])
+	}
+	wg.Wait()
+
+	return rows
+}
+
+// queryDatabaseStats connects to an instance's database and returns its usage, or nil if it is unreachable
+func queryDatabaseStats(instance *provider.Instance) *metrics.Database {
+	info, err := database.ForInstance(instance)
+	if err != nil {
+		return nil
+	}
+	conn, err := database.Open(info)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	stats, err := database.Stats(conn)
+	if err != nil {
+		return nil
+	}
+	return stats
+}
+
+// renderTopInstances writes the 'top instances' table
+func renderTopInstances(w io.Writer, rows []instanceUsage) {
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No instances found.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATUS\tCPU\tMEMORY\tDB-SIZE\tCONNECTIONS")
+	for _, row := range rows {
+		if row.Metrics == nil {
+			status := row.Status
+			if row.Error != "" {
+				status = "error: " + row.Error
+			}
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t-\n", row.Name, status)
+			continue
+		}
+
+		size, connections := formatDatabaseUsage(row.Metrics.Database)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.Name,
+			row.Status,
+			formatCPU(row.Metrics.CPUPercent),
+			formatMemory(row.Metrics.MemoryBytes, row.Metrics.MemoryLimitBytes),
+			size,
+			connections,
+		)
+	}
+	tw.Flush()
+}
+
+// renderTopInstance writes the usage of one instance, with a table of its services if requested
+func renderTopInstance(w io.Writer, row instanceUsage, services bool) {
+	if row.Error != "" {
+		fmt.Fprintf(w, "Error: %s\n", row.Error)
+		return
+	}
+	if row.Metrics == nil {
+		fmt.Fprintf(w, "Instance '%s' is %s\n", row.Name, row.Status)
+		return
+	}
+
+	m := row.Metrics
+	size, connections := formatDatabaseUsage(m.Database)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "Instance:\t%s\n", row.Name)
+	fmt.Fprintf(tw, "CPU:\t%s\n", formatCPU(m.CPUPercent))
+	fmt.Fprintf(tw, "Memory:\t%s\n", formatMemory(m.MemoryBytes, m.MemoryLimitBytes))
+	fmt.Fprintf(tw, "Database Size:\t%s\n", size)
+	fmt.Fprintf(tw, "Connections:\t%s\n", connections)
+	tw.Flush()
+
+	if !services || len(m.Services) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "SERVICE\tCPU\tMEMORY")
+	for _, svc := range m.Services {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", svc.Name, formatCPU(svc.CPUPercent), formatMemory(svc.MemoryBytes, 0))
+	}
+	tw.Flush()
+}
+
+// formatCPU formats a CPU percentage; it exceeds 100% when more than one core is busy
+func formatCPU(percent float64) string {
+	return fmt.Sprintf("%.1f%%", percent)
+}
+
+// formatMemory formats memory usage, with the limit if it is known
+func formatMemory(used, limit uint64) string {
+	if limit == 0 {
+		return metrics.FormatBytes(used)
+	}
+	return fmt.Sprintf("%s / %s", metrics.FormatBytes(used), metrics.FormatBytes(limit))
+}
+
+// formatDatabaseUsage formats the database size and connections, "-" if unknown
+func formatDatabaseUsage(db *metrics.Database) (string, string) {
+	if db == nil {
+		return "-", "-"
+	}
+	return metrics.FormatBytes(db.SizeBytes), fmt.Sprintf("%d", db.Connections)
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+	topCmd.AddCommand(topInstancesCmd)
+	topCmd.AddCommand(topInstanceCmd)
+
+	topCmd.PersistentFlags().BoolVarP(&topWatch, "watch", "w", false, "Refresh the display until interrupted")
+	topCmd.PersistentFlags().DurationVar(&topInterval, "interval", 2*time.Second, "Refresh interval with --watch")
+	topCmd.PersistentFlags().StringVarP(&topOutput, "output", "o", "table", "Output format: table or json")
+	topInstanceCmd.Flags().BoolVar(&topServices, "services", false, "Show the usage of each service")
+}
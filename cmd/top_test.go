@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/metrics"
+	"github.com/qubitquilt/supactl/internal/provider"
+)
+
+// fakeMetricsProvider reports fixed metrics; instances without an entry fail
+type fakeMetricsProvider struct {
+	provider.InstanceProvider
+	metrics map[string]*metrics.Instance
+}
+
+func (f *fakeMetricsProvider) ProviderType() string { return provider.ProviderTypeRemote }
+
+func (f *fakeMetricsProvider) GetMetrics(name string) (*metrics.Instance, error) {
+	if m, ok := f.metrics[name]; ok {
+		return m, nil
+	}
+	return nil, errors.New("metrics unavailable")
+}
+
+func TestCollectUsage(t *testing.T) {
+	p := &fakeMetricsProvider{metrics: map[string]*metrics.Instance{
+		"app": {
+			Name:        "app",
+			CPUPercent:  12.5,
+			MemoryBytes: 256 << 20,
+			Database:    &metrics.Database{SizeBytes: 64 << 20, Connections: 3},
+		},
+	}}
+	instances := []provider.Instance{
+		{Name: "app", Status: "running"},
+		{Name: "broken", Status: "running"},
+		{Name: "idle", Status: "stopped"},
+	}
+
+	rows := collectUsage(p, p, instances)
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %+v", rows)
+	}
+	if rows[0].Metrics == nil || rows[1].Error != "metrics unavailable" || rows[2].Metrics != nil || rows[2].Error != "" {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+
+	var buf bytes.Buffer
+	renderTopInstances(&buf, rows)
+	output := buf.String()
+	for _, want := range []string{"NAME", "12.5%", "256.0MiB", "64.0MiB", "error: metrics unavailable", "stopped"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderTopInstance(t *testing.T) {
+	row := instanceUsage{Name: "app", Status: "running", Metrics: &metrics.Instance{
+		CPUPercent:       3,
+		MemoryBytes:      1 << 30,
+		MemoryLimitBytes: 4 << 30,
+		Services:         []metrics.Service{{Name: "db", CPUPercent: 2, MemoryBytes: 512 << 20}},
+	}}
+
+	var buf bytes.Buffer
+	renderTopInstance(&buf, row, false)
+	if strings.Contains(buf.String(), "SERVICE") || !strings.Contains(buf.String(), "1.0GiB / 4.0GiB") {
+		t.Errorf("unexpected output without --services:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	renderTopInstance(&buf, row, true)
+	if !strings.Contains(buf.String(), "SERVICE") || !strings.Contains(buf.String(), "512.0MiB") {
+		t.Errorf("unexpected output with --services:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/audit"
+	"github.com/qubitquilt/supactl/internal/auth"
+	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/qubitquilt/supactl/internal/provider"
+	"github.com/qubitquilt/supactl/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	uiContexts []string
+	uiInterval time.Duration
+	uiTimeout  time.Duration
+)
+
+// uiCmd opens the interactive dashboard
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Open an interactive dashboard of instances across contexts",
+	Long: `Open a full-screen dashboard listing the instances of every context.
+
+Status and health are refreshed every --interval. Health is checked by
+calling the API gateway of running instances.
+
+Keys:
+  ↑/↓, j/k     Move the selection
+  s, x, r      Start, stop or restart the selected instance
+  d            Delete the selected instance (asks for confirmation)
+  l, enter     Toggle a pane tailing the instance's logs
+  o            Open Studio in the browser
+  R            Refresh now
+  q, ctrl+c    Quit
+
+Examples:
+  supactl ui
+  supactl ui --context local,production --interval 10s`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := auth.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to load configuration: %v\n", err)
+			exit(1)
+		}
+
+		contexts := uiContexts
+		if len(contexts) == 0 {
+			contexts = config.ListContexts()
+			sort.Strings(contexts)
+		}
+		if len(contexts) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: No contexts configured. Run 'supactl login' or 'supactl config set-context' first.\n")
+			exit(1)
+		}
+		for _, name := range contexts {
+			if _, exists := config.Contexts[name]; !exists {
+				fmt.Fprintf(os.Stderr, "Error: Context '%s' does not exist\n", name)
+				exit(1)
+			}
+		}
+		if uiInterval <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --interval must be positive\n")
+			exit(1)
+		}
+
+		// Providers are opened from the dashboard's goroutines, so supactl.yaml is read up front
+		version := supabaseVersion()
+
+		backend := &tui.Backend{
+			Contexts: contexts,
+			Open: func(name string) (provider.InstanceProvider, error) {
+				// Command output would corrupt the dashboard; failures carry it in their error
+				return newProviderWithExecutor(name, config.Contexts[name], local.QuietExecutor{}, version)
+			},
+			Timeout: uiTimeout,
+			Health:  tui.CheckHealth,
+			OpenURL: tui.OpenBrowser,
+			Audit: func(row tui.Row, action string, err error, duration time.Duration) {
+				record := audit.Record{
+					Time:       time.Now().Add(-duration).UTC(),
+					User:       audit.CurrentUser(),
+					Context:    row.Context,
+					Provider:   config.Contexts[row.Context].Provider,
+					Instance:   row.Instance.Name,
+					Action:     action,
+					Result:     audit.ResultSuccess,
+					DurationMS: duration.Milliseconds(),
+				}
+				if err != nil {
+					record.Result, record.Error = audit.ResultFailure, err.Error()
+				}
+				// Warnings would corrupt the dashboard; a failed write is dropped
+				appendAudit(record)
+			},
+		}
+
+		if err := tui.Run(backend, uiInterval); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+	uiCmd.Flags().StringSliceVar(&uiContexts, "context", nil, "Comma-separated contexts to show (default: all)")
+	uiCmd.Flags().DurationVar(&uiInterval, "interval", 5*time.Second, "Refresh interval")
+	uiCmd.Flags().DurationVar(&uiTimeout, "timeout", 10*time.Second, "Per-context timeout when listing instances")
+}
@@ -11,24 +11,23 @@ import (
 // unlinkCmd represents the unlink command
 var unlinkCmd = &cobra.Command{
 	Use:   "unlink",
-	Short: "Unlink current directory from remote instance",
-	Long: `Unlink the current directory from its linked remote Supabase instance.
+	Short: "Unlink current directory from its instance",
+	Long: `Unlink the current directory from its linked Supabase instance.
 
 This will remove the .supacontrol/project file from the current directory.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if !link.IsLinked() {
+		l, err := link.Load()
+		if err != nil {
 			fmt.Println("This directory is not linked to any project.")
 			return
 		}
 
-		projectName, _ := link.GetLink()
-
 		if err := link.ClearLink(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to unlink: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
-		fmt.Printf("Successfully unlinked from '%s'\n", projectName)
+		fmt.Printf("Successfully unlinked from '%s'\n", l.Ref())
 	},
 }
 
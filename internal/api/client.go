@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -242,3 +244,180 @@ func (c *Client) GetLogs(name string, lines int) (string, error) {
 
 	return string(bodyBytes), nil
 }
+
+// GetInstanceConfig retrieves the environment configuration of an instance
+func (c *Client) GetInstanceConfig(name string) (map[string]string, error) {
+	endpoint := fmt.Sprintf("/api/v1/instances/%s/config", name)
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var configResp InstanceConfigResponse
+	if err := json.NewDecoder(resp.Body).Decode(&configResp); err != nil {
+		return nil, fmt.Errorf("failed to parse config response: %w", err)
+	}
+
+	if configResp.Config == nil {
+		configResp.Config = make(map[string]string)
+	}
+
+	return configResp.Config, nil
+}
+
+// UpdateInstanceConfig sets and unsets environment configuration keys of an instance
+func (c *Client) UpdateInstanceConfig(name string, set map[string]string, unset []string) error {
+	endpoint := fmt.Sprintf("/api/v1/instances/%s/config", name)
+	reqBody := UpdateInstanceConfigRequest{Set: set, Unset: unset}
+
+	resp, err := c.makeRequest("PATCH", endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return c.handleErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// SetInstanceLabels replaces the labels of an instance
+func (c *Client) SetInstanceLabels(name string, labels map[string]string) error {
+	endpoint := fmt.Sprintf("/api/v1/instances/%s/labels", name)
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	reqBody := SetInstanceLabelsRequest{Labels: labels}
+
+	resp, err := c.makeRequest("PUT", endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return c.handleErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// ListFunctions retrieves the edge functions deployed to an instance
+func (c *Client) ListFunctions(name string) ([]Function, error) {
+	endpoint := fmt.Sprintf("/api/v1/instances/%s/functions", name)
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var listResp ListFunctionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("failed to parse functions list: %w", err)
+	}
+
+	return listResp.Functions, nil
+}
+
+// DeployFunction uploads the files of an edge function, replacing any previous version
+func (c *Client) DeployFunction(name, function string, files []FunctionFile) error {
+	endpoint := fmt.Sprintf("/api/v1/instances/%s/functions/%s", name, function)
+	reqBody := DeployFunctionRequest{Files: files}
+
+	resp, err := c.makeRequest("PUT", endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return c.handleErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// DeleteFunction deletes an edge function from an instance
+func (c *Client) DeleteFunction(name, function string) error {
+	endpoint := fmt.Sprintf("/api/v1/instances/%s/functions/%s", name, function)
+	resp, err := c.makeRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return c.handleErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// GetInstanceMetrics retrieves the current resource usage of an instance
+func (c *Client) GetInstanceMetrics(name string) (*InstanceMetrics, error) {
+	endpoint := fmt.Sprintf("/api/v1/instances/%s/metrics", name)
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var metrics InstanceMetrics
+	if err := json.NewDecoder(resp.Body).Decode(&metrics); err != nil {
+		return nil, fmt.Errorf("failed to parse instance metrics: %w", err)
+	}
+
+	return &metrics, nil
+}
+
+// ListAuditEvents retrieves the server-side audit trail. An empty instance, a zero since
+// and a non-positive limit are not sent, so the server applies its defaults.
+func (c *Client) ListAuditEvents(instance string, since time.Time, limit int) ([]AuditEvent, error) {
+	query := url.Values{}
+	if instance != "" {
+		query.Set("instance", instance)
+	}
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	endpoint := "/api/v1/audit"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var listResp ListAuditEventsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("failed to parse audit events: %w", err)
+	}
+
+	return listResp.Events, nil
+}
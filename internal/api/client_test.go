@@ -3,7 +3,9 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/qubitquilt/supactl/internal/testutil"
 )
@@ -702,6 +704,218 @@ func TestGetLogs(t *testing.T) {
 	}
 }
 
+func TestGetInstanceConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		response   interface{}
+		want       map[string]string
+		wantErr    bool
+	}{
+		{
+			name:       "get config successfully",
+			statusCode: http.StatusOK,
+			response: InstanceConfigResponse{
+				Config: map[string]string{"SITE_URL": "https://app.example.com", "JWT_EXPIRY": "3600"},
+			},
+			want: map[string]string{"SITE_URL": "https://app.example.com", "JWT_EXPIRY": "3600"},
+		},
+		{
+			name:       "empty config",
+			statusCode: http.StatusOK,
+			response:   map[string]interface{}{},
+			want:       map[string]string{},
+		},
+		{
+			name:       "instance not found",
+			statusCode: http.StatusNotFound,
+			response: ErrorResponse{
+				Error:   "Not Found",
+				Message: "Instance not found",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewMockServer()
+			defer server.Close()
+
+			server.On("GET", "/api/v1/instances/my-project/config", func(w http.ResponseWriter, r *http.Request) {
+				testutil.RespondJSON(w, tt.statusCode, tt.response)
+			})
+
+			client := NewClient(server.URL(), "test-key")
+			config, err := client.GetInstanceConfig("my-project")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetInstanceConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(config, tt.want) {
+				t.Errorf("GetInstanceConfig() = %v, want %v", config, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateInstanceConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		set        map[string]string
+		unset      []string
+		statusCode int
+		wantErr    bool
+	}{
+		{
+			name:       "set values",
+			set:        map[string]string{"SMTP_HOST": "smtp.example.com"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "unset values",
+			unset:      []string{"SMTP_HOST"},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "invalid key",
+			set:        map[string]string{"NOT_A_KEY": "x"},
+			statusCode: http.StatusBadRequest,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewMockServer()
+			defer server.Close()
+
+			server.On("PATCH", "/api/v1/instances/my-project/config", func(w http.ResponseWriter, r *http.Request) {
+				var req UpdateInstanceConfigRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				if !reflect.DeepEqual(req.Set, tt.set) || !reflect.DeepEqual(req.Unset, tt.unset) {
+					t.Errorf("request = %+v, want set=%v unset=%v", req, tt.set, tt.unset)
+				}
+
+				if tt.wantErr {
+					testutil.RespondError(w, tt.statusCode, "Unknown configuration key")
+					return
+				}
+				w.WriteHeader(tt.statusCode)
+			})
+
+			client := NewClient(server.URL(), "test-key")
+			err := client.UpdateInstanceConfig("my-project", tt.set, tt.unset)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateInstanceConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetInstanceLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		wantLabels map[string]string
+		statusCode int
+		wantErr    bool
+	}{
+		{
+			name:       "replace labels",
+			labels:     map[string]string{"team": "core"},
+			wantLabels: map[string]string{"team": "core"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "clear labels",
+			labels:     nil,
+			wantLabels: map[string]string{},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "instance not found",
+			labels:     map[string]string{"team": "core"},
+			wantLabels: map[string]string{"team": "core"},
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewMockServer()
+			defer server.Close()
+
+			server.On("PUT", "/api/v1/instances/my-project/labels", func(w http.ResponseWriter, r *http.Request) {
+				var req SetInstanceLabelsRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				if !reflect.DeepEqual(req.Labels, tt.wantLabels) {
+					t.Errorf("labels = %v, want %v", req.Labels, tt.wantLabels)
+				}
+
+				if tt.wantErr {
+					testutil.RespondError(w, tt.statusCode, "Instance not found")
+					return
+				}
+				w.WriteHeader(tt.statusCode)
+			})
+
+			client := NewClient(server.URL(), "test-key")
+			err := client.SetInstanceLabels("my-project", tt.labels)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetInstanceLabels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+
+	server.On("GET", "/api/v1/instances/my-project/functions", func(w http.ResponseWriter, r *http.Request) {
+		testutil.RespondJSON(w, http.StatusOK, ListFunctionsResponse{Functions: []Function{{Name: "hello"}}})
+	})
+	server.On("PUT", "/api/v1/instances/my-project/functions/hello", func(w http.ResponseWriter, r *http.Request) {
+		var req DeployFunctionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if len(req.Files) != 1 || req.Files[0].Path != "hello/index.ts" || string(req.Files[0].Content) != "Deno.serve()" {
+			t.Errorf("unexpected files: %+v", req.Files)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server.On("DELETE", "/api/v1/instances/my-project/functions/missing", func(w http.ResponseWriter, r *http.Request) {
+		testutil.RespondError(w, http.StatusNotFound, "Function not found")
+	})
+
+	client := NewClient(server.URL(), "test-key")
+
+	functions, err := client.ListFunctions("my-project")
+	if err != nil || len(functions) != 1 || functions[0].Name != "hello" {
+		t.Errorf("ListFunctions() = %+v, %v", functions, err)
+	}
+
+	files := []FunctionFile{{Path: "hello/index.ts", Content: []byte("Deno.serve()")}}
+	if err := client.DeployFunction("my-project", "hello", files); err != nil {
+		t.Errorf("DeployFunction() error = %v", err)
+	}
+
+	if err := client.DeleteFunction("my-project", "missing"); err == nil || err.Error() != "Function not found" {
+		t.Errorf("DeleteFunction() error = %v, want 'Function not found'", err)
+	}
+}
+
 func TestHandleErrorResponse(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -772,3 +986,60 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGetInstanceMetrics(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+
+	server.On("GET", "/api/v1/instances/my-project/metrics", func(w http.ResponseWriter, r *http.Request) {
+		testutil.RespondJSON(w, http.StatusOK, InstanceMetrics{
+			CPUPercent:  12.5,
+			MemoryBytes: 1 << 30,
+			Services:    []ServiceMetrics{{Name: "db", CPUPercent: 10, MemoryBytes: 512 << 20}},
+			Database:    &DatabaseMetrics{SizeBytes: 64 << 20, Connections: 7},
+		})
+	})
+	server.On("GET", "/api/v1/instances/missing/metrics", func(w http.ResponseWriter, r *http.Request) {
+		testutil.RespondError(w, http.StatusNotFound, "Instance not found")
+	})
+
+	client := NewClient(server.URL(), "test-key")
+
+	metrics, err := client.GetInstanceMetrics("my-project")
+	if err != nil {
+		t.Fatalf("GetInstanceMetrics() error = %v", err)
+	}
+	if metrics.CPUPercent != 12.5 || len(metrics.Services) != 1 || metrics.Database == nil || metrics.Database.Connections != 7 {
+		t.Errorf("unexpected metrics: %+v", metrics)
+	}
+
+	if _, err := client.GetInstanceMetrics("missing"); err == nil || err.Error() != "Instance not found" {
+		t.Errorf("GetInstanceMetrics() error = %v, want 'Instance not found'", err)
+	}
+}
+
+func TestListAuditEvents(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+
+	server.On("GET", "/api/v1/audit", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("instance") != "my-project" || query.Get("since") != "2026-01-02T10:00:00Z" || query.Get("limit") != "10" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		testutil.RespondJSON(w, http.StatusOK, ListAuditEventsResponse{Events: []AuditEvent{
+			{Time: "2026-01-02T11:00:00Z", User: "alice", Instance: "my-project", Action: "delete", Result: "success"},
+		}})
+	})
+
+	client := NewClient(server.URL(), "test-key")
+
+	since := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	events, err := client.ListAuditEvents("my-project", since, 10)
+	if err != nil {
+		t.Fatalf("ListAuditEvents() error = %v", err)
+	}
+	if len(events) != 1 || events[0].User != "alice" || events[0].Action != "delete" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
@@ -2,15 +2,16 @@ package api
 
 // Instance represents a Supabase instance managed by SupaControl
 type Instance struct {
-	Name        string `json:"name"`
-	Status      string `json:"status"`
-	StudioURL   string `json:"studio_url"`
-	APIURL      string `json:"api_url"`
-	KongURL     string `json:"kong_url"`
-	AnonKey     string `json:"anon_key,omitempty"`
-	ServiceKey  string `json:"service_key,omitempty"`
-	DatabaseURL string `json:"database_url,omitempty"`
-	CreatedAt   string `json:"created_at,omitempty"`
+	Name        string            `json:"name"`
+	Status      string            `json:"status"`
+	StudioURL   string            `json:"studio_url"`
+	APIURL      string            `json:"api_url"`
+	KongURL     string            `json:"kong_url"`
+	AnonKey     string            `json:"anon_key,omitempty"`
+	ServiceKey  string            `json:"service_key,omitempty"`
+	DatabaseURL string            `json:"database_url,omitempty"`
+	CreatedAt   string            `json:"created_at,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
 }
 
 // ErrorResponse represents an error response from the API
@@ -39,3 +40,81 @@ type AuthResponse struct {
 	} `json:"user"`
 	Authenticated bool `json:"authenticated"`
 }
+
+// InstanceConfigResponse represents the response from the instance config endpoint
+type InstanceConfigResponse struct {
+	Config map[string]string `json:"config"`
+}
+
+// UpdateInstanceConfigRequest represents a request to change an instance's configuration
+type UpdateInstanceConfigRequest struct {
+	Set   map[string]string `json:"set,omitempty"`
+	Unset []string          `json:"unset,omitempty"`
+}
+
+// SetInstanceLabelsRequest represents a request to replace an instance's labels
+type SetInstanceLabelsRequest struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// Function represents an edge function deployed to an instance
+type Function struct {
+	Name      string `json:"name"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// ListFunctionsResponse represents the response from the list functions endpoint
+type ListFunctionsResponse struct {
+	Functions []Function `json:"functions"`
+}
+
+// FunctionFile is a source file of an edge function; Content is base64-encoded in JSON
+type FunctionFile struct {
+	Path    string `json:"path"`
+	Content []byte `json:"content"`
+}
+
+// DeployFunctionRequest represents a request to create or replace an edge function
+type DeployFunctionRequest struct {
+	Files []FunctionFile `json:"files"`
+}
+
+// ServiceMetrics is the resource usage of one service of an instance
+type ServiceMetrics struct {
+	Name             string  `json:"name"`
+	CPUPercent       float64 `json:"cpu_percent"`
+	MemoryBytes      uint64  `json:"memory_bytes"`
+	MemoryLimitBytes uint64  `json:"memory_limit_bytes,omitempty"`
+}
+
+// DatabaseMetrics is the usage of an instance's Postgres database
+type DatabaseMetrics struct {
+	SizeBytes   uint64 `json:"size_bytes"`
+	Connections int    `json:"connections"`
+}
+
+// InstanceMetrics represents the response from the instance metrics endpoint
+type InstanceMetrics struct {
+	CPUPercent       float64          `json:"cpu_percent"`
+	MemoryBytes      uint64           `json:"memory_bytes"`
+	MemoryLimitBytes uint64           `json:"memory_limit_bytes,omitempty"`
+	Services         []ServiceMetrics `json:"services,omitempty"`
+	Database         *DatabaseMetrics `json:"database,omitempty"`
+	CollectedAt      string           `json:"collected_at,omitempty"`
+}
+
+// AuditEvent is an operation recorded in the server-side audit trail
+type AuditEvent struct {
+	Time       string `json:"time"`
+	User       string `json:"user"`
+	Instance   string `json:"instance,omitempty"`
+	Action     string `json:"action"`
+	Result     string `json:"result"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+}
+
+// ListAuditEventsResponse represents the response from the audit endpoint
+type ListAuditEventsResponse struct {
+	Events []AuditEvent `json:"events"`
+}
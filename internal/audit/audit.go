@@ -0,0 +1,172 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const auditFile = ".supacontrol/audit.jsonl"
+
+// Results of an audited operation
+const (
+	ResultSuccess   = "success"
+	ResultFailure   = "failure"
+	ResultCancelled = "cancelled"
+)
+
+// Record is one audited operation, stored as a line of JSON
+type Record struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	Context  string    `json:"context,omitempty"`
+	Provider string    `json:"provider,omitempty"`
+	Instance string    `json:"instance,omitempty"`
+	Action   string    `json:"action"`
+	Result   string    `json:"result"`
+	Error    string    `json:"error,omitempty"`
+	// DurationMS is the run time of the operation in milliseconds
+	DurationMS int64 `json:"duration_ms"`
+}
+
+// Duration returns the run time of the operation
+func (r *Record) Duration() time.Duration {
+	return time.Duration(r.DurationMS) * time.Millisecond
+}
+
+// Filter selects records; zero fields match everything
+type Filter struct {
+	Instance string
+	Context  string
+	Since    time.Time
+	// Limit keeps only the most recent records
+	Limit int
+}
+
+// Match reports whether a record is selected by the filter, ignoring Limit
+func (f Filter) Match(r Record) bool {
+	if f.Instance != "" && r.Instance != f.Instance {
+		return false
+	}
+	if f.Context != "" && r.Context != f.Context {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// GetAuditPath returns the path of the local audit log
+func GetAuditPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, auditFile), nil
+}
+
+// CurrentUser returns the name of the OS user running supactl
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// Append adds a record to the audit log at path. Each record is written with a
+// single append, so concurrent supactl processes do not interleave lines.
+func Append(path string, r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Read returns the records of the audit log at path selected by the filter, oldest first.
+// A missing log has no records; malformed lines are skipped.
+func Read(path string, filter Filter) ([]Record, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		if filter.Match(r) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return Limit(records, filter.Limit), nil
+}
+
+// Limit sorts records oldest first and keeps the most recent n; n <= 0 keeps all
+func Limit(records []Record, n int) []Record {
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	if n > 0 && len(records) > n {
+		records = records[len(records)-n:]
+	}
+	return records
+}
+
+// ParseSince parses a --since value: a duration before now such as "90m", "24h" or "7d",
+// a date (2006-01-02) or an RFC 3339 timestamp
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time '%s' (use a duration like 24h or 7d, a date, or an RFC 3339 timestamp)", value)
+}
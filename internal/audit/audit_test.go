@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "audit.jsonl")
+	base := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	records := []Record{
+		{Time: base.Add(2 * time.Hour), User: "alice", Context: "local", Provider: "local", Instance: "app", Action: "delete", Result: ResultSuccess, DurationMS: 1500},
+		{Time: base, User: "bob", Context: "prod", Provider: "remote", Instance: "app", Action: "start", Result: ResultFailure, Error: "exit status 1"},
+		{Time: base.Add(time.Hour), User: "bob", Context: "local", Instance: "other", Action: "db reset", Result: ResultCancelled},
+	}
+	for _, r := range records {
+		if err := Append(path, r); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("audit log permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	all, err := Read(path, Filter{})
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(all) != 3 || all[0].User != "bob" || all[2].Action != "delete" {
+		t.Fatalf("records should be sorted oldest first: %+v", all)
+	}
+	if all[2].Duration() != 1500*time.Millisecond {
+		t.Errorf("Duration() = %s", all[2].Duration())
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"instance", Filter{Instance: "app"}, 2},
+		{"context", Filter{Context: "local"}, 2},
+		{"since", Filter{Since: base.Add(30 * time.Minute)}, 2},
+		{"instance and context", Filter{Instance: "app", Context: "prod"}, 1},
+		{"limit keeps the most recent", Filter{Limit: 1}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(path, tt.filter)
+			if err != nil || len(got) != tt.want {
+				t.Errorf("Read() = %d records, %v; want %d", len(got), err, tt.want)
+			}
+		})
+	}
+
+	latest, _ := Read(path, Filter{Limit: 1})
+	if latest[0].Action != "delete" {
+		t.Errorf("Limit should keep the latest record, got %+v", latest)
+	}
+}
+
+func TestRead_MissingAndMalformed(t *testing.T) {
+	dir := t.TempDir()
+
+	records, err := Read(filepath.Join(dir, "missing.jsonl"), Filter{})
+	if err != nil || len(records) != 0 {
+		t.Errorf("missing log: %v, %v", records, err)
+	}
+
+	path := filepath.Join(dir, "audit.jsonl")
+	content := "not json\n" + `{"time":"2026-01-02T10:00:00Z","user":"alice","action":"create","result":"success","duration_ms":5}` + "\n\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err = Read(path, Filter{})
+	if err != nil || len(records) != 1 || records[0].User != "alice" {
+		t.Errorf("malformed lines should be skipped: %+v, %v", records, err)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]time.Time{
+		"90m":                  now.Add(-90 * time.Minute),
+		"24h":                  now.Add(-24 * time.Hour),
+		"7d":                   now.AddDate(0, 0, -7),
+		"2026-03-01":           time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2026-03-01T08:00:00Z": time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+	}
+	for input, want := range tests {
+		got, err := ParseSince(input, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "yesterday", "-1h", "xd"} {
+		if _, err := ParseSince(input, now); err == nil {
+			t.Errorf("ParseSince(%q) expected error", input)
+		}
+	}
+}
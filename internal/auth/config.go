@@ -14,9 +14,11 @@ const (
 
 // ContextConfig represents the configuration for a single context
 type ContextConfig struct {
-	Provider  string `json:"provider"`            // "local" or "remote"
-	ServerURL string `json:"server_url,omitempty"` // Only for remote contexts
-	APIKey    string `json:"api_key,omitempty"`    // Only for remote contexts
+	Provider     string `json:"provider"`                // "local" or "remote"
+	ServerURL    string `json:"server_url,omitempty"`    // Only for remote contexts
+	APIKey       string `json:"api_key,omitempty"`       // Only for remote contexts
+	DockerRunner string `json:"docker_runner,omitempty"` // Only for local contexts: "cli" (default) or "api"
+	DockerSocket string `json:"docker_socket,omitempty"` // Only for local contexts using the "api" runner
 }
 
 // Config represents the complete configuration with multiple contexts
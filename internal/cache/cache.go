@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+const cacheDir = ".supacontrol/cache"
+
+// unsafeKeyChars matches characters that are replaced in cache file names
+var unsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// Store keeps JSON values on disk for a limited time
+type Store struct {
+	Dir string
+	TTL time.Duration
+	// now returns the current time; overridden in tests
+	now func() time.Time
+}
+
+// entry is the file format of a cached value
+type entry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// GetCacheDir returns the directory of the on-disk cache
+func GetCacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, cacheDir), nil
+}
+
+// NewStore creates a store in the default cache directory whose values expire after ttl
+func NewStore(ttl time.Duration) (*Store, error) {
+	dir, err := GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{Dir: dir, TTL: ttl, now: time.Now}, nil
+}
+
+// Get decodes the value stored under key into v. It reports false if the
+// value is missing, expired or unreadable.
+func (s *Store) Get(key string, v interface{}) bool {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false
+	}
+	if s.currentTime().Sub(e.StoredAt) > s.TTL {
+		return false
+	}
+
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Set stores v under key
+func (s *Store) Set(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache value: %w", err)
+	}
+
+	data, err := json.Marshal(entry{StoredAt: s.currentTime(), Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(s.Dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the value stored under key
+func (s *Store) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete cache entry: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of a key
+func (s *Store) path(key string) string {
+	return filepath.Join(s.Dir, unsafeKeyChars.ReplaceAllString(key, "_")+".json")
+}
+
+// currentTime returns the store's notion of now
+func (s *Store) currentTime() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
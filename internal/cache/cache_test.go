@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T, now *time.Time) *Store {
+	t.Helper()
+	return &Store{Dir: filepath.Join(t.TempDir(), "cache"), TTL: time.Minute, now: func() time.Time { return *now }}
+}
+
+func TestStore_SetGet(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := newTestStore(t, &now)
+
+	if err := store.Set("instances-local", []string{"alpha", "beta"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	var got []string
+	if !store.Get("instances-local", &got) {
+		t.Fatal("expected cache hit")
+	}
+	if len(got) != 2 || got[0] != "alpha" || got[1] != "beta" {
+		t.Errorf("unexpected value: %v", got)
+	}
+
+	if store.Get("instances-other", &got) {
+		t.Error("expected cache miss for unknown key")
+	}
+}
+
+func TestStore_Expiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := newTestStore(t, &now)
+
+	if err := store.Set("key", "value"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	now = now.Add(59 * time.Second)
+	var got string
+	if !store.Get("key", &got) || got != "value" {
+		t.Errorf("expected cache hit before TTL, got %q", got)
+	}
+
+	now = now.Add(2 * time.Second)
+	if store.Get("key", &got) {
+		t.Error("expected cache miss after TTL")
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := newTestStore(t, &now)
+
+	if err := store.Set("key", 1); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := store.Delete("key"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	var got int
+	if store.Get("key", &got) {
+		t.Error("expected cache miss after delete")
+	}
+	if err := store.Delete("key"); err != nil {
+		t.Errorf("deleting a missing key should not fail: %v", err)
+	}
+}
+
+func TestStore_UnsafeKey(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := newTestStore(t, &now)
+
+	if err := store.Set("instances-../../prod ctx", "x"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(store.Dir)
+	if err != nil {
+		t.Fatalf("failed to read cache dir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "instances-.._.._prod_ctx.json" {
+		t.Errorf("unexpected cache files: %v", entries)
+	}
+}
+
+func TestStore_CorruptEntry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := newTestStore(t, &now)
+
+	if err := os.MkdirAll(store.Dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(store.path("key"), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var got string
+	if store.Get("key", &got) {
+		t.Error("expected cache miss for corrupt entry")
+	}
+}
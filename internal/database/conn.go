@@ -0,0 +1,117 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	// Registers the "postgres" driver
+	_ "github.com/lib/pq"
+
+	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/qubitquilt/supactl/internal/provider"
+)
+
+// ConnInfo describes how to reach an instance's Postgres database
+type ConnInfo struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	// SSLMode is passed to the driver as sslmode; empty leaves the driver default
+	SSLMode string
+
+	// Container is the db container of a local instance, used when no host psql is available
+	Container string
+}
+
+// ForInstance resolves the connection details of an instance. Remote instances use their
+// database URL; local instances read credentials from the project's .env file.
+func ForInstance(inst *provider.Instance) (*ConnInfo, error) {
+	if inst.DatabaseURL != "" {
+		return ParseURL(inst.DatabaseURL)
+	}
+
+	if inst.Directory != "" {
+		creds, err := local.GetDatabaseCredentials(inst.Name, inst.Directory)
+		if err != nil {
+			return nil, err
+		}
+		return &ConnInfo{
+			Host:      "localhost",
+			Port:      inst.DBPort,
+			User:      creds.User,
+			Password:  creds.Password,
+			Database:  creds.Database,
+			SSLMode:   "disable",
+			Container: creds.Container,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("instance '%s' does not expose a database URL", inst.Name)
+}
+
+// ParseURL parses a postgres:// connection URL
+func ParseURL(raw string) (*ConnInfo, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database URL: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return nil, fmt.Errorf("invalid database URL: unsupported scheme '%s'", u.Scheme)
+	}
+
+	info := &ConnInfo{
+		Host:     u.Hostname(),
+		Port:     5432,
+		Database: strings.TrimPrefix(u.Path, "/"),
+		SSLMode:  u.Query().Get("sslmode"),
+	}
+	if port := u.Port(); port != "" {
+		info.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("invalid database URL: bad port '%s'", port)
+		}
+	}
+	if u.User != nil {
+		info.User = u.User.Username()
+		info.Password, _ = u.User.Password()
+	}
+	if info.Database == "" {
+		info.Database = "postgres"
+	}
+
+	return info, nil
+}
+
+// URL returns the connection as a postgres:// URL
+func (c *ConnInfo) URL() string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(c.User, c.Password),
+		Host:   fmt.Sprintf("%s:%d", c.Host, c.Port),
+		Path:   "/" + c.Database,
+	}
+	if c.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": {c.SSLMode}}.Encode()
+	}
+	return u.String()
+}
+
+// Open connects to the database and verifies the connection
+func Open(info *ConnInfo) (*sql.DB, error) {
+	conn, err := sql.Open("postgres", info.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to %s:%d: %w", info.Host, info.Port, err)
+	}
+
+	return conn, nil
+}
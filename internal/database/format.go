@@ -0,0 +1,84 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteTable prints result sets as aligned tables, psql-style
+func WriteTable(w io.Writer, results []ResultSet) error {
+	for i, set := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		if len(set.Columns) == 0 {
+			fmt.Fprintln(w, "OK")
+			continue
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(set.Columns, "\t"))
+
+		separators := make([]string, len(set.Columns))
+		for j, column := range set.Columns {
+			separators[j] = strings.Repeat("-", len(column))
+		}
+		fmt.Fprintln(tw, strings.Join(separators, "\t"))
+
+		for _, row := range set.Rows {
+			cells := make([]string, len(row))
+			for j, value := range row {
+				cells[j] = formatCell(value)
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		if len(set.Rows) == 1 {
+			fmt.Fprintln(w, "(1 row)")
+		} else {
+			fmt.Fprintf(w, "(%d rows)\n", len(set.Rows))
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON prints result sets as JSON arrays of row objects. A single result set
+// is printed as one array; several are wrapped in an outer array.
+func WriteJSON(w io.Writer, results []ResultSet) error {
+	sets := make([][]map[string]interface{}, len(results))
+	for i, set := range results {
+		rows := make([]map[string]interface{}, 0, len(set.Rows))
+		for _, row := range set.Rows {
+			object := make(map[string]interface{}, len(set.Columns))
+			for j, column := range set.Columns {
+				object[column] = row[j]
+			}
+			rows = append(rows, object)
+		}
+		sets[i] = rows
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if len(sets) == 1 {
+		return encoder.Encode(sets[0])
+	}
+	return encoder.Encode(sets)
+}
+
+// formatCell renders a value for table output; NULL is shown as an empty cell like psql
+func formatCell(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return strings.ReplaceAll(fmt.Sprint(value), "\n", "\\n")
+}
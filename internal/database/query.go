@@ -0,0 +1,82 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ResultSet holds the rows returned by one statement. Statements that return no rows have no columns.
+type ResultSet struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Query runs one or more SQL statements and returns a result set per statement.
+// Statements run in a single round trip, so they share an implicit transaction.
+func Query(conn *sql.DB, query string) ([]ResultSet, error) {
+	rows, err := conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []ResultSet
+	for {
+		set, err := readResultSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, set)
+
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// readResultSet reads all rows of the current result set
+func readResultSet(rows *sql.Rows) (ResultSet, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return ResultSet{}, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	set := ResultSet{Columns: columns}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return ResultSet{}, fmt.Errorf("failed to read row: %w", err)
+		}
+
+		for i, value := range values {
+			values[i] = normalizeValue(value)
+		}
+		set.Rows = append(set.Rows, values)
+	}
+
+	return set, rows.Err()
+}
+
+// normalizeValue converts driver values into types that print and marshal naturally
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
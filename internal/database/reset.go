@@ -0,0 +1,134 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// SystemSchemas are the schemas owned by Supabase services. A reset keeps them intact.
+var SystemSchemas = map[string]bool{
+	"auth":               true,
+	"storage":            true,
+	"realtime":           true,
+	"_realtime":          true,
+	"supabase_functions": true,
+	"extensions":         true,
+	"graphql":            true,
+	"graphql_public":     true,
+	"pgbouncer":          true,
+	"pgsodium":           true,
+	"pgsodium_masks":     true,
+	"vault":              true,
+	"net":                true,
+	"cron":               true,
+	"_analytics":         true,
+	"_supavisor":         true,
+	"information_schema": true,
+}
+
+// IsSystemSchema reports whether a schema belongs to Postgres or Supabase
+func IsSystemSchema(name string) bool {
+	return SystemSchemas[name] || strings.HasPrefix(name, "pg_")
+}
+
+// applicationSchemasQuery lists schemas, leaving out those that hold an installed extension
+// (dropping them would take the extension with them). public is always reset.
+const applicationSchemasQuery = `SELECT n.nspname FROM pg_catalog.pg_namespace n
+WHERE n.nspname = 'public'
+   OR NOT EXISTS (SELECT 1 FROM pg_catalog.pg_extension e WHERE e.extnamespace = n.oid)`
+
+// ApplicationSchemas returns the schemas a reset drops: every schema that is not a system schema
+// or an extension's schema, including supabase_migrations so migrations are applied again from scratch
+func ApplicationSchemas(conn *sql.DB) ([]string, error) {
+	rows, err := conn.Query(applicationSchemasQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to list schemas: %w", err)
+		}
+		if !IsSystemSchema(name) {
+			schemas = append(schemas, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	sort.Strings(schemas)
+	return schemas, nil
+}
+
+// ResetSQL returns the statements that drop the given schemas and recreate an empty
+// public schema with the grants Supabase sets up for its API roles
+func ResetSQL(schemas []string) string {
+	var b strings.Builder
+	for _, schema := range schemas {
+		if IsSystemSchema(schema) {
+			continue
+		}
+		fmt.Fprintf(&b, "DROP SCHEMA IF EXISTS %s CASCADE;\n", pq.QuoteIdentifier(schema))
+	}
+
+	b.WriteString(`CREATE SCHEMA IF NOT EXISTS public;
+ALTER SCHEMA public OWNER TO pg_database_owner;
+GRANT USAGE ON SCHEMA public TO postgres, anon, authenticated, service_role;
+GRANT ALL ON ALL TABLES IN SCHEMA public TO postgres, anon, authenticated, service_role;
+GRANT ALL ON ALL ROUTINES IN SCHEMA public TO postgres, anon, authenticated, service_role;
+GRANT ALL ON ALL SEQUENCES IN SCHEMA public TO postgres, anon, authenticated, service_role;
+ALTER DEFAULT PRIVILEGES FOR ROLE postgres IN SCHEMA public GRANT ALL ON TABLES TO postgres, anon, authenticated, service_role;
+ALTER DEFAULT PRIVILEGES FOR ROLE postgres IN SCHEMA public GRANT ALL ON ROUTINES TO postgres, anon, authenticated, service_role;
+ALTER DEFAULT PRIVILEGES FOR ROLE postgres IN SCHEMA public GRANT ALL ON SEQUENCES TO postgres, anon, authenticated, service_role;
+`)
+
+	return b.String()
+}
+
+// Reset drops all application schemas and recreates an empty public schema in one transaction.
+// It returns the schemas that were dropped.
+func Reset(conn *sql.DB) ([]string, error) {
+	schemas, err := ApplicationSchemas(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err := tx.Exec(ResetSQL(schemas)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to reset schemas: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit reset: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// ExecFile runs the statements of a SQL file in one transaction
+func ExecFile(conn *sql.DB, script string) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
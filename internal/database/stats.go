@@ -0,0 +1,28 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/qubitquilt/supactl/internal/metrics"
+)
+
+// statsQuery returns the size of the current database and the number of other client
+// connections to it
+const statsQuery = `
+select
+  pg_database_size(current_database()),
+  (select count(*) from pg_stat_activity
+    where datname = current_database()
+      and backend_type = 'client backend'
+      and pid <> pg_backend_pid())`
+
+// Stats returns the size of the connected database and its client connections,
+// not counting the connection used to query them
+func Stats(conn *sql.DB) (*metrics.Database, error) {
+	var stats metrics.Database
+	if err := conn.QueryRow(statsQuery).Scan(&stats.SizeBytes, &stats.Connections); err != nil {
+		return nil, fmt.Errorf("failed to query database stats: %w", err)
+	}
+	return &stats, nil
+}
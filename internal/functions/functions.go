@@ -0,0 +1,253 @@
+package functions
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDir is where functions live in a linked directory, relative to its root
+var DefaultDir = filepath.Join("supabase", "functions")
+
+// RouterName is the function the self-hosted edge runtime uses to route requests
+// to the other functions. It is part of the stack and cannot be deployed or deleted.
+const RouterName = "main"
+
+// nameRegex matches valid function names
+var nameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// indexTemplate is the entrypoint written by New
+const indexTemplate = `// Edge function '%s'. Deploy it with: supactl functions deploy <instance> %s
+
+Deno.serve(async (req) => {
+  const { name } = await req.json().catch(() => ({ name: "world" }))
+
+  return new Response(JSON.stringify({ message: ` + "`Hello ${name}!`" + ` }), {
+    headers: { "Content-Type": "application/json" },
+  })
+})
+`
+
+// File is a source file of a function, with its path relative to the functions directory
+type File struct {
+	Path    string `json:"path"`
+	Content []byte `json:"content"`
+}
+
+// Source is a function ready to deploy. Files include the shared directories
+// (such as _shared) the function may import.
+type Source struct {
+	Name  string
+	Files []File
+}
+
+// Info describes a deployed function
+type Info struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ValidateName checks that a function name can be deployed
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid function name '%s': use letters, digits, '-' and '_', starting with a letter", name)
+	}
+	if name == RouterName {
+		return fmt.Errorf("'%s' is reserved for the edge runtime router", RouterName)
+	}
+	return nil
+}
+
+// New creates a function with an index.ts entrypoint in dir
+func New(dir, name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+
+	functionDir := filepath.Join(dir, name)
+	if _, err := os.Stat(functionDir); err == nil {
+		return "", fmt.Errorf("function '%s' already exists in %s", name, dir)
+	}
+
+	if err := os.MkdirAll(functionDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create function directory: %w", err)
+	}
+
+	index := filepath.Join(functionDir, "index.ts")
+	if err := os.WriteFile(index, []byte(fmt.Sprintf(indexTemplate, name, name)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", index, err)
+	}
+
+	return index, nil
+}
+
+// Discover returns the names of the functions in dir. Directories starting with
+// "_" or "." hold shared code and are not functions.
+func Discover(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("functions directory not found: %s", dir)
+		}
+		return nil, fmt.Errorf("failed to read functions directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && !isShared(entry.Name()) && entry.Name() != RouterName {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Load reads the given functions (all functions if names is empty) from dir
+func Load(dir string, names []string) ([]Source, error) {
+	if len(names) == 0 {
+		var err error
+		if names, err = Discover(dir); err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no functions found in %s", dir)
+		}
+	}
+
+	shared, err := sharedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]Source, 0, len(names))
+	for _, name := range names {
+		if err := ValidateName(name); err != nil {
+			return nil, err
+		}
+
+		files, err := readTree(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("function '%s' not found in %s", name, dir)
+		}
+
+		sources = append(sources, Source{Name: name, Files: append(files, shared...)})
+	}
+
+	return sources, nil
+}
+
+// Install writes functions into an edge runtime functions directory, replacing
+// previous versions so deleted files do not linger
+func Install(volumeDir string, sources []Source) error {
+	for _, source := range sources {
+		if err := os.RemoveAll(filepath.Join(volumeDir, source.Name)); err != nil {
+			return fmt.Errorf("failed to remove previous version of '%s': %w", source.Name, err)
+		}
+
+		for _, file := range source.Files {
+			target := filepath.Join(volumeDir, filepath.FromSlash(file.Path))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			if err := os.WriteFile(target, file.Content, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", file.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ListInstalled returns the functions in an edge runtime functions directory
+func ListInstalled(volumeDir string) ([]Info, error) {
+	names, err := Discover(volumeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]Info, 0, len(names))
+	for _, name := range names {
+		stat, err := os.Stat(filepath.Join(volumeDir, name))
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, Info{Name: name, UpdatedAt: stat.ModTime()})
+	}
+	return infos, nil
+}
+
+// Uninstall removes a function from an edge runtime functions directory
+func Uninstall(volumeDir, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(volumeDir, name)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("function '%s' not found", name)
+	}
+	return os.RemoveAll(dir)
+}
+
+// sharedFiles returns the files of the shared directories in dir
+func sharedFiles(dir string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read functions directory: %w", err)
+	}
+
+	var files []File
+	for _, entry := range entries {
+		if entry.IsDir() && isShared(entry.Name()) && !strings.HasPrefix(entry.Name(), ".") {
+			tree, err := readTree(dir, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, tree...)
+		}
+	}
+	return files, nil
+}
+
+// readTree reads all files below dir/name with slash-separated paths relative to dir
+func readTree(dir, name string) ([]File, error) {
+	root := filepath.Join(dir, name)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var files []File
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Path: path.Clean(filepath.ToSlash(rel)), Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read function '%s': %w", name, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// isShared reports whether a directory holds shared code rather than a function
+func isShared(name string) bool {
+	return strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".")
+}
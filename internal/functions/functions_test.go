@@ -0,0 +1,106 @@
+package functions
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/testutil"
+)
+
+func TestNewAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	index, err := New(dir, "hello")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if !strings.Contains(testutil.ReadFile(t, index), "Deno.serve") {
+		t.Errorf("unexpected template in %s", index)
+	}
+	if _, err := New(dir, "hello"); err == nil {
+		t.Error("expected an error for an existing function")
+	}
+
+	testutil.CreateTestFile(t, dir, filepath.Join("hello", "lib", "util.ts"), "export {}")
+	testutil.CreateTestFile(t, dir, filepath.Join("_shared", "cors.ts"), "export const cors = {}")
+	testutil.CreateTestFile(t, dir, filepath.Join("main", "index.ts"), "router")
+
+	names, err := Discover(dir)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"hello"}) {
+		t.Errorf("Discover() = %v, want [hello]", names)
+	}
+
+	sources, err := Load(dir, nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(sources) != 1 || sources[0].Name != "hello" {
+		t.Fatalf("Load() = %+v", sources)
+	}
+
+	var paths []string
+	for _, f := range sources[0].Files {
+		paths = append(paths, f.Path)
+	}
+	if want := []string{"hello/index.ts", "hello/lib/util.ts", "_shared/cors.ts"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("files = %v, want %v", paths, want)
+	}
+
+	if _, err := Load(dir, []string{"missing"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Load(missing) error = %v", err)
+	}
+	if _, err := Load(dir, []string{"main"}); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("Load(main) error = %v", err)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	volume := t.TempDir()
+	testutil.CreateTestFile(t, volume, filepath.Join("main", "index.ts"), "router")
+	testutil.CreateTestFile(t, volume, filepath.Join("hello", "stale.ts"), "old")
+
+	sources := []Source{{Name: "hello", Files: []File{
+		{Path: "hello/index.ts", Content: []byte("new")},
+		{Path: "_shared/cors.ts", Content: []byte("cors")},
+	}}}
+	if err := Install(volume, sources); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	if got := testutil.ReadFile(t, filepath.Join(volume, "hello", "index.ts")); got != "new" {
+		t.Errorf("hello/index.ts = %q, want new", got)
+	}
+	if testutil.FileExists(filepath.Join(volume, "hello", "stale.ts")) {
+		t.Error("stale file of the previous version was kept")
+	}
+	if !testutil.FileExists(filepath.Join(volume, "_shared", "cors.ts")) {
+		t.Error("shared file was not installed")
+	}
+
+	infos, err := ListInstalled(volume)
+	if err != nil {
+		t.Fatalf("ListInstalled failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Name != "hello" {
+		t.Errorf("ListInstalled() = %+v, want only hello", infos)
+	}
+
+	if err := Uninstall(volume, "main"); err == nil {
+		t.Error("the router must not be removable")
+	}
+	if err := Uninstall(volume, "hello"); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(volume, "hello")); !os.IsNotExist(err) {
+		t.Error("function directory still exists")
+	}
+	if err := Uninstall(volume, "hello"); err == nil {
+		t.Error("expected an error for a missing function")
+	}
+}
@@ -0,0 +1,224 @@
+package gotrue
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Client calls the GoTrue (Supabase Auth) admin API of an instance through its API gateway
+type Client struct {
+	BaseURL    string
+	ServiceKey string
+	HTTPClient *http.Client
+}
+
+// User is an auth user as returned by the admin API
+type User struct {
+	ID               string                 `json:"id"`
+	Email            string                 `json:"email,omitempty"`
+	Phone            string                 `json:"phone,omitempty"`
+	Role             string                 `json:"role,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	EmailConfirmedAt *time.Time             `json:"email_confirmed_at,omitempty"`
+	LastSignInAt     *time.Time             `json:"last_sign_in_at,omitempty"`
+	UserMetadata     map[string]interface{} `json:"user_metadata,omitempty"`
+	AppMetadata      map[string]interface{} `json:"app_metadata,omitempty"`
+}
+
+// CreateUserRequest is the body of a user creation request
+type CreateUserRequest struct {
+	Email        string                 `json:"email,omitempty"`
+	Phone        string                 `json:"phone,omitempty"`
+	Password     string                 `json:"password,omitempty"`
+	EmailConfirm bool                   `json:"email_confirm,omitempty"`
+	PhoneConfirm bool                   `json:"phone_confirm,omitempty"`
+	UserMetadata map[string]interface{} `json:"user_metadata,omitempty"`
+}
+
+// listUsersResponse is a page of the admin user list
+type listUsersResponse struct {
+	Users []User `json:"users"`
+}
+
+// errorResponse covers the error bodies of current and older GoTrue versions
+type errorResponse struct {
+	Msg              string `json:"msg"`
+	Message          string `json:"message"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// usersPageSize is the number of users requested per list call
+const usersPageSize = 100
+
+// uuidRegex matches user IDs
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// NewClient creates an admin API client for an instance's gateway URL
+func NewClient(gatewayURL, serviceKey string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(gatewayURL, "/") + "/auth/v1",
+		ServiceKey: serviceKey,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// ListUsers returns all users
+func (c *Client) ListUsers() ([]User, error) {
+	all := make([]User, 0)
+	for page := 1; ; page++ {
+		var resp listUsersResponse
+		endpoint := fmt.Sprintf("/admin/users?page=%d&per_page=%d", page, usersPageSize)
+		if err := c.do("GET", endpoint, nil, &resp); err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Users...)
+
+		if len(resp.Users) < usersPageSize {
+			return all, nil
+		}
+	}
+}
+
+// GetUser returns a user by ID
+func (c *Client) GetUser(id string) (*User, error) {
+	var user User
+	if err := c.do("GET", "/admin/users/"+url.PathEscape(id), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindUser returns a user by ID or email address
+func (c *Client) FindUser(idOrEmail string) (*User, error) {
+	if uuidRegex.MatchString(idOrEmail) {
+		return c.GetUser(idOrEmail)
+	}
+
+	users, err := c.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if strings.EqualFold(users[i].Email, idOrEmail) {
+			return &users[i], nil
+		}
+	}
+	return nil, fmt.Errorf("user '%s' not found", idOrEmail)
+}
+
+// CreateUser creates a user without sending any email
+func (c *Client) CreateUser(req CreateUserRequest) (*User, error) {
+	var user User
+	if err := c.do("POST", "/admin/users", req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdatePassword sets a user's password
+func (c *Client) UpdatePassword(id, password string) error {
+	return c.do("PUT", "/admin/users/"+url.PathEscape(id), map[string]string{"password": password}, nil)
+}
+
+// DeleteUser deletes a user
+func (c *Client) DeleteUser(id string) error {
+	return c.do("DELETE", "/admin/users/"+url.PathEscape(id), nil, nil)
+}
+
+// InviteUser creates a user and sends them an invitation email
+func (c *Client) InviteUser(email string, metadata map[string]interface{}) (*User, error) {
+	body := map[string]interface{}{"email": email}
+	if len(metadata) > 0 {
+		body["data"] = metadata
+	}
+
+	var user User
+	if err := c.do("POST", "/invite", body, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SendRecovery sends a password recovery email
+func (c *Client) SendRecovery(email string) error {
+	return c.do("POST", "/recover", map[string]string{"email": email}, nil)
+}
+
+// RecoveryLink generates a password recovery link without sending an email
+func (c *Client) RecoveryLink(email string) (string, error) {
+	var resp struct {
+		ActionLink string `json:"action_link"`
+	}
+	if err := c.do("POST", "/admin/generate_link", map[string]string{"type": "recovery", "email": email}, &resp); err != nil {
+		return "", err
+	}
+	return resp.ActionLink, nil
+}
+
+// do sends an authenticated request with an optional JSON body and decodes the response into out
+func (c *Client) do(method, endpoint string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.BaseURL+endpoint, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.ServiceKey)
+	req.Header.Set("apikey", c.ServiceKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseError(resp)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseError turns an error response into an error
+func parseError(resp *http.Response) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("HTTP %d: failed to read error response", resp.StatusCode)
+	}
+
+	var errResp errorResponse
+	if err := json.Unmarshal(data, &errResp); err == nil {
+		for _, msg := range []string{errResp.Msg, errResp.Message, errResp.ErrorDescription, errResp.Error} {
+			if msg != "" {
+				return fmt.Errorf("%s", msg)
+			}
+		}
+	}
+
+	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
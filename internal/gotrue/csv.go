@@ -0,0 +1,86 @@
+package gotrue
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseUsersCSV reads users to create from CSV with a header row. The columns email,
+// phone, password and email_confirm map to the request fields; any other column is
+// stored in user_metadata. Rows without email_confirm use autoConfirm.
+func ParseUsersCSV(r io.Reader, autoConfirm bool) ([]CreateUserRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("CSV is empty")
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+
+	hasIdentity := false
+	for _, column := range header {
+		hasIdentity = hasIdentity || column == "email" || column == "phone"
+	}
+	if !hasIdentity {
+		return nil, fmt.Errorf("CSV must have an email or phone column")
+	}
+
+	var users []CreateUserRequest
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		user := CreateUserRequest{EmailConfirm: autoConfirm}
+		for i, column := range header {
+			value := strings.TrimSpace(record[i])
+			switch column {
+			case "email":
+				user.Email = value
+			case "phone":
+				user.Phone = value
+				user.PhoneConfirm = autoConfirm
+			case "password":
+				user.Password = value
+			case "email_confirm":
+				if value == "" {
+					continue
+				}
+				confirm, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid email_confirm value '%s'", line, value)
+				}
+				user.EmailConfirm = confirm
+			default:
+				if value == "" {
+					continue
+				}
+				if user.UserMetadata == nil {
+					user.UserMetadata = make(map[string]interface{})
+				}
+				user.UserMetadata[column] = value
+			}
+		}
+
+		if user.Email == "" && user.Phone == "" {
+			return nil, fmt.Errorf("line %d: email or phone is required", line)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,116 @@
+package gotrue
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/testutil"
+)
+
+func TestClient_Users(t *testing.T) {
+	ms := testutil.NewMockServer()
+	defer ms.Close()
+
+	users := []User{
+		{ID: "7f0c6a4e-2b1f-4d4e-9a55-3f1f1b1b1b1b", Email: "Alice@example.com"},
+		{ID: "8a1d7b5f-3c2a-4e5f-8b66-4a2a2c2c2c2c", Email: "bob@example.com"},
+	}
+
+	ms.On("GET", "/auth/v1/admin/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer service-key" || r.Header.Get("apikey") != "service-key" {
+			testutil.RespondJSON(w, http.StatusUnauthorized, map[string]string{"msg": "Invalid JWT"})
+			return
+		}
+		testutil.RespondJSON(w, http.StatusOK, map[string]interface{}{"users": users})
+	})
+	ms.On("POST", "/auth/v1/admin/users", func(w http.ResponseWriter, r *http.Request) {
+		var req CreateUserRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Email == "taken@example.com" {
+			testutil.RespondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"code": 422, "error_code": "email_exists", "msg": "A user with this email address has already been registered",
+			})
+			return
+		}
+		testutil.RespondJSON(w, http.StatusOK, User{ID: "new-id", Email: req.Email})
+	})
+	ms.On("DELETE", "/auth/v1/admin/users/"+users[1].ID, func(w http.ResponseWriter, r *http.Request) {
+		testutil.RespondJSON(w, http.StatusOK, map[string]string{})
+	})
+	ms.On("POST", "/auth/v1/admin/generate_link", func(w http.ResponseWriter, r *http.Request) {
+		testutil.RespondJSON(w, http.StatusOK, map[string]string{"action_link": "http://localhost:8000/auth/v1/verify?token=abc&type=recovery"})
+	})
+
+	client := NewClient(ms.URL()+"/", "service-key")
+
+	list, err := client.ListUsers()
+	if err != nil {
+		t.Fatalf("ListUsers failed: %v", err)
+	}
+	if len(list) != 2 {
+		t.Errorf("ListUsers() returned %d users, want 2", len(list))
+	}
+
+	user, err := client.FindUser("alice@example.com")
+	if err != nil || user.ID != users[0].ID {
+		t.Errorf("FindUser(email) = %+v, %v", user, err)
+	}
+	if _, err := client.FindUser("nobody@example.com"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("FindUser(unknown) error = %v", err)
+	}
+
+	created, err := client.CreateUser(CreateUserRequest{Email: "carol@example.com", Password: "secret", EmailConfirm: true})
+	if err != nil || created.ID != "new-id" {
+		t.Errorf("CreateUser() = %+v, %v", created, err)
+	}
+	if _, err := client.CreateUser(CreateUserRequest{Email: "taken@example.com"}); err == nil || !strings.Contains(err.Error(), "already been registered") {
+		t.Errorf("CreateUser(taken) error = %v, want the API message", err)
+	}
+
+	if err := client.DeleteUser(users[1].ID); err != nil {
+		t.Errorf("DeleteUser failed: %v", err)
+	}
+
+	link, err := client.RecoveryLink("alice@example.com")
+	if err != nil || !strings.Contains(link, "type=recovery") {
+		t.Errorf("RecoveryLink() = %q, %v", link, err)
+	}
+}
+
+func TestParseUsersCSV(t *testing.T) {
+	content := `email, password, email_confirm, name
+alice@example.com, secret1, , Alice
+bob@example.com, secret2, false,
+`
+	users, err := ParseUsersCSV(strings.NewReader(content), true)
+	if err != nil {
+		t.Fatalf("ParseUsersCSV failed: %v", err)
+	}
+
+	want := []CreateUserRequest{
+		{Email: "alice@example.com", Password: "secret1", EmailConfirm: true, UserMetadata: map[string]interface{}{"name": "Alice"}},
+		{Email: "bob@example.com", Password: "secret2"},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("ParseUsersCSV() = %+v, want %+v", users, want)
+	}
+}
+
+func TestParseUsersCSV_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"":                                   "empty",
+		"name\nAlice\n":                      "email or phone column",
+		"email,name\n,Alice\n":               "line 2: email or phone is required",
+		"email,email_confirm\na@b.c,maybe\n": "invalid email_confirm",
+	}
+
+	for content, wantErr := range tests {
+		_, err := ParseUsersCSV(strings.NewReader(content), true)
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseUsersCSV(%q) error = %v, want %q", content, err, wantErr)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package link
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,8 +18,30 @@ func GetLinkPath() string {
 	return filepath.Join(".", linkDir, linkFile)
 }
 
-// SaveLink saves the project name to the local link file
-func SaveLink(projectName string) error {
+// Link records the instance a directory is linked to and the context it belongs to.
+// Links written by older versions hold only the instance name and have no context.
+type Link struct {
+	Context   string `json:"context,omitempty"`
+	Provider  string `json:"provider,omitempty"`
+	Instance  string `json:"instance"`
+	ServerURL string `json:"server_url,omitempty"`
+}
+
+// Ref returns the instance reference of the link: context/instance, or the bare
+// instance name for links without a context
+func (l *Link) Ref() string {
+	if l.Context == "" {
+		return l.Instance
+	}
+	return l.Context + "/" + l.Instance
+}
+
+// Save writes the link to the local link file
+func Save(l *Link) error {
+	if strings.TrimSpace(l.Instance) == "" {
+		return fmt.Errorf("instance name is required")
+	}
+
 	linkDirPath := filepath.Join(".", linkDir)
 
 	// Create link directory if it doesn't exist
@@ -26,8 +49,13 @@ func SaveLink(projectName string) error {
 		return fmt.Errorf("failed to create link directory: %w", err)
 	}
 
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal link: %w", err)
+	}
+
 	linkPath := GetLinkPath()
-	if err := os.WriteFile(linkPath, []byte(projectName), 0644); err != nil {
+	if err := os.WriteFile(linkPath, append(data, '\n'), 0644); err != nil {
 		return fmt.Errorf("failed to write link file: %w", err)
 	}
 
@@ -40,24 +68,46 @@ func SaveLink(projectName string) error {
 	return nil
 }
 
-// GetLink reads the project name from the local link file
-func GetLink() (string, error) {
+// Load reads the local link file, accepting both the JSON format and the bare
+// instance name written by older versions
+func Load() (*Link, error) {
 	linkPath := GetLinkPath()
 
 	data, err := os.ReadFile(linkPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", fmt.Errorf("no project linked. Run 'supactl link' to get started")
+			return nil, fmt.Errorf("no project linked. Run 'supactl link' to get started")
 		}
-		return "", fmt.Errorf("failed to read link file: %w", err)
+		return nil, fmt.Errorf("failed to read link file: %w", err)
 	}
 
-	projectName := strings.TrimSpace(string(data))
-	if projectName == "" {
-		return "", fmt.Errorf("link file is empty. Run 'supactl link' to link a project")
+	content := strings.TrimSpace(string(data))
+	if content == "" {
+		return nil, fmt.Errorf("link file is empty. Run 'supactl link' to link a project")
 	}
 
-	return projectName, nil
+	if !strings.HasPrefix(content, "{") {
+		return &Link{Instance: content}, nil
+	}
+
+	var l Link
+	if err := json.Unmarshal([]byte(content), &l); err != nil {
+		return nil, fmt.Errorf("failed to parse link file: %w", err)
+	}
+	if strings.TrimSpace(l.Instance) == "" {
+		return nil, fmt.Errorf("link file has no instance. Run 'supactl link' to link a project")
+	}
+
+	return &l, nil
+}
+
+// GetLink reads the linked instance name from the local link file
+func GetLink() (string, error) {
+	l, err := Load()
+	if err != nil {
+		return "", err
+	}
+	return l.Instance, nil
 }
 
 // ClearLink removes the local link file
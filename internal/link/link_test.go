@@ -7,7 +7,7 @@ import (
 	"testing"
 )
 
-func TestSaveLink(t *testing.T) {
+func TestSave(t *testing.T) {
 	// Create temporary directory for testing
 	tempDir, err := os.MkdirTemp("", "supactl-link-test-*")
 	if err != nil {
@@ -21,24 +21,24 @@ func TestSaveLink(t *testing.T) {
 	defer os.Chdir(oldWd)
 
 	tests := []struct {
-		name        string
-		projectName string
-		wantErr     bool
+		name    string
+		link    Link
+		wantErr bool
 	}{
 		{
-			name:        "valid project name",
-			projectName: "my-project",
-			wantErr:     false,
+			name:    "local context",
+			link:    Link{Context: "local", Provider: "local", Instance: "my-project"},
+			wantErr: false,
 		},
 		{
-			name:        "project with hyphens",
-			projectName: "my-test-project-v2",
-			wantErr:     false,
+			name:    "remote context with server URL",
+			link:    Link{Context: "prod", Provider: "remote", Instance: "my-test-project-v2", ServerURL: "https://supacontrol.example.com"},
+			wantErr: false,
 		},
 		{
-			name:        "empty project name",
-			projectName: "",
-			wantErr:     false, // SaveLink doesn't validate
+			name:    "empty instance name",
+			link:    Link{Context: "local"},
+			wantErr: true,
 		},
 	}
 
@@ -47,36 +47,96 @@ func TestSaveLink(t *testing.T) {
 			// Clean up link directory
 			os.RemoveAll(linkDir)
 
-			err := SaveLink(tt.projectName)
+			err := Save(&tt.link)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("SaveLink() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Save() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
 			if !tt.wantErr {
-				// Verify link file was created
-				linkPath := GetLinkPath()
-				if _, err := os.Stat(linkPath); os.IsNotExist(err) {
-					t.Errorf("link file was not created")
-					return
-				}
-
-				// Verify content
-				data, err := os.ReadFile(linkPath)
+				// Verify the link round-trips
+				got, err := Load()
 				if err != nil {
-					t.Errorf("failed to read link file: %v", err)
+					t.Errorf("Load() failed: %v", err)
 					return
 				}
 
-				content := strings.TrimSpace(string(data))
-				if content != tt.projectName {
-					t.Errorf("link content = %v, want %v", content, tt.projectName)
+				if *got != tt.link {
+					t.Errorf("Load() = %+v, want %+v", *got, tt.link)
 				}
 			}
 		})
 	}
 }
 
+func TestLoad_Formats(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "supactl-link-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tempDir)
+	defer os.Chdir(oldWd)
+
+	tests := []struct {
+		name    string
+		content string
+		want    Link
+		wantRef string
+		wantErr bool
+	}{
+		{
+			name:    "legacy bare name",
+			content: "legacy-project\n",
+			want:    Link{Instance: "legacy-project"},
+			wantRef: "legacy-project",
+		},
+		{
+			name:    "json",
+			content: `{"context": "prod", "provider": "remote", "instance": "app", "server_url": "https://sc.example.com"}`,
+			want:    Link{Context: "prod", Provider: "remote", Instance: "app", ServerURL: "https://sc.example.com"},
+			wantRef: "prod/app",
+		},
+		{
+			name:    "json without instance",
+			content: `{"context": "prod"}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed json",
+			content: `{"context": `,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.RemoveAll(linkDir)
+			os.MkdirAll(linkDir, 0755)
+			if err := os.WriteFile(GetLinkPath(), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+
+			got, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if *got != tt.want {
+				t.Errorf("Load() = %+v, want %+v", *got, tt.want)
+			}
+			if got.Ref() != tt.wantRef {
+				t.Errorf("Ref() = %s, want %s", got.Ref(), tt.wantRef)
+			}
+		})
+	}
+}
+
 func TestGetLink(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "supactl-link-test-*")
 	if err != nil {
@@ -97,7 +157,7 @@ func TestGetLink(t *testing.T) {
 		{
 			name: "valid link file",
 			setupFunc: func() error {
-				return SaveLink("test-project")
+				return Save(&Link{Context: "local", Instance: "test-project"})
 			},
 			wantProject: "test-project",
 			wantErr:     false,
@@ -126,9 +186,10 @@ func TestGetLink(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "link file with trailing newline",
+			name: "legacy link file with trailing newline",
 			setupFunc: func() error {
-				return SaveLink("project-with-newline\n")
+				os.MkdirAll(linkDir, 0755)
+				return os.WriteFile(GetLinkPath(), []byte("project-with-newline\n"), 0644)
 			},
 			wantProject: "project-with-newline",
 			wantErr:     false,
@@ -177,7 +238,7 @@ func TestClearLink(t *testing.T) {
 		{
 			name: "clear existing link",
 			setupFunc: func() error {
-				return SaveLink("test-project")
+				return Save(&Link{Context: "local", Instance: "test-project"})
 			},
 			wantErr: false,
 		},
@@ -234,7 +295,7 @@ func TestIsLinked(t *testing.T) {
 		{
 			name: "is linked",
 			setupFunc: func() error {
-				return SaveLink("project")
+				return Save(&Link{Instance: "project"})
 			},
 			want: true,
 		},
@@ -328,8 +389,8 @@ func TestAddToGitignore(t *testing.T) {
 				t.Fatalf("setup failed: %v", err)
 			}
 
-			// Call SaveLink which should trigger addToGitignore
-			SaveLink("test-project")
+			// Call Save which should trigger addToGitignore
+			Save(&Link{Instance: "test-project"})
 
 			// Check if .gitignore was modified correctly
 			if _, err := os.Stat(".gitignore"); err == nil {
@@ -0,0 +1,613 @@
+package local
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectLabel is the label added to every service supactl manages
+const ProjectLabel = "com.supactl.project"
+
+// ComposeFile is a structural, comment-preserving view of a docker-compose.yml document
+type ComposeFile struct {
+	doc *yaml.Node
+}
+
+// NewComposeFile creates an empty compose document
+func NewComposeFile() *ComposeFile {
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	return &ComposeFile{doc: &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}}
+}
+
+// LoadComposeFile reads and parses a docker-compose.yml file
+func LoadComposeFile(path string) (*ComposeFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return ParseComposeFile(data)
+}
+
+// ParseComposeFile parses docker-compose YAML content
+func ParseComposeFile(data []byte) (*ComposeFile, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse compose file: %w", err)
+	}
+
+	// An empty document is treated as an empty mapping
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse compose file: top level must be a mapping")
+	}
+
+	return &ComposeFile{doc: &doc}, nil
+}
+
+// Bytes serializes the compose document back to YAML
+func (c *ComposeFile) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.doc); err != nil {
+		return nil, fmt.Errorf("failed to encode compose file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode compose file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save writes the compose document to path
+func (c *ComposeFile) Save(path string) error {
+	data, err := c.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Services returns the service names in document order
+func (c *ComposeFile) Services() []string {
+	services := mappingValue(c.root(), "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	names := make([]string, 0, len(services.Content)/2)
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		names = append(names, services.Content[i].Value)
+	}
+	return names
+}
+
+// HasService reports whether the document defines the named service
+func (c *ComposeFile) HasService(name string) bool {
+	return c.service(name) != nil
+}
+
+// ContainerName returns the container_name of a service, or "" if unset
+func (c *ComposeFile) ContainerName(service string) string {
+	svc := c.service(service)
+	if svc == nil {
+		return ""
+	}
+	if node := mappingValue(svc, "container_name"); node != nil {
+		return node.Value
+	}
+	return ""
+}
+
+// SetContainerName sets the container_name of a service
+func (c *ComposeFile) SetContainerName(service, name string) error {
+	svc := c.service(service)
+	if svc == nil {
+		return fmt.Errorf("service '%s' not found", service)
+	}
+	setMappingValue(svc, "container_name", scalarNode(name))
+	return nil
+}
+
+// PublishedPort returns the published host port for a container port of a service.
+// The returned value may be an interpolation such as ${KONG_HTTP_PORT}.
+func (c *ComposeFile) PublishedPort(service string, target int) (string, bool) {
+	svc := c.service(service)
+	if svc == nil {
+		return "", false
+	}
+	ports := mappingValue(svc, "ports")
+	if ports == nil || ports.Kind != yaml.SequenceNode {
+		return "", false
+	}
+
+	for _, entry := range ports.Content {
+		switch entry.Kind {
+		case yaml.ScalarNode:
+			spec, err := parsePortSpec(entry.Value)
+			if err == nil && spec.target == target {
+				return spec.published, true
+			}
+		case yaml.MappingNode:
+			if t := mappingValue(entry, "target"); t != nil && t.Value == strconv.Itoa(target) {
+				if p := mappingValue(entry, "published"); p != nil {
+					return p.Value, true
+				}
+				return "", true
+			}
+		}
+	}
+	return "", false
+}
+
+// PublishesVariable reports whether a service publishes a port whose container side is
+// itself interpolated from the named variable (e.g. "${POSTGRES_PORT}:${POSTGRES_PORT}").
+// Such mappings follow the .env file rather than a fixed container port.
+func (c *ComposeFile) PublishesVariable(service, variable string) bool {
+	svc := c.service(service)
+	if svc == nil {
+		return false
+	}
+	ports := mappingValue(svc, "ports")
+	if ports == nil || ports.Kind != yaml.SequenceNode {
+		return false
+	}
+
+	for _, entry := range ports.Content {
+		if entry.Kind != yaml.ScalarNode {
+			continue
+		}
+		parts := splitPortSpec(entry.Value)
+		target, _, _ := strings.Cut(parts[len(parts)-1], "/")
+		if target == "${"+variable+"}" || strings.HasPrefix(target, "${"+variable+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPublishedPort rewrites the host side of every port mapping of a service that
+// targets the given container port. Both short ("8000:8000") and long (target/published)
+// syntax are supported, as are ${VAR} interpolations. It returns false if the service
+// has no mapping for the container port.
+func (c *ComposeFile) SetPublishedPort(service string, target, published int) (bool, error) {
+	svc := c.service(service)
+	if svc == nil {
+		return false, fmt.Errorf("service '%s' not found", service)
+	}
+	ports := mappingValue(svc, "ports")
+	if ports == nil || ports.Kind != yaml.SequenceNode {
+		return false, nil
+	}
+
+	updated := false
+	for _, entry := range ports.Content {
+		switch entry.Kind {
+		case yaml.ScalarNode:
+			spec, err := parsePortSpec(entry.Value)
+			if err != nil {
+				return false, fmt.Errorf("service '%s': %w", service, err)
+			}
+			if spec.target != target {
+				continue
+			}
+			spec.published = strconv.Itoa(published)
+			entry.Value = spec.String()
+			updated = true
+		case yaml.MappingNode:
+			t := mappingValue(entry, "target")
+			if t == nil || t.Value != strconv.Itoa(target) {
+				continue
+			}
+			setMappingValue(entry, "published", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: strconv.Itoa(published), Style: yaml.DoubleQuotedStyle})
+			updated = true
+		}
+	}
+
+	return updated, nil
+}
+
+// SetLabel sets a label on a service, supporting both map and list label syntax
+func (c *ComposeFile) SetLabel(service, key, value string) error {
+	svc := c.service(service)
+	if svc == nil {
+		return fmt.Errorf("service '%s' not found", service)
+	}
+
+	labels := mappingValue(svc, "labels")
+	if labels == nil {
+		labels = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(svc, "labels", labels)
+	}
+
+	switch labels.Kind {
+	case yaml.MappingNode:
+		setMappingValue(labels, key, scalarNode(value))
+	case yaml.SequenceNode:
+		entry := key + "=" + value
+		for _, item := range labels.Content {
+			if k, _, _ := strings.Cut(item.Value, "="); k == key {
+				item.Value = entry
+				return nil
+			}
+		}
+		labels.Content = append(labels.Content, scalarNode(entry))
+	default:
+		return fmt.Errorf("service '%s': unsupported labels format", service)
+	}
+
+	return nil
+}
+
+// Networks returns the names of the top-level networks, in file order
+func (c *ComposeFile) Networks() []string {
+	networks := mappingValue(c.root(), "networks")
+	if networks == nil || networks.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	names := make([]string, 0, len(networks.Content)/2)
+	for i := 0; i+1 < len(networks.Content); i += 2 {
+		names = append(names, networks.Content[i].Value)
+	}
+	return names
+}
+
+// NetworkName returns the explicit engine-level name of a top-level network, or "" when
+// compose derives it from the project name. External networks report "" as well, since
+// they are not created by compose and must keep their name.
+func (c *ComposeFile) NetworkName(network string) string {
+	def := mappingValue(mappingValue(c.root(), "networks"), network)
+	if def == nil || def.Kind != yaml.MappingNode {
+		return ""
+	}
+	if external := mappingValue(def, "external"); external != nil && external.Value != "false" {
+		return ""
+	}
+	if name := mappingValue(def, "name"); name != nil {
+		return name.Value
+	}
+	return ""
+}
+
+// SetNetworkName sets the engine-level name of a top-level network, creating it if needed
+func (c *ComposeFile) SetNetworkName(network, name string) {
+	networks := mappingValue(c.root(), "networks")
+	if networks == nil || networks.Kind != yaml.MappingNode {
+		networks = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(c.root(), "networks", networks)
+	}
+
+	def := mappingValue(networks, network)
+	if def == nil || def.Kind != yaml.MappingNode {
+		def = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(networks, network, def)
+	}
+	setMappingValue(def, "name", scalarNode(name))
+}
+
+// ServicesReferencing returns the services whose definition interpolates any of the given variables
+func (c *ComposeFile) ServicesReferencing(variables ...string) []string {
+	patterns := make([]*regexp.Regexp, len(variables))
+	for i, variable := range variables {
+		name := regexp.QuoteMeta(variable)
+		patterns[i] = regexp.MustCompile(`\$\{` + name + `[}:?-]|\$` + name + `\b`)
+	}
+
+	var services []string
+	for _, service := range c.Services() {
+		if nodeMatchesAny(c.service(service), patterns) {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// SetHeaderComment sets the comment written at the top of the document
+func (c *ComposeFile) SetHeaderComment(comment string) {
+	c.root().HeadComment = comment
+}
+
+// CopyServiceField copies a field of a service from src, creating the service if needed.
+// A non-empty tag (such as the "!override" merge directive) is set on the copied value.
+// It returns false if src does not define the field.
+func (c *ComposeFile) CopyServiceField(src *ComposeFile, service, key, tag string) bool {
+	from := src.service(service)
+	if from == nil {
+		return false
+	}
+	value := mappingValue(from, key)
+	if value == nil {
+		return false
+	}
+
+	copied := deepCopyNode(value)
+	if tag != "" {
+		copied.Tag = tag
+	}
+	setMappingValue(c.ensureService(service), key, copied)
+	return true
+}
+
+// DependsOn returns the services a service depends on, in either list or mapping syntax
+func (c *ComposeFile) DependsOn(service string) []string {
+	svc := c.service(service)
+	if svc == nil {
+		return nil
+	}
+
+	dependsOn := mappingValue(svc, "depends_on")
+	if dependsOn == nil {
+		return nil
+	}
+
+	var names []string
+	switch dependsOn.Kind {
+	case yaml.SequenceNode:
+		for _, entry := range dependsOn.Content {
+			names = append(names, entry.Value)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(dependsOn.Content); i += 2 {
+			names = append(names, dependsOn.Content[i].Value)
+		}
+	}
+	return names
+}
+
+// RemoveDependencies drops the named services from a service's depends_on, in either list or
+// mapping syntax. It returns true if any dependency was removed.
+func (c *ComposeFile) RemoveDependencies(service string, names map[string]bool) bool {
+	dependsOn := mappingValue(c.service(service), "depends_on")
+	if dependsOn == nil {
+		return false
+	}
+
+	removed := false
+	var kept []*yaml.Node
+	switch dependsOn.Kind {
+	case yaml.SequenceNode:
+		for _, entry := range dependsOn.Content {
+			if names[entry.Value] {
+				removed = true
+				continue
+			}
+			kept = append(kept, entry)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(dependsOn.Content); i += 2 {
+			if names[dependsOn.Content[i].Value] {
+				removed = true
+				continue
+			}
+			kept = append(kept, dependsOn.Content[i], dependsOn.Content[i+1])
+		}
+	}
+	dependsOn.Content = kept
+	return removed
+}
+
+// SetProfiles assigns a service to compose profiles, creating the service if needed
+func (c *ComposeFile) SetProfiles(service string, profiles ...string) {
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, profile := range profiles {
+		list.Content = append(list.Content, scalarNode(profile))
+	}
+	setMappingValue(c.ensureService(service), "profiles", list)
+}
+
+// SetResourceLimits sets the mem_limit and cpus of a service, creating the service if needed.
+// Empty limits are left unset.
+func (c *ComposeFile) SetResourceLimits(service string, resources ServiceResources) {
+	svc := c.ensureService(service)
+	if resources.Memory != "" {
+		setMappingValue(svc, "mem_limit", scalarNode(resources.Memory))
+	}
+	if resources.CPUs != "" {
+		// Untagged so the number is written plain rather than as a quoted string
+		setMappingValue(svc, "cpus", &yaml.Node{Kind: yaml.ScalarNode, Value: resources.CPUs})
+	}
+}
+
+// root returns the top-level mapping node
+func (c *ComposeFile) root() *yaml.Node {
+	return c.doc.Content[0]
+}
+
+// service returns the mapping node for a service, or nil if it does not exist
+func (c *ComposeFile) service(name string) *yaml.Node {
+	services := mappingValue(c.root(), "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		return nil
+	}
+	svc := mappingValue(services, name)
+	if svc == nil {
+		return nil
+	}
+	// A service declared with no body ("web:") is promoted to an empty mapping
+	if svc.Kind == yaml.ScalarNode && svc.Tag == "!!null" {
+		svc.Kind = yaml.MappingNode
+		svc.Tag = "!!map"
+		svc.Value = ""
+	}
+	if svc.Kind != yaml.MappingNode {
+		return nil
+	}
+	return svc
+}
+
+// ensureService returns the mapping node for a service, creating it if needed
+func (c *ComposeFile) ensureService(name string) *yaml.Node {
+	if svc := c.service(name); svc != nil {
+		return svc
+	}
+
+	services := mappingValue(c.root(), "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		services = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(c.root(), "services", services)
+	}
+
+	svc := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(services, name, svc)
+	return svc
+}
+
+// nodeMatchesAny reports whether any scalar in a node tree matches one of the patterns
+func nodeMatchesAny(node *yaml.Node, patterns []*regexp.Regexp) bool {
+	if node == nil {
+		return false
+	}
+	if node.Kind == yaml.ScalarNode {
+		for _, re := range patterns {
+			if re.MatchString(node.Value) {
+				return true
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if nodeMatchesAny(child, patterns) {
+			return true
+		}
+	}
+	return false
+}
+
+// deepCopyNode returns a copy of a node tree that shares nothing with the original
+func deepCopyNode(node *yaml.Node) *yaml.Node {
+	copied := *node
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = deepCopyNode(child)
+	}
+	return &copied
+}
+
+// mappingValue returns the value node for key in a mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue replaces or appends key in a mapping node, keeping any comments on the old value
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			old := node.Content[i+1]
+			value.LineComment = old.LineComment
+			value.HeadComment = old.HeadComment
+			value.FootComment = old.FootComment
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, scalarNode(key), value)
+}
+
+// scalarNode creates a plain string scalar node
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// portSpec is a parsed short-syntax port mapping ("[ip:][published:]target[/protocol]")
+type portSpec struct {
+	ip        string
+	published string
+	target    int
+	protocol  string
+}
+
+// parsePortSpec parses a short-syntax port mapping, keeping ${VAR} interpolations intact
+func parsePortSpec(value string) (portSpec, error) {
+	var spec portSpec
+
+	parts := splitPortSpec(value)
+	last := parts[len(parts)-1]
+	if target, proto, ok := strings.Cut(last, "/"); ok {
+		last = target
+		spec.protocol = proto
+	}
+
+	target, err := strconv.Atoi(last)
+	if err != nil {
+		// Port ranges and interpolated container ports are left untouched
+		return portSpec{target: -1}, nil
+	}
+	spec.target = target
+
+	switch len(parts) {
+	case 1:
+	case 2:
+		spec.published = parts[0]
+	case 3:
+		spec.ip = parts[0]
+		spec.published = parts[1]
+	default:
+		return spec, fmt.Errorf("invalid port mapping '%s'", value)
+	}
+
+	return spec, nil
+}
+
+// String formats the port mapping back to short syntax
+func (s portSpec) String() string {
+	var b strings.Builder
+	if s.ip != "" {
+		b.WriteString(s.ip)
+		b.WriteString(":")
+	}
+	if s.published != "" {
+		b.WriteString(s.published)
+		b.WriteString(":")
+	}
+	b.WriteString(strconv.Itoa(s.target))
+	if s.protocol != "" {
+		b.WriteString("/")
+		b.WriteString(s.protocol)
+	}
+	return b.String()
+}
+
+// splitPortSpec splits a port mapping on colons that are not inside ${...} or [...]
+func splitPortSpec(value string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth > 0 {
+				depth--
+			}
+		case ':':
+			if depth == 0 {
+				parts = append(parts, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, value[start:])
+}
@@ -0,0 +1,304 @@
+package local
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComposeFile_SetPublishedPort(t *testing.T) {
+	content := `services:
+  kong:
+    ports:
+      - "127.0.0.1:${KONG_HTTP_PORT:-8000}:8000/tcp"
+      - target: 8443
+        published: ${KONG_HTTPS_PORT}
+        protocol: tcp
+      - 9000-9001:9000-9001
+`
+	compose, err := ParseComposeFile([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseComposeFile failed: %v", err)
+	}
+
+	for target, published := range map[int]int{8000: 1000, 8443: 1443} {
+		ok, err := compose.SetPublishedPort("kong", target, published)
+		if err != nil || !ok {
+			t.Fatalf("SetPublishedPort(%d) = %v, %v", target, ok, err)
+		}
+	}
+
+	if ok, _ := compose.SetPublishedPort("kong", 3000, 1); ok {
+		t.Error("SetPublishedPort should report false for an unmapped container port")
+	}
+
+	data, err := compose.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{`127.0.0.1:1000:8000/tcp`, `published: "1443"`, `9000-9001:9000-9001`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestComposeFile_Labels(t *testing.T) {
+	content := `services:
+  web:
+    labels:
+      - "existing=1"
+  worker:
+    labels:
+      team: core
+`
+	compose, err := ParseComposeFile([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseComposeFile failed: %v", err)
+	}
+
+	if err := compose.SetLabel("web", "existing", "2"); err != nil {
+		t.Fatalf("SetLabel failed: %v", err)
+	}
+	if err := compose.SetLabel("worker", ProjectLabel, "p"); err != nil {
+		t.Fatalf("SetLabel failed: %v", err)
+	}
+
+	if err := compose.SetLabel("missing", "a", "b"); err == nil {
+		t.Error("SetLabel should fail for an unknown service")
+	}
+
+	data, err := compose.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{"existing=2", ProjectLabel + ": p", "team: core"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestComposeFile_Networks(t *testing.T) {
+	content := `services:
+  web:
+    networks:
+      - front
+      - back
+networks:
+  front:
+  back:
+    name: shared_back
+  edge:
+    external: true
+    name: edge
+`
+	compose, err := ParseComposeFile([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseComposeFile failed: %v", err)
+	}
+
+	if got, want := compose.Networks(), []string{"front", "back", "edge"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Networks() = %v, want %v", got, want)
+	}
+	for network, want := range map[string]string{"front": "", "back": "shared_back", "edge": "", "missing": ""} {
+		if got := compose.NetworkName(network); got != want {
+			t.Errorf("NetworkName(%q) = %q, want %q", network, got, want)
+		}
+	}
+
+	compose.SetNetworkName("front", "p_front")
+	if got := compose.NetworkName("front"); got != "p_front" {
+		t.Errorf("NetworkName after SetNetworkName = %q", got)
+	}
+}
+
+// loadFixture parses one of the Supabase compose files in testdata/compose
+func loadFixture(t *testing.T, name string) *ComposeFile {
+	t.Helper()
+	compose, err := LoadComposeFile(filepath.Join("testdata", "compose", name))
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+	return compose
+}
+
+func TestComposeFile_Fixtures(t *testing.T) {
+	for _, fixture := range []string{"supabase-2022-10.yml", "supabase-2024-03.yml", "supabase-2025-06.yml"} {
+		t.Run(fixture, func(t *testing.T) {
+			compose := loadFixture(t, fixture)
+
+			for _, service := range []string{"studio", "kong", "auth", "rest", "db"} {
+				if !compose.HasService(service) {
+					t.Errorf("service %s not found in %v", service, compose.Services())
+				}
+			}
+
+			for _, service := range compose.Services() {
+				if name := compose.ContainerName(service); name != "" {
+					if err := compose.SetContainerName(service, "p-"+name); err != nil {
+						t.Fatalf("SetContainerName failed: %v", err)
+					}
+				}
+				if err := compose.SetLabel(service, ProjectLabel, "p"); err != nil {
+					t.Fatalf("SetLabel failed: %v", err)
+				}
+			}
+			if ok, err := compose.SetPublishedPort("kong", 8000, 1000); err != nil || !ok {
+				t.Fatalf("SetPublishedPort(kong, 8000) = %v, %v", ok, err)
+			}
+
+			data, err := compose.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes failed: %v", err)
+			}
+			if !strings.Contains(string(data), "# Usage") {
+				t.Error("header comment was not preserved")
+			}
+
+			edited, err := ParseComposeFile(data)
+			if err != nil {
+				t.Fatalf("edited document does not parse: %v", err)
+			}
+			if got, _ := edited.PublishedPort("kong", 8000); got != "1000" {
+				t.Errorf("kong:8000 published = %q, want 1000", got)
+			}
+			for _, service := range edited.Services() {
+				if name := edited.ContainerName(service); name != "" && !strings.HasPrefix(name, "p-") {
+					t.Errorf("container_name of %s = %q, want p- prefix", service, name)
+				}
+			}
+			if got := strings.Count(string(data), ProjectLabel+": p"); got != len(edited.Services()) {
+				t.Errorf("%d services carry the project label, want %d", got, len(edited.Services()))
+			}
+		})
+	}
+}
+
+func TestComposeFile_Variables(t *testing.T) {
+	// The 2024 database publishes "${POSTGRES_PORT}:${POSTGRES_PORT}"
+	if !loadFixture(t, "supabase-2024-03.yml").PublishesVariable("db", "POSTGRES_PORT") {
+		t.Error("expected the database port to follow POSTGRES_PORT")
+	}
+
+	compose := loadFixture(t, "supabase-2025-06.yml")
+	if compose.PublishesVariable("supavisor", "POSTGRES_PORT") {
+		t.Error("the 2025 pooler publishes POSTGRES_PORT on a fixed container port")
+	}
+	if compose.PublishesVariable("kong", "KONG_HTTP_PORT") {
+		t.Error("kong publishes KONG_HTTP_PORT on a fixed container port")
+	}
+
+	services := compose.ServicesReferencing("KONG_HTTP_PORT")
+	if !reflect.DeepEqual(services, []string{"kong"}) {
+		t.Errorf("ServicesReferencing(KONG_HTTP_PORT) = %v, want [kong]", services)
+	}
+	if services := compose.ServicesReferencing("NOT_USED_ANYWHERE"); len(services) != 0 {
+		t.Errorf("ServicesReferencing(NOT_USED_ANYWHERE) = %v", services)
+	}
+}
+
+func TestComposeFile_CopyServiceField(t *testing.T) {
+	src, err := ParseComposeFile([]byte("services:\n  web:\n    ports:\n      - 80:80\n"))
+	if err != nil {
+		t.Fatalf("ParseComposeFile failed: %v", err)
+	}
+
+	dst := NewComposeFile()
+	dst.SetHeaderComment("generated")
+	if !dst.CopyServiceField(src, "web", "ports", "!override") {
+		t.Fatal("CopyServiceField should copy ports")
+	}
+	if dst.CopyServiceField(src, "web", "labels", "") || dst.CopyServiceField(src, "missing", "ports", "") {
+		t.Error("CopyServiceField should report fields and services that do not exist")
+	}
+
+	// The copy is independent of the source
+	if _, err := src.SetPublishedPort("web", 80, 8080); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := dst.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{"# generated", "ports: !override", "- 80:80"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestComposeFile_Dependencies(t *testing.T) {
+	content := `services:
+  studio:
+    depends_on:
+      analytics:
+        condition: service_healthy
+      meta:
+        condition: service_started
+  rest:
+    depends_on:
+      - db
+      - analytics
+`
+	compose, err := ParseComposeFile([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseComposeFile failed: %v", err)
+	}
+
+	if got := compose.DependsOn("rest"); !reflect.DeepEqual(got, []string{"db", "analytics"}) {
+		t.Errorf("DependsOn(rest) = %v", got)
+	}
+
+	disabled := map[string]bool{"analytics": true}
+	if !compose.RemoveDependencies("studio", disabled) || !compose.RemoveDependencies("rest", disabled) {
+		t.Fatal("RemoveDependencies should report the removed dependency")
+	}
+	if compose.RemoveDependencies("rest", disabled) || compose.RemoveDependencies("missing", disabled) {
+		t.Error("RemoveDependencies should report false when nothing is removed")
+	}
+
+	if got := compose.DependsOn("studio"); !reflect.DeepEqual(got, []string{"meta"}) {
+		t.Errorf("DependsOn(studio) = %v, want [meta]", got)
+	}
+	if got := compose.DependsOn("rest"); !reflect.DeepEqual(got, []string{"db"}) {
+		t.Errorf("DependsOn(rest) = %v, want [db]", got)
+	}
+}
+
+func TestComposeFile_ProfilesAndLimits(t *testing.T) {
+	compose := NewComposeFile()
+	compose.SetProfiles("analytics", "disabled")
+	compose.SetResourceLimits("db", ServiceResources{Memory: "1g", CPUs: "2"})
+	compose.SetResourceLimits("rest", ServiceResources{Memory: "256m"})
+
+	path := filepath.Join(t.TempDir(), ComposeOverrideFileName)
+	if err := compose.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	saved, err := LoadComposeFile(path)
+	if err != nil {
+		t.Fatalf("LoadComposeFile failed: %v", err)
+	}
+	if got := saved.Services(); !reflect.DeepEqual(got, []string{"analytics", "db", "rest"}) {
+		t.Errorf("Services() = %v", got)
+	}
+
+	data, _ := saved.Bytes()
+	out := string(data)
+	for _, want := range []string{"- disabled", "mem_limit: 1g", "cpus: 2\n", "mem_limit: 256m"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "cpus") != 1 {
+		t.Errorf("empty limits should be left unset:\n%s", out)
+	}
+}
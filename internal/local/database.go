@@ -0,0 +1,100 @@
+package local
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// databaseInfrastructure are services that depend on the db but are needed to reach it
+// (the pooler) or only store Supabase's own data, so they keep running during a reset
+var databaseInfrastructure = map[string]bool{"supavisor": true, "analytics": true, "vector": true}
+
+// DatabaseCredentials are the connection details of a local project's Postgres database
+type DatabaseCredentials struct {
+	User     string
+	Password string
+	Database string
+
+	// Container is the db container, for connecting from inside Docker
+	Container string
+}
+
+// GetDatabaseCredentials reads a project's database credentials from its .env and compose files.
+// When the host port is served by the supavisor pooler, the user carries the pooler tenant ID.
+func GetDatabaseCredentials(projectID, directory string) (*DatabaseCredentials, error) {
+	env, err := LoadProjectEnv(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	password, ok := env.Get("POSTGRES_PASSWORD")
+	if !ok || password == "" {
+		return nil, fmt.Errorf("POSTGRES_PASSWORD is not set in %s", GetEnvPath(directory))
+	}
+
+	creds := &DatabaseCredentials{
+		User:     "postgres",
+		Password: password,
+		Database: "postgres",
+	}
+	if database, ok := env.Get("POSTGRES_DB"); ok && database != "" {
+		creds.Database = database
+	}
+
+	dockerDir := GetDockerDir(directory)
+	upstream, err := LoadComposeFile(filepath.Join(dockerDir, ComposeFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := upstream.PublishedPort("supavisor", 5432); ok {
+		tenant, _ := env.Get("POOLER_TENANT_ID")
+		if tenant == "" {
+			return nil, fmt.Errorf("POOLER_TENANT_ID is not set in %s", GetEnvPath(directory))
+		}
+		creds.User = "postgres." + tenant
+	}
+
+	creds.Container = upstream.ContainerName("db")
+	if HasComposeOverride(directory) {
+		override, err := LoadComposeFile(filepath.Join(dockerDir, ComposeOverrideFileName))
+		if err != nil {
+			return nil, err
+		}
+		if name := override.ContainerName("db"); name != "" {
+			creds.Container = name
+		}
+	}
+	if creds.Container == "" {
+		// Compose's default container naming
+		creds.Container = fmt.Sprintf("%s-db-1", projectID)
+	}
+
+	return creds, nil
+}
+
+// DatabaseClientServices returns the services that connect to the db service and should be
+// stopped while the database is reset, sorted by name
+func DatabaseClientServices(directory string) ([]string, error) {
+	compose, err := LoadComposeFile(filepath.Join(GetDockerDir(directory), ComposeFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var services []string
+	for _, service := range compose.Services() {
+		if databaseInfrastructure[service] {
+			continue
+		}
+		for _, dependency := range compose.DependsOn(service) {
+			if dependency == "db" {
+				services = append(services, service)
+				break
+			}
+		}
+	}
+	sort.Strings(services)
+
+	return services, nil
+}
@@ -0,0 +1,65 @@
+package local
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGetDatabaseCredentials(t *testing.T) {
+	env := "POSTGRES_PASSWORD=secret\nPOSTGRES_DB=app\nPOOLER_TENANT_ID=tenant\n"
+
+	tests := []struct {
+		fixture       string
+		wantUser      string
+		wantContainer string
+	}{
+		{"supabase-2022-10.yml", "postgres", "myproj-supabase-db"},
+		{"supabase-2025-06.yml", "postgres.tenant", "myproj-supabase-db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			directory := setupFixtureProject(t, tt.fixture)
+			if err := os.WriteFile(GetEnvPath(directory), []byte(env), 0600); err != nil {
+				t.Fatalf("failed to write .env: %v", err)
+			}
+			if _, err := WriteComposeOverride("myproj", testProject(directory)); err != nil {
+				t.Fatalf("WriteComposeOverride failed: %v", err)
+			}
+
+			creds, err := GetDatabaseCredentials("myproj", directory)
+			if err != nil {
+				t.Fatalf("GetDatabaseCredentials failed: %v", err)
+			}
+
+			want := DatabaseCredentials{User: tt.wantUser, Password: "secret", Database: "app", Container: tt.wantContainer}
+			if *creds != want {
+				t.Errorf("credentials = %+v, want %+v", *creds, want)
+			}
+		})
+	}
+}
+
+func TestDatabaseClientServices(t *testing.T) {
+	directory := setupFixtureProject(t, "supabase-2025-06.yml")
+
+	services, err := DatabaseClientServices(directory)
+	if err != nil {
+		t.Fatalf("DatabaseClientServices failed: %v", err)
+	}
+
+	for _, service := range services {
+		if service == "db" || service == "supavisor" || service == "analytics" {
+			t.Errorf("%s must keep running during a reset, got %v", service, services)
+		}
+	}
+	for _, want := range []string{"auth", "rest"} {
+		found := false
+		for _, service := range services {
+			found = found || service == want
+		}
+		if !found {
+			t.Errorf("expected %s in %v", want, services)
+		}
+	}
+}
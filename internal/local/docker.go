@@ -1,15 +1,31 @@
 package local
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/qubitquilt/supactl/internal/metrics"
 )
 
-// DockerComposeUp starts the Docker Compose services for a project
-func DockerComposeUp(projectID, directory string) error {
-	dockerDir := filepath.Join(directory, "supabase", "docker")
+// CLIRunner is a ComposeRunner that shells out to 'docker compose'
+type CLIRunner struct {
+	exec CommandExecutor
+}
+
+// NewCLIRunner creates a runner that runs docker through executor
+func NewCLIRunner(executor CommandExecutor) *CLIRunner {
+	return &CLIRunner{exec: executor}
+}
+
+// Up starts the given Docker Compose services of a project (or all services if none),
+// along with the services they depend on
+func (r *CLIRunner) Up(projectID, directory string, services ...string) error {
+	dockerDir := GetDockerDir(directory)
 
 	// Check if directory exists
 	if _, err := os.Stat(dockerDir); os.IsNotExist(err) {
@@ -23,54 +39,169 @@ func DockerComposeUp(projectID, directory string) error {
 	}
 
 	// Run docker compose up -d
-	cmd := exec.Command("docker", "compose", "-p", projectID, "up", "-d")
-	cmd.Dir = dockerDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
+	if err := r.exec.Run(dockerDir, "docker", ComposeArgs(projectID, directory, append([]string{"up", "-d"}, services...)...)...); err != nil {
 		return fmt.Errorf("docker compose up failed: %w", err)
 	}
 
 	return nil
 }
 
-// DockerComposeDown stops and removes the Docker Compose services for a project
-func DockerComposeDown(projectID, directory string) error {
-	dockerDir := filepath.Join(directory, "supabase", "docker")
+// Stop stops the given services (or all services if none) without removing them
+func (r *CLIRunner) Stop(projectID, directory string, services ...string) error {
+	if err := r.exec.Run(GetDockerDir(directory), "docker", ComposeArgs(projectID, directory, append([]string{"stop"}, services...)...)...); err != nil {
+		return fmt.Errorf("docker compose stop failed: %w", err)
+	}
+
+	return nil
+}
+
+// Restart restarts the given services (or all services if none)
+func (r *CLIRunner) Restart(projectID, directory string, services ...string) error {
+	if err := r.exec.Run(GetDockerDir(directory), "docker", ComposeArgs(projectID, directory, append([]string{"restart"}, services...)...)...); err != nil {
+		return fmt.Errorf("docker compose restart failed: %w", err)
+	}
+
+	return nil
+}
+
+// Down stops and removes the Docker Compose services for a project. The project's
+// volumes, which hold its data, are only removed if removeVolumes is set.
+func (r *CLIRunner) Down(projectID, directory string, removeVolumes bool) error {
+	dockerDir := GetDockerDir(directory)
 
 	// Check if directory exists
 	if _, err := os.Stat(dockerDir); os.IsNotExist(err) {
 		return fmt.Errorf("docker directory not found: %s", dockerDir)
 	}
 
-	// Run docker compose down -v --remove-orphans
-	cmd := exec.Command("docker", "compose", "-p", projectID, "down", "-v", "--remove-orphans")
-	cmd.Dir = dockerDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
+	args := []string{"down", "--remove-orphans"}
+	if removeVolumes {
+		args = append(args, "-v")
+	}
+	if err := r.exec.Run(dockerDir, "docker", ComposeArgs(projectID, directory, args...)...); err != nil {
 		return fmt.Errorf("docker compose down failed: %w", err)
 	}
 
 	return nil
 }
 
+// Running reports whether any of the project's containers is running
+func (r *CLIRunner) Running(projectID, directory string) (bool, error) {
+	output, err := r.exec.Output(GetDockerDir(directory), "docker", ComposeArgs(projectID, directory, "ps", "-q")...)
+	if err != nil {
+		return false, fmt.Errorf("docker compose ps failed: %w", err)
+	}
+
+	// If there are container IDs in the output, project is running
+	return len(strings.TrimSpace(string(output))) > 0, nil
+}
+
+// RunningServices returns the sorted names of the project's services that have a running container
+func (r *CLIRunner) RunningServices(projectID, directory string) ([]string, error) {
+	output, err := r.exec.Output(GetDockerDir(directory), "docker", ComposeArgs(projectID, directory, "ps", "--services", "--status", "running")...)
+	if err != nil {
+		return nil, fmt.Errorf("docker compose ps failed: %w", err)
+	}
+
+	services := strings.Fields(string(output))
+	sort.Strings(services)
+	return services, nil
+}
+
+// Logs returns the most recent lines of the logs of the project's services
+func (r *CLIRunner) Logs(projectID, directory string, lines int) (string, error) {
+	output, err := r.exec.CombinedOutput(GetDockerDir(directory), "docker", ComposeArgs(projectID, directory, "logs", "--tail", fmt.Sprintf("%d", lines))...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get logs: %w", err)
+	}
+
+	return string(output), nil
+}
+
+// Stats returns the CPU and memory usage of a project's running containers, keyed by
+// compose service
+func (r *CLIRunner) Stats(projectID, directory string) ([]metrics.Service, error) {
+	output, err := r.exec.Output(GetDockerDir(directory), "docker", ComposeArgs(projectID, directory, "ps", "--format", "{{.Name}}\t{{.Service}}")...)
+	if err != nil {
+		return nil, fmt.Errorf("docker compose ps failed: %w", err)
+	}
+
+	services := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if container, service, ok := strings.Cut(line, "\t"); ok {
+			services[container] = service
+		}
+	}
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	args := []string{"stats", "--no-stream", "--format", "{{json .}}"}
+	for container := range services {
+		args = append(args, container)
+	}
+
+	output, err = r.exec.Output("", "docker", args...)
+	if err != nil {
+		return nil, fmt.Errorf("docker stats failed: %w", err)
+	}
+
+	return metrics.ParseDockerStats(output, services)
+}
+
 // CheckDockerAvailable checks if Docker is available on the system
-func CheckDockerAvailable() error {
-	cmd := exec.Command("docker", "version")
-	if err := cmd.Run(); err != nil {
+func CheckDockerAvailable(executor CommandExecutor) error {
+	if _, err := executor.Output("", "docker", "version"); err != nil {
 		return fmt.Errorf("docker is not available or not running: %w", err)
 	}
 	return nil
 }
 
-// CheckDockerComposeAvailable checks if Docker Compose is available
-func CheckDockerComposeAvailable() error {
-	cmd := exec.Command("docker", "compose", "version")
-	if err := cmd.Run(); err != nil {
+// MinDockerComposeVersion is the oldest Docker Compose release that understands the
+// !override tag used by the compose override file
+const MinDockerComposeVersion = "2.24.4"
+
+// ErrDockerComposeTooOld is returned by CheckDockerComposeAvailable for releases older than MinDockerComposeVersion
+var ErrDockerComposeTooOld = errors.New("docker compose is too old")
+
+// CheckDockerComposeAvailable checks if Docker Compose is available and recent enough
+func CheckDockerComposeAvailable(executor CommandExecutor) error {
+	output, err := executor.Output("", "docker", "compose", "version", "--short")
+	if err != nil {
 		return fmt.Errorf("docker compose is not available: %w", err)
 	}
+
+	version := strings.TrimSpace(string(output))
+	if compareVersions(version, MinDockerComposeVersion) < 0 {
+		return fmt.Errorf("%w: found %s, supactl needs %s or later", ErrDockerComposeTooOld, version, MinDockerComposeVersion)
+	}
 	return nil
 }
+
+// compareVersions compares dotted numeric versions such as "v2.24.4" or "2.27.0-desktop.1",
+// ignoring a leading v and any pre-release or build suffix. Unparseable components count as 0.
+func compareVersions(a, b string) int {
+	parse := func(version string) [3]int {
+		version = strings.TrimPrefix(version, "v")
+		if i := strings.IndexAny(version, "-+"); i >= 0 {
+			version = version[:i]
+		}
+
+		var parts [3]int
+		for i, field := range strings.SplitN(version, ".", 3) {
+			parts[i], _ = strconv.Atoi(field)
+		}
+		return parts
+	}
+
+	pa, pb := parse(a), parse(b)
+	for i := range pa {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
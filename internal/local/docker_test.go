@@ -0,0 +1,83 @@
+package local
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/testutil"
+)
+
+// setupStartableProject returns a fixture project with the .env file CLIRunner.Up requires
+func setupStartableProject(t *testing.T) string {
+	t.Helper()
+	directory := setupFixtureProject(t, "supabase-2025-06.yml")
+	if err := os.WriteFile(GetEnvPath(directory), []byte("POSTGRES_PASSWORD=secret\n"), 0600); err != nil {
+		t.Fatalf("failed to write .env: %v", err)
+	}
+	return directory
+}
+
+// Regression test: stopping an instance used to run 'down -v', deleting its data volumes
+func TestDockerCompose_Arguments(t *testing.T) {
+	calls := testutil.FakeCommand(t, "docker", "")
+	directory := setupStartableProject(t)
+	runner := NewCLIRunner(OSExecutor{})
+	base := []string{"compose", "-p", "myproj", "-f", ComposeFileName}
+
+	steps := []struct {
+		name string
+		run  func() error
+		want []string
+	}{
+		{"up", func() error { return runner.Up("myproj", directory) }, []string{"up", "-d"}},
+		{"up services", func() error { return runner.Up("myproj", directory, "studio", "kong") }, []string{"up", "-d", "studio", "kong"}},
+		{"stop", func() error { return runner.Stop("myproj", directory) }, []string{"stop"}},
+		{"stop services", func() error { return runner.Stop("myproj", directory, "realtime") }, []string{"stop", "realtime"}},
+		{"restart services", func() error { return runner.Restart("myproj", directory, "auth") }, []string{"restart", "auth"}},
+		{"down keeps volumes", func() error { return runner.Down("myproj", directory, false) }, []string{"down", "--remove-orphans"}},
+		{"down removes volumes", func() error { return runner.Down("myproj", directory, true) }, []string{"down", "--remove-orphans", "-v"}},
+		{"running services", func() error { _, err := runner.RunningServices("myproj", directory); return err }, []string{"ps", "--services", "--status", "running"}},
+	}
+
+	for i, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s failed: %v", step.name, err)
+		}
+		got := calls()
+		if len(got) != i+1 {
+			t.Fatalf("%s: expected %d docker runs, got %v", step.name, i+1, got)
+		}
+		if want := append(append([]string{}, base...), step.want...); !reflect.DeepEqual(got[i], want) {
+			t.Errorf("%s: docker %q, want %q", step.name, got[i], want)
+		}
+	}
+}
+
+func TestCheckDockerComposeAvailable(t *testing.T) {
+	tests := []struct {
+		version string
+		tooOld  bool
+	}{
+		{"2.24.4\n", false},
+		{"v2.29.1\n", false},
+		{"2.27.0-desktop.2\n", false},
+		{"2.24.3\n", true},
+		{"2.3.3\n", true},
+		{"1.29.2\n", true},
+	}
+
+	for _, tt := range tests {
+		executor := &testutil.RecordingExecutor{}
+		executor.On("docker compose version --short", tt.version, nil)
+
+		err := CheckDockerComposeAvailable(executor)
+		if got := errors.Is(err, ErrDockerComposeTooOld); got != tt.tooOld {
+			t.Errorf("CheckDockerComposeAvailable(%q) = %v, want too old %v", tt.version, err, tt.tooOld)
+		}
+		if !tt.tooOld && err != nil {
+			t.Errorf("CheckDockerComposeAvailable(%q) unexpected error: %v", tt.version, err)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// envLine is a single line of a .env file. Comments and blank lines have an empty key.
+type envLine struct {
+	raw   string
+	key   string
+	value string
+}
+
+// EnvFile is a parsed .env file that preserves comments, ordering and formatting of untouched lines
+type EnvFile struct {
+	lines []envLine
+}
+
+// LoadEnvFile reads and parses a .env file
+func LoadEnvFile(path string) (*EnvFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	return ParseEnvFile(data), nil
+}
+
+// ParseEnvFile parses .env content. Lines that are not KEY=VALUE assignments are kept verbatim.
+func ParseEnvFile(data []byte) *EnvFile {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+
+	env := &EnvFile{}
+	if text == "" {
+		return env
+	}
+
+	for _, raw := range strings.Split(text, "\n") {
+		line := envLine{raw: raw}
+		if key, value, ok := parseEnvAssignment(raw); ok {
+			line.key = key
+			line.value = value
+		}
+		env.lines = append(env.lines, line)
+	}
+	return env
+}
+
+// Get returns the value of key and whether it is set
+func (e *EnvFile) Get(key string) (string, bool) {
+	for i := len(e.lines) - 1; i >= 0; i-- {
+		if e.lines[i].key == key {
+			return e.lines[i].value, true
+		}
+	}
+	return "", false
+}
+
+// Set sets key to value, replacing every existing assignment or appending a new one
+func (e *EnvFile) Set(key, value string) {
+	if !e.Replace(key, value) {
+		e.lines = append(e.lines, envLine{raw: formatEnvAssignment(key, value), key: key, value: value})
+	}
+}
+
+// Replace sets key to value only if it is already assigned, and reports whether it was
+func (e *EnvFile) Replace(key, value string) bool {
+	replaced := false
+	for i := range e.lines {
+		if e.lines[i].key == key {
+			prefix, comment := envAssignmentAffixes(e.lines[i].raw)
+			e.lines[i] = envLine{raw: prefix + formatEnvAssignment(key, value) + comment, key: key, value: value}
+			replaced = true
+		}
+	}
+	return replaced
+}
+
+// Unset removes every assignment of key and reports whether any existed
+func (e *EnvFile) Unset(key string) bool {
+	kept := e.lines[:0]
+	removed := false
+	for _, line := range e.lines {
+		if line.key == key {
+			removed = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	e.lines = kept
+	return removed
+}
+
+// Keys returns the assigned keys in sorted order
+func (e *EnvFile) Keys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, line := range e.lines {
+		if line.key != "" && !seen[line.key] {
+			seen[line.key] = true
+			keys = append(keys, line.key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Values returns all assignments as a map
+func (e *EnvFile) Values() map[string]string {
+	values := make(map[string]string)
+	for _, line := range e.lines {
+		if line.key != "" {
+			values[line.key] = line.value
+		}
+	}
+	return values
+}
+
+// Bytes serializes the file
+func (e *EnvFile) Bytes() []byte {
+	var b strings.Builder
+	for _, line := range e.lines {
+		b.WriteString(line.raw)
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+// Save writes the file with 0600 permissions, since .env files hold secrets
+func (e *EnvFile) Save(path string) error {
+	if err := os.WriteFile(path, e.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// parseEnvAssignment parses a "KEY=VALUE" line, with optional "export" prefix, quoting and trailing comments
+func parseEnvAssignment(raw string) (string, string, bool) {
+	line := strings.TrimSpace(raw)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+	line = strings.TrimPrefix(line, "export ")
+
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", "", false
+	}
+	key = strings.TrimSpace(key)
+	if !isValidEnvKey(key) {
+		return "", "", false
+	}
+
+	value = strings.TrimSpace(value)
+	switch {
+	case len(value) >= 2 && value[0] == '"':
+		if end := closingQuote(value, '"'); end > 0 {
+			return key, unescapeDoubleQuoted(value[1:end]), true
+		}
+	case len(value) >= 2 && value[0] == '\'':
+		if end := strings.IndexByte(value[1:], '\''); end >= 0 {
+			return key, value[1 : end+1], true
+		}
+	}
+
+	// Unquoted values end at the first " #" comment
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return key, value, true
+}
+
+// envAssignmentAffixes returns what surrounds the assignment of a line parseEnvAssignment
+// accepts: the indentation and "export" prefix, and the trailing comment
+func envAssignmentAffixes(raw string) (string, string) {
+	rest := strings.TrimLeft(raw, " \t")
+	prefix := raw[:len(raw)-len(rest)]
+	if strings.HasPrefix(rest, "export ") {
+		prefix += "export "
+	}
+
+	_, value, _ := strings.Cut(rest, "=")
+	value = strings.TrimSpace(value)
+	switch {
+	case len(value) >= 2 && value[0] == '"':
+		if end := closingQuote(value, '"'); end > 0 {
+			return prefix, value[end+1:]
+		}
+	case len(value) >= 2 && value[0] == '\'':
+		if end := strings.IndexByte(value[1:], '\''); end >= 0 {
+			return prefix, value[end+2:]
+		}
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		return prefix, value[i:]
+	}
+	return prefix, ""
+}
+
+// closingQuote returns the index of the unescaped closing quote in s (which starts with the opening quote)
+func closingQuote(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDoubleQuoted resolves the escapes supported inside double-quoted values
+func unescapeDoubleQuoted(s string) string {
+	r := strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`)
+	return r.Replace(s)
+}
+
+// formatEnvAssignment formats a KEY=VALUE line, quoting the value only when necessary
+func formatEnvAssignment(key, value string) string {
+	if value == "" || !strings.ContainsAny(value, " \t\n#\"'\\") {
+		return key + "=" + value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return key + `="` + escaped + `"`
+}
+
+// isValidEnvKey reports whether key is a valid environment variable name
+func isValidEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(isDigit && i > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateEnvKey checks that key is a well-formed environment variable name
+func ValidateEnvKey(key string) error {
+	if !isValidEnvKey(key) {
+		return fmt.Errorf("invalid key '%s': must contain only letters, digits and underscores and not start with a digit", key)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	content := `############
+# Secrets
+############
+
+POSTGRES_PASSWORD=your-super-secret-and-long-postgres-password
+export SITE_URL=http://localhost:3000
+SMTP_SENDER_NAME="Fake Sender" # trailing comment
+ADDITIONAL_REDIRECT_URLS=
+QUOTED='single # not a comment'
+ESCAPED="line\nbreak \"quoted\""
+UNQUOTED=value # comment
+not a valid line
+`
+	env := ParseEnvFile([]byte(content))
+
+	tests := map[string]string{
+		"POSTGRES_PASSWORD":        "your-super-secret-and-long-postgres-password",
+		"SITE_URL":                 "http://localhost:3000",
+		"SMTP_SENDER_NAME":         "Fake Sender",
+		"ADDITIONAL_REDIRECT_URLS": "",
+		"QUOTED":                   "single # not a comment",
+		"ESCAPED":                  "line\nbreak \"quoted\"",
+		"UNQUOTED":                 "value",
+	}
+	for key, want := range tests {
+		got, ok := env.Get(key)
+		if !ok || got != want {
+			t.Errorf("Get(%q) = %q, %v; want %q", key, got, ok, want)
+		}
+	}
+
+	if _, ok := env.Get("MISSING"); ok {
+		t.Error("Get should report false for unset keys")
+	}
+
+	if string(env.Bytes()) != content {
+		t.Errorf("unchanged file did not round-trip:\n%s", env.Bytes())
+	}
+}
+
+func TestEnvFile_SetReplaceUnset(t *testing.T) {
+	env := ParseEnvFile([]byte("# header\nA=1\nB=2\n"))
+
+	if !env.Replace("A", "10") {
+		t.Error("Replace should report true for an existing key")
+	}
+	if env.Replace("C", "3") {
+		t.Error("Replace should not add missing keys")
+	}
+
+	env.Set("C", "has spaces")
+	env.Set("D", `back\slash`)
+	if !env.Unset("B") {
+		t.Error("Unset should report true for an existing key")
+	}
+	if env.Unset("B") {
+		t.Error("Unset should report false for a missing key")
+	}
+
+	want := "# header\nA=10\nC=\"has spaces\"\nD=\"back\\\\slash\"\n"
+	if got := string(env.Bytes()); got != want {
+		t.Errorf("Bytes() = %q, want %q", got, want)
+	}
+
+	reparsed := ParseEnvFile(env.Bytes())
+	if !reflect.DeepEqual(reparsed.Values(), env.Values()) {
+		t.Errorf("values did not survive a round trip: %v vs %v", reparsed.Values(), env.Values())
+	}
+
+	if got := env.Keys(); !reflect.DeepEqual(got, []string{"A", "C", "D"}) {
+		t.Errorf("Keys() = %v", got)
+	}
+}
+
+func TestEnvFile_ReplaceKeepsExportAndComment(t *testing.T) {
+	env := ParseEnvFile([]byte("export A=1 # api port\n  B=\"two\"  # quoted\nexport C='x'\n"))
+
+	env.Replace("A", "10")
+	env.Replace("B", "has spaces")
+	env.Replace("C", "y")
+
+	want := "export A=10 # api port\n  B=\"has spaces\"  # quoted\nexport C=y\n"
+	if got := string(env.Bytes()); got != want {
+		t.Errorf("Bytes() = %q, want %q", got, want)
+	}
+
+	reparsed := ParseEnvFile(env.Bytes())
+	if !reflect.DeepEqual(reparsed.Values(), map[string]string{"A": "10", "B": "has spaces", "C": "y"}) {
+		t.Errorf("values did not survive a round trip: %v", reparsed.Values())
+	}
+}
+
+func TestValidateEnvKey(t *testing.T) {
+	for _, key := range []string{"SITE_URL", "_PRIVATE", "A1"} {
+		if err := ValidateEnvKey(key); err != nil {
+			t.Errorf("ValidateEnvKey(%q) unexpected error: %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "1ABC", "WITH-DASH", "WITH SPACE"} {
+		if err := ValidateEnvKey(key); err == nil {
+			t.Errorf("ValidateEnvKey(%q) expected error", key)
+		}
+	}
+}
+
+func TestKnownEnvKeysAndAffectedServices(t *testing.T) {
+	directory := setupFixtureProject(t, "supabase-2022-10.yml")
+	dockerDir := GetDockerDir(directory)
+
+	if err := os.WriteFile(filepath.Join(dockerDir, ".env.example"), []byte("SITE_URL=\nSMTP_HOST=\n"), 0644); err != nil {
+		t.Fatalf("failed to write .env.example: %v", err)
+	}
+	if err := os.WriteFile(GetEnvPath(directory), []byte("SITE_URL=x\nCUSTOM=y\n"), 0600); err != nil {
+		t.Fatalf("failed to write .env: %v", err)
+	}
+
+	known, err := KnownEnvKeys(directory)
+	if err != nil {
+		t.Fatalf("KnownEnvKeys failed: %v", err)
+	}
+	for _, key := range []string{"SITE_URL", "SMTP_HOST", "CUSTOM"} {
+		if !known[key] {
+			t.Errorf("expected %s to be known", key)
+		}
+	}
+
+	services, err := AffectedServices(directory, []string{"ANON_KEY"})
+	if err != nil {
+		t.Fatalf("AffectedServices failed: %v", err)
+	}
+	if !reflect.DeepEqual(services, []string{"studio"}) {
+		t.Errorf("AffectedServices(ANON_KEY) = %v, want [studio]", services)
+	}
+
+	services, err = AffectedServices(directory, []string{"POSTGRES_PASSWORD"})
+	if err != nil {
+		t.Fatalf("AffectedServices failed: %v", err)
+	}
+	if strings.Join(services, ",") != "db,rest,studio" {
+		t.Errorf("AffectedServices(POSTGRES_PASSWORD) = %v, want [db rest studio]", services)
+	}
+}
+
+func TestGetServiceRoleKey(t *testing.T) {
+	directory := setupFixtureProject(t, "supabase-2025-06.yml")
+
+	if err := os.WriteFile(GetEnvPath(directory), []byte("SERVICE_ROLE_KEY=stored-key\n"), 0600); err != nil {
+		t.Fatalf("failed to write .env: %v", err)
+	}
+	key, err := GetServiceRoleKey(directory)
+	if err != nil || key != "stored-key" {
+		t.Errorf("GetServiceRoleKey() = %q, %v; want stored-key", key, err)
+	}
+
+	// Without a stored key, one is signed from the JWT secret
+	if err := os.WriteFile(GetEnvPath(directory), []byte("JWT_SECRET=test-secret-key-for-jwt-generation\n"), 0600); err != nil {
+		t.Fatalf("failed to write .env: %v", err)
+	}
+	key, err = GetServiceRoleKey(directory)
+	if err != nil || strings.Count(key, ".") != 2 {
+		t.Errorf("GetServiceRoleKey() = %q, %v; want a signed JWT", key, err)
+	}
+
+	if err := os.WriteFile(GetEnvPath(directory), []byte("SITE_URL=x\n"), 0600); err != nil {
+		t.Fatalf("failed to write .env: %v", err)
+	}
+	if _, err := GetServiceRoleKey(directory); err == nil {
+		t.Error("expected an error without SERVICE_ROLE_KEY or JWT_SECRET")
+	}
+}
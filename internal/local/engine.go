@@ -0,0 +1,330 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/metrics"
+)
+
+// Labels compose puts on the containers it creates
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// EngineRunner is a ComposeRunner that talks to the Docker Engine API over a unix socket
+// instead of running the docker CLI. The Engine API has no notion of compose files, so
+// creating and removing a project's containers (Up and Down) is delegated to another
+// runner; the other operations find the containers by the labels compose puts on them.
+type EngineRunner struct {
+	client  *http.Client
+	compose ComposeRunner
+}
+
+// engineContainer is an entry of the Engine API's container list
+type engineContainer struct {
+	ID     string            `json:"Id"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// service returns the compose service the container runs
+func (c engineContainer) service() string {
+	return c.Labels[composeServiceLabel]
+}
+
+// engineCPUStats is the CPU part of a container stats sample
+type engineCPUStats struct {
+	CPUUsage struct {
+		TotalUsage  uint64   `json:"total_usage"`
+		PercpuUsage []uint64 `json:"percpu_usage"`
+	} `json:"cpu_usage"`
+	SystemUsage uint64 `json:"system_cpu_usage"`
+	OnlineCPUs  uint32 `json:"online_cpus"`
+}
+
+// engineStats is a container stats sample
+type engineStats struct {
+	CPUStats    engineCPUStats `json:"cpu_stats"`
+	PreCPUStats engineCPUStats `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+}
+
+// NewEngineRunner creates a runner for the Docker Engine listening on socket, delegating
+// Up and Down to compose. An empty socket uses DOCKER_HOST, or DefaultDockerSocket.
+func NewEngineRunner(socket string, compose ComposeRunner) (*EngineRunner, error) {
+	if socket == "" {
+		socket = DefaultDockerSocket
+		if host := os.Getenv("DOCKER_HOST"); host != "" {
+			if !strings.HasPrefix(host, "unix://") {
+				return nil, fmt.Errorf("DOCKER_HOST '%s' is not a unix socket; use the %s docker runner", host, RunnerCLI)
+			}
+			socket = host
+		}
+	}
+	socket = strings.TrimPrefix(socket, "unix://")
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}
+
+	return &EngineRunner{
+		client:  &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		compose: compose,
+	}, nil
+}
+
+// Up creates and starts services through the compose runner
+func (r *EngineRunner) Up(projectID, directory string, services ...string) error {
+	return r.compose.Up(projectID, directory, services...)
+}
+
+// Down removes the project's containers through the compose runner
+func (r *EngineRunner) Down(projectID, directory string, removeVolumes bool) error {
+	return r.compose.Down(projectID, directory, removeVolumes)
+}
+
+// Stop stops the running containers of the given services (or all services if none)
+func (r *EngineRunner) Stop(projectID, directory string, services ...string) error {
+	containers, err := r.containers(projectID, false, services)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range containers {
+		if err := r.do(http.MethodPost, "/containers/"+c.ID+"/stop", nil); err != nil {
+			return fmt.Errorf("failed to stop %s: %w", c.service(), err)
+		}
+	}
+	return nil
+}
+
+// Restart restarts the containers of the given services (or all services if none)
+func (r *EngineRunner) Restart(projectID, directory string, services ...string) error {
+	containers, err := r.containers(projectID, true, services)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range containers {
+		if err := r.do(http.MethodPost, "/containers/"+c.ID+"/restart", nil); err != nil {
+			return fmt.Errorf("failed to restart %s: %w", c.service(), err)
+		}
+	}
+	return nil
+}
+
+// Running reports whether any of the project's containers is running
+func (r *EngineRunner) Running(projectID, directory string) (bool, error) {
+	containers, err := r.containers(projectID, false, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(containers) > 0, nil
+}
+
+// RunningServices returns the sorted names of the project's services that have a running container
+func (r *EngineRunner) RunningServices(projectID, directory string) ([]string, error) {
+	containers, err := r.containers(projectID, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []string
+	for _, c := range containers {
+		// containers are sorted by service, so replicas are adjacent
+		if n := len(services); n == 0 || services[n-1] != c.service() {
+			services = append(services, c.service())
+		}
+	}
+	return services, nil
+}
+
+// Logs returns the most recent lines of each container's logs, prefixed with its service
+// like 'docker compose logs'
+func (r *EngineRunner) Logs(projectID, directory string, lines int) (string, error) {
+	containers, err := r.containers(projectID, true, nil)
+	if err != nil {
+		return "", err
+	}
+
+	width := 0
+	for _, c := range containers {
+		width = max(width, len(c.service()))
+	}
+
+	var out strings.Builder
+	for _, c := range containers {
+		query := url.Values{"stdout": {"true"}, "stderr": {"true"}, "tail": {fmt.Sprintf("%d", lines)}}
+		var raw bytes.Buffer
+		if err := r.do(http.MethodGet, "/containers/"+c.ID+"/logs?"+query.Encode(), &raw); err != nil {
+			return "", fmt.Errorf("failed to get logs of %s: %w", c.service(), err)
+		}
+
+		text := strings.TrimRight(string(demuxLogs(raw.Bytes())), "\n")
+		if text == "" {
+			continue
+		}
+		for _, line := range strings.Split(text, "\n") {
+			fmt.Fprintf(&out, "%-*s | %s\n", width, c.service(), line)
+		}
+	}
+
+	return out.String(), nil
+}
+
+// Stats returns the CPU and memory usage of the project's running containers
+func (r *EngineRunner) Stats(projectID, directory string) ([]metrics.Service, error) {
+	containers, err := r.containers(projectID, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []metrics.Service
+	for _, c := range containers {
+		var stats engineStats
+		if err := r.do(http.MethodGet, "/containers/"+c.ID+"/stats?stream=false", &stats); err != nil {
+			return nil, fmt.Errorf("failed to get stats of %s: %w", c.service(), err)
+		}
+		result = append(result, serviceUsage(c.service(), &stats))
+	}
+
+	return result, nil
+}
+
+// containers lists the project's containers (only running ones unless all is set) that run
+// one of services, or any service if none, sorted by service
+func (r *EngineRunner) containers(projectID string, all bool, services []string) ([]engineContainer, error) {
+	filters, err := json.Marshal(map[string][]string{"label": {composeProjectLabel + "=" + projectID}})
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{"filters": {string(filters)}}
+	if all {
+		query.Set("all", "true")
+	}
+
+	var listed []engineContainer
+	if err := r.do(http.MethodGet, "/containers/json?"+query.Encode(), &listed); err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(services))
+	for _, service := range services {
+		wanted[service] = true
+	}
+
+	var containers []engineContainer
+	for _, c := range listed {
+		if len(wanted) == 0 || wanted[c.service()] {
+			containers = append(containers, c)
+		}
+	}
+	sort.SliceStable(containers, func(i, j int) bool { return containers[i].service() < containers[j].service() })
+
+	return containers, nil
+}
+
+// do sends a request to the Engine API. A *bytes.Buffer result receives the raw body;
+// any other non-nil result is decoded from JSON.
+func (r *EngineRunner) do(method, path string, result interface{}) error {
+	req, err := http.NewRequest(method, "http://docker"+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the Docker Engine: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 304 Not Modified: the container is already stopped or started
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotModified {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("docker engine: %s", apiErr.Message)
+		}
+		return fmt.Errorf("docker engine: %s", resp.Status)
+	}
+
+	switch result := result.(type) {
+	case nil:
+		return nil
+	case *bytes.Buffer:
+		_, err = result.ReadFrom(resp.Body)
+	default:
+		err = json.NewDecoder(resp.Body).Decode(result)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the Docker Engine response: %w", err)
+	}
+	return nil
+}
+
+// demuxLogs strips the stream headers the Engine puts in the logs of containers without a
+// TTY: each frame is [stream, 0, 0, 0, size (4 bytes, big endian)] followed by size bytes.
+// Logs of TTY containers are returned unchanged.
+func demuxLogs(data []byte) []byte {
+	var out []byte
+	rest := data
+	for len(rest) > 0 {
+		if len(rest) < 8 || rest[0] > 2 || rest[1] != 0 || rest[2] != 0 || rest[3] != 0 {
+			// Not a multiplexed stream
+			return data
+		}
+		size := int(binary.BigEndian.Uint32(rest[4:8]))
+		if len(rest) < 8+size {
+			return data
+		}
+		out = append(out, rest[8:8+size]...)
+		rest = rest[8+size:]
+	}
+	return out
+}
+
+// serviceUsage computes a service's usage from a stats sample the way 'docker stats' does:
+// CPU relative to one core, memory without the reclaimable page cache
+func serviceUsage(service string, stats *engineStats) metrics.Service {
+	usage := metrics.Service{Name: service, MemoryLimitBytes: stats.MemoryStats.Limit}
+
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	cpus := float64(stats.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		usage.CPUPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	// cgroup v1 reports total_inactive_file, v2 inactive_file
+	usage.MemoryBytes = stats.MemoryStats.Usage
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if cache, ok := stats.MemoryStats.Stats[key]; ok && cache < usage.MemoryBytes {
+			usage.MemoryBytes -= cache
+			break
+		}
+	}
+
+	return usage
+}
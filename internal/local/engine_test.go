@@ -0,0 +1,254 @@
+package local
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/testutil"
+)
+
+// fakeEngine is a Docker Engine API serving a fixed set of containers
+type fakeEngine struct {
+	mu       sync.Mutex
+	running  map[string]bool
+	requests []string
+}
+
+// fakeContainers are the compose containers of project "myproj", plus one of another project
+var fakeContainers = []struct {
+	id, project, service string
+}{
+	{"c-rt", "myproj", "realtime"},
+	{"c-db", "myproj", "db"},
+	{"c-other", "other", "db"},
+}
+
+// newFakeEngine starts a fake Engine on a unix socket and returns a runner using it
+func newFakeEngine(t *testing.T, compose ComposeRunner) (*fakeEngine, *EngineRunner) {
+	t.Helper()
+	engine := &fakeEngine{running: map[string]bool{"c-db": true, "c-other": true}}
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets are not available: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(engine)
+	srv.Listener = listener
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	runner, err := NewEngineRunner("unix://"+socket, compose)
+	if err != nil {
+		t.Fatalf("NewEngineRunner failed: %v", err)
+	}
+	return engine, runner
+}
+
+func (e *fakeEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests = append(e.requests, r.Method+" "+r.URL.Path)
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/containers/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	id, action, _ := strings.Cut(path, "/")
+
+	switch {
+	case id == "json":
+		var filters map[string][]string
+		json.Unmarshal([]byte(r.URL.Query().Get("filters")), &filters)
+		var list []map[string]interface{}
+		for _, c := range fakeContainers {
+			if filters["label"][0] != composeProjectLabel+"="+c.project {
+				continue
+			}
+			if !e.running[c.id] && r.URL.Query().Get("all") != "true" {
+				continue
+			}
+			list = append(list, map[string]interface{}{
+				"Id":     c.id,
+				"Labels": map[string]string{composeProjectLabel: c.project, composeServiceLabel: c.service},
+			})
+		}
+		json.NewEncoder(w).Encode(list)
+
+	case action == "stop":
+		e.running[id] = false
+		w.WriteHeader(http.StatusNoContent)
+
+	case action == "restart":
+		if id == "c-missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"No such container: c-missing"}`)
+			return
+		}
+		e.running[id] = true
+		w.WriteHeader(http.StatusNoContent)
+
+	case action == "logs":
+		for _, line := range []string{"starting " + id + "\n", "ready\n"} {
+			header := make([]byte, 8)
+			header[0] = 1
+			binary.BigEndian.PutUint32(header[4:], uint32(len(line)))
+			w.Write(append(header, line...))
+		}
+
+	case action == "stats":
+		fmt.Fprint(w, `{
+			"cpu_stats": {"cpu_usage": {"total_usage": 300}, "system_cpu_usage": 2000, "online_cpus": 2},
+			"precpu_stats": {"cpu_usage": {"total_usage": 100}, "system_cpu_usage": 1000},
+			"memory_stats": {"usage": 1000, "limit": 4000, "stats": {"inactive_file": 200}}
+		}`)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// calls returns the requests served so far and forgets them
+func (e *fakeEngine) calls() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	requests := e.requests
+	e.requests = nil
+	return requests
+}
+
+func TestEngineRunner_StopAndRestart(t *testing.T) {
+	engine, runner := newFakeEngine(t, nil)
+
+	running, err := runner.Running("myproj", "")
+	if err != nil || !running {
+		t.Fatalf("Running() = %v, %v; want true", running, err)
+	}
+
+	if err := runner.Stop("myproj", ""); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	// Only the running container of the project is stopped
+	want := []string{"GET /containers/json", "GET /containers/json", "POST /containers/c-db/stop"}
+	if got := engine.calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+	if running, _ := runner.Running("myproj", ""); running {
+		t.Error("project should not be running after Stop")
+	}
+	if !engine.running["c-other"] {
+		t.Error("Stop must not touch other projects")
+	}
+	engine.calls()
+
+	if err := runner.Restart("myproj", "", "realtime"); err != nil {
+		t.Fatalf("Restart failed: %v", err)
+	}
+	want = []string{"GET /containers/json", "POST /containers/c-rt/restart"}
+	if got := engine.calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+
+	services, err := runner.RunningServices("myproj", "")
+	if err != nil || !reflect.DeepEqual(services, []string{"realtime"}) {
+		t.Errorf("RunningServices() = %v, %v; want [realtime]", services, err)
+	}
+}
+
+func TestEngineRunner_Logs(t *testing.T) {
+	_, runner := newFakeEngine(t, nil)
+
+	logs, err := runner.Logs("myproj", "", 10)
+	if err != nil {
+		t.Fatalf("Logs failed: %v", err)
+	}
+	want := "db       | starting c-db\n" +
+		"db       | ready\n" +
+		"realtime | starting c-rt\n" +
+		"realtime | ready\n"
+	if logs != want {
+		t.Errorf("Logs() = %q, want %q", logs, want)
+	}
+}
+
+func TestEngineRunner_Stats(t *testing.T) {
+	_, runner := newFakeEngine(t, nil)
+
+	services, err := runner.Stats("myproj", "")
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if len(services) != 1 || services[0].Name != "db" {
+		t.Fatalf("Stats() = %+v, want the running db container", services)
+	}
+	// (300-100) / (2000-1000) * 2 CPUs
+	if math.Abs(services[0].CPUPercent-40) > 1e-9 {
+		t.Errorf("CPUPercent = %v, want 40", services[0].CPUPercent)
+	}
+	if services[0].MemoryBytes != 800 || services[0].MemoryLimitBytes != 4000 {
+		t.Errorf("memory = %d / %d, want 800 / 4000", services[0].MemoryBytes, services[0].MemoryLimitBytes)
+	}
+}
+
+func TestEngineRunner_DelegatesUpAndDown(t *testing.T) {
+	exec := &testutil.RecordingExecutor{}
+	engine, runner := newFakeEngine(t, NewCLIRunner(exec))
+	directory := setupStartableProject(t)
+
+	if err := runner.Up("myproj", directory, "db"); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if err := runner.Down("myproj", directory, false); err != nil {
+		t.Fatalf("Down failed: %v", err)
+	}
+
+	base := "docker compose -p myproj -f " + ComposeFileName
+	want := []string{base + " up -d db", base + " down --remove-orphans"}
+	if got := exec.CommandLines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+	if got := engine.calls(); len(got) != 0 {
+		t.Errorf("Up and Down should not call the Engine API, got %q", got)
+	}
+}
+
+func TestEngineRunner_Errors(t *testing.T) {
+	_, runner := newFakeEngine(t, nil)
+
+	if err := runner.do(http.MethodPost, "/containers/c-missing/restart", nil); err == nil || err.Error() != "docker engine: No such container: c-missing" {
+		t.Errorf("expected the Engine's message, got %v", err)
+	}
+}
+
+func TestNewEngineRunner_DockerHost(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://127.0.0.1:2375")
+	if _, err := NewEngineRunner("", nil); err == nil {
+		t.Error("expected an error for a TCP DOCKER_HOST")
+	}
+	if _, err := NewEngineRunner("/run/docker.sock", nil); err != nil {
+		t.Errorf("an explicit socket should override DOCKER_HOST: %v", err)
+	}
+}
+
+func TestDemuxLogs(t *testing.T) {
+	plain := []byte("tty output\n")
+	if got := demuxLogs(plain); string(got) != string(plain) {
+		t.Errorf("demuxLogs(tty) = %q", got)
+	}
+
+	frame := append([]byte{2, 0, 0, 0, 0, 0, 0, 4}, "err\n"...)
+	if got := demuxLogs(frame); string(got) != "err\n" {
+		t.Errorf("demuxLogs(frame) = %q", got)
+	}
+}
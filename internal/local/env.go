@@ -0,0 +1,81 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// GetEnvPath returns the path of a project's .env file
+func GetEnvPath(directory string) string {
+	return filepath.Join(GetDockerDir(directory), ".env")
+}
+
+// LoadProjectEnv loads a project's .env file
+func LoadProjectEnv(directory string) (*EnvFile, error) {
+	return LoadEnvFile(GetEnvPath(directory))
+}
+
+// KnownEnvKeys returns the keys declared in the project's .env.example together with
+// any keys already present in its .env file
+func KnownEnvKeys(directory string) (map[string]bool, error) {
+	known := make(map[string]bool)
+
+	example, err := LoadEnvFile(filepath.Join(GetDockerDir(directory), ".env.example"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if example != nil {
+		for _, key := range example.Keys() {
+			known[key] = true
+		}
+	}
+
+	env, err := LoadProjectEnv(directory)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range env.Keys() {
+		known[key] = true
+	}
+
+	return known, nil
+}
+
+// AffectedServices returns the compose services whose configuration references any of the keys
+func AffectedServices(directory string, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	compose, err := LoadComposeFile(filepath.Join(GetDockerDir(directory), ComposeFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	services := compose.ServicesReferencing(keys...)
+	sort.Strings(services)
+	return services, nil
+}
+
+// GetServiceRoleKey returns the project's service role key from its .env file.
+// If the key is missing it is signed from JWT_SECRET, the same way it was generated on setup.
+func GetServiceRoleKey(directory string) (string, error) {
+	env, err := LoadProjectEnv(directory)
+	if err != nil {
+		return "", err
+	}
+
+	if key, ok := env.Get("SERVICE_ROLE_KEY"); ok && key != "" {
+		return key, nil
+	}
+
+	secret, ok := env.Get("JWT_SECRET")
+	if !ok || secret == "" {
+		return "", fmt.Errorf("neither SERVICE_ROLE_KEY nor JWT_SECRET is set in %s", GetEnvPath(directory))
+	}
+
+	return GenerateJWT(secret, "service_role")
+}
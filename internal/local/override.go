@@ -0,0 +1,457 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ComposeFileName is the upstream Supabase compose file, which supactl never modifies
+	ComposeFileName = "docker-compose.yml"
+
+	// ComposeOverrideFileName is the project-specific override supactl generates next to it
+	ComposeOverrideFileName = "docker-compose.supactl.yml"
+
+	composeOverrideHeader = "Generated by supactl. Do not edit: this file is rewritten whenever the project changes.\n" +
+		"It is layered over docker-compose.yml so the upstream checkout stays pristine."
+)
+
+// composeExpectedServices are the services every supported Supabase compose file defines
+var composeExpectedServices = []string{"studio", "kong", "auth", "rest", "realtime", "storage", "meta", "db"}
+
+// composePortBinding maps a container port of a service to one of the project's host ports.
+// Services are tried in order; the first one that publishes the container port is updated.
+type composePortBinding struct {
+	services []string
+	target   int
+	hostPort func(*Ports) int
+	required bool
+	// envVar is the .env variable that drives the mapping in versions that interpolate the container port
+	envVar string
+}
+
+// composePortBindings lists the published ports supactl assigns per project
+var composePortBindings = []composePortBinding{
+	{services: []string{"kong"}, target: 8000, hostPort: func(p *Ports) int { return p.API }, required: true},
+	{services: []string{"kong"}, target: 8443, hostPort: func(p *Ports) int { return p.KongHTTPS }, required: true},
+	// Newer versions expose Postgres through the supavisor pooler instead of the db service
+	{services: []string{"supavisor", "db"}, target: 5432, hostPort: func(p *Ports) int { return p.DB }, required: true, envVar: "POSTGRES_PORT"},
+	{services: []string{"supavisor"}, target: 6543, hostPort: func(p *Ports) int { return p.Pooler }},
+	{services: []string{"studio"}, target: 3000, hostPort: func(p *Ports) int { return p.Studio }},
+	{services: []string{"analytics"}, target: 4000, hostPort: func(p *Ports) int { return p.Analytics }},
+	{services: []string{"mail", "inbucket"}, target: 9000, hostPort: func(p *Ports) int { return p.Inbucket }},
+	{services: []string{"mail", "inbucket"}, target: 2500, hostPort: func(p *Ports) int { return p.SMTP }},
+	{services: []string{"mail", "inbucket"}, target: 1100, hostPort: func(p *Ports) int { return p.POP3 }},
+}
+
+// GetDockerDir returns the docker directory of a project
+func GetDockerDir(directory string) string {
+	return filepath.Join(directory, "supabase", "docker")
+}
+
+// GetFunctionsDir returns the directory the edge runtime serves functions from
+func GetFunctionsDir(directory string) string {
+	return filepath.Join(GetDockerDir(directory), "volumes", "functions")
+}
+
+// CheckServices returns an error naming the first of services the project's upstream
+// docker-compose.yml does not define
+func CheckServices(directory string, services []string) error {
+	compose, err := LoadComposeFile(filepath.Join(GetDockerDir(directory), ComposeFileName))
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		if !compose.HasService(service) {
+			return fmt.Errorf("service '%s' not found (available: %s)", service, strings.Join(compose.Services(), ", "))
+		}
+	}
+	return nil
+}
+
+// ComposeArgs returns the docker arguments that select a project's compose project and files,
+// followed by args. The override file is only included once it has been generated.
+func ComposeArgs(projectID, directory string, args ...string) []string {
+	composeArgs := []string{"compose", "-p", projectID, "-f", ComposeFileName}
+	if HasComposeOverride(directory) {
+		composeArgs = append(composeArgs, "-f", ComposeOverrideFileName)
+	}
+	return append(composeArgs, args...)
+}
+
+// HasComposeOverride reports whether the project's override file has been generated
+func HasComposeOverride(directory string) bool {
+	_, err := os.Stat(filepath.Join(GetDockerDir(directory), ComposeOverrideFileName))
+	return err == nil
+}
+
+// WriteComposeOverride generates docker-compose.supactl.yml for a project from the upstream
+// docker-compose.yml. It returns the expected services (or required port mappings) that were
+// not found in the upstream file.
+func WriteComposeOverride(projectID string, project *Project) ([]string, error) {
+	dockerDir := GetDockerDir(project.Directory)
+
+	upstream, err := LoadComposeFile(filepath.Join(dockerDir, ComposeFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	override, missing, err := buildComposeOverride(upstream, projectID, project)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := override.Save(filepath.Join(dockerDir, ComposeOverrideFileName)); err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
+// SyncComposeOverride regenerates a project's override file so it reflects the profile and
+// resource limits recorded for the project. Projects still modified in place are left alone
+// until they are migrated; that is an error if they have a profile or limits to apply.
+func SyncComposeOverride(projectID string, project *Project) error {
+	if !HasComposeOverride(project.Directory) {
+		if len(DisabledServices(project.Profile)) > 0 || len(project.Resources) > 0 {
+			return fmt.Errorf("'%s' still uses a modified docker-compose.yml, so its profile and resource limits cannot be applied; run 'supactl local migrate %s' first", projectID, projectID)
+		}
+		return nil
+	}
+	_, err := WriteComposeOverride(projectID, project)
+	return err
+}
+
+// buildComposeOverride derives the override document for a project from the upstream compose file.
+// The upstream document is modified in memory only.
+func buildComposeOverride(upstream *ComposeFile, projectID string, project *Project) (*ComposeFile, []string, error) {
+	missing, err := applyProjectToCompose(upstream, projectID, &project.Ports)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	disabled := make(map[string]bool)
+	for _, service := range DisabledServices(project.Profile) {
+		if upstream.HasService(service) {
+			disabled[service] = true
+		}
+	}
+
+	override := NewComposeFile()
+	override.SetHeaderComment(composeOverrideHeader)
+
+	for _, network := range upstream.Networks() {
+		if name := upstream.NetworkName(network); name != "" {
+			override.SetNetworkName(network, name)
+		}
+	}
+
+	for _, service := range upstream.Services() {
+		override.CopyServiceField(upstream, service, "container_name", "")
+		override.CopyServiceField(upstream, service, "labels", "")
+		// Compose appends port lists from override files, so the whole list is replaced instead
+		override.CopyServiceField(upstream, service, "ports", "!override")
+
+		if disabled[service] {
+			override.SetProfiles(service, composeDisabledProfile)
+		} else if upstream.RemoveDependencies(service, disabled) {
+			// Compose refuses to start a service that depends on one outside the active profiles
+			override.CopyServiceField(upstream, service, "depends_on", "!override")
+		}
+
+		if resources, ok := project.Resources[service]; ok {
+			override.SetResourceLimits(service, resources)
+		}
+	}
+
+	return override, missing, nil
+}
+
+// applyProjectToCompose rewrites container names, labels, network names and published ports
+// for a project
+func applyProjectToCompose(compose *ComposeFile, projectID string, ports *Ports) ([]string, error) {
+	var missing []string
+	for _, name := range composeExpectedServices {
+		if !compose.HasService(name) {
+			missing = append(missing, name)
+		}
+	}
+
+	for _, service := range compose.Services() {
+		// Prefix container names with the project ID so multiple instances can coexist
+		if existing := compose.ContainerName(service); existing != "" && !strings.HasPrefix(existing, projectID+"-") {
+			if err := compose.SetContainerName(service, fmt.Sprintf("%s-%s", projectID, existing)); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := compose.SetLabel(service, ProjectLabel, projectID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Compose scopes networks by project name, except those given an explicit name, which
+	// would be shared by every instance
+	for _, network := range compose.Networks() {
+		if name := compose.NetworkName(network); name != "" && !strings.HasPrefix(name, projectID+"_") {
+			compose.SetNetworkName(network, fmt.Sprintf("%s_%s", projectID, name))
+		}
+	}
+
+	for _, binding := range composePortBindings {
+		mapped := false
+		for _, service := range binding.services {
+			if !compose.HasService(service) {
+				continue
+			}
+			if binding.envVar != "" && compose.PublishesVariable(service, binding.envVar) {
+				// The port follows the .env value, which UpdateEnvFile already sets
+				mapped = true
+				break
+			}
+			ok, err := compose.SetPublishedPort(service, binding.target, binding.hostPort(ports))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				mapped = true
+				break
+			}
+		}
+
+		if !mapped && binding.required {
+			missing = append(missing, fmt.Sprintf("%s (port %d)", strings.Join(binding.services, "/"), binding.target))
+		}
+	}
+
+	return missing, nil
+}
+
+// upstreamModifiedFiles are the files older supactl versions rewrote in place,
+// relative to the cloned supabase repository
+var upstreamModifiedFiles = []string{
+	filepath.Join("docker", ComposeFileName),
+	filepath.Join("supabase", "config.toml"),
+}
+
+// IsModifiedInPlace reports whether a project's upstream docker-compose.yml was rewritten
+// in place by an older supactl version (container names carry the project prefix)
+func IsModifiedInPlace(directory, projectID string) (bool, error) {
+	compose, err := LoadComposeFile(filepath.Join(GetDockerDir(directory), ComposeFileName))
+	if err != nil {
+		return false, err
+	}
+
+	for _, service := range compose.Services() {
+		if strings.HasPrefix(compose.ContainerName(service), projectID+"-") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ErrUpstreamEdited is returned by MigrateProject when upstream files carry changes
+// other than the ones older supactl versions made, which restoring them would discard
+var ErrUpstreamEdited = errors.New("upstream files have changes not made by supactl")
+
+// MigrateProject moves a project that was modified in place to the override file layout.
+// Upstream files are restored from the git checkout and the override is regenerated.
+// Unless discardEdits is set, it refuses with ErrUpstreamEdited when restoring would
+// lose the user's own edits. It returns true if upstream files had to be restored.
+func MigrateProject(projectID string, project *Project, discardEdits bool) (bool, []string, error) {
+	modified, err := IsModifiedInPlace(project.Directory, projectID)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if modified {
+		if !discardEdits {
+			edited, err := UpstreamEdits(project.Directory, projectID)
+			if err != nil {
+				return false, nil, err
+			}
+			if len(edited) > 0 {
+				return false, nil, fmt.Errorf("%w: %s", ErrUpstreamEdited, strings.Join(edited, ", "))
+			}
+		}
+
+		if err := restoreUpstreamFiles(project.Directory); err != nil {
+			return false, nil, err
+		}
+	}
+
+	missing, err := WriteComposeOverride(projectID, project)
+	if err != nil {
+		return modified, nil, err
+	}
+
+	return modified, missing, nil
+}
+
+// UpstreamEdits lists the upstream files that differ from the git checkout by more than
+// the container names, labels, ports and config.toml keys older supactl versions rewrote
+func UpstreamEdits(directory, projectID string) ([]string, error) {
+	repoDir := filepath.Join(directory, "supabase")
+
+	var edited []string
+	for _, file := range upstreamModifiedFiles {
+		current, err := os.ReadFile(filepath.Join(repoDir, file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		cmd := exec.Command("git", "-C", repoDir, "show", "HEAD:"+filepath.ToSlash(file))
+		pristine, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from git: %w", file, err)
+		}
+
+		var same bool
+		if filepath.Ext(file) == ".toml" {
+			same = sameConfigTomlIgnoringSupactl(string(pristine), string(current))
+		} else {
+			same, err = sameComposeIgnoringSupactl(pristine, current, projectID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare %s: %w", file, err)
+			}
+		}
+		if !same {
+			edited = append(edited, file)
+		}
+	}
+
+	return edited, nil
+}
+
+// sameComposeIgnoringSupactl compares two compose files after dropping what supactl rewrites:
+// container names, the project label, published ports and the prefix of network names
+func sameComposeIgnoringSupactl(pristine, current []byte, projectID string) (bool, error) {
+	var a, b map[string]interface{}
+	if err := yaml.Unmarshal(pristine, &a); err != nil {
+		return false, err
+	}
+	if err := yaml.Unmarshal(current, &b); err != nil {
+		// Unparseable means someone other than supactl touched it
+		return false, nil
+	}
+
+	stripSupactlChanges(a, projectID)
+	stripSupactlChanges(b, projectID)
+	return reflect.DeepEqual(a, b), nil
+}
+
+func stripSupactlChanges(compose map[string]interface{}, projectID string) {
+	services, _ := compose["services"].(map[string]interface{})
+	for _, value := range services {
+		service, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		delete(service, "container_name")
+
+		switch labels := service["labels"].(type) {
+		case map[string]interface{}:
+			delete(labels, ProjectLabel)
+			if len(labels) == 0 {
+				delete(service, "labels")
+			}
+		case []interface{}:
+			var kept []interface{}
+			for _, label := range labels {
+				if s, ok := label.(string); ok && strings.HasPrefix(s, ProjectLabel+"=") {
+					continue
+				}
+				kept = append(kept, label)
+			}
+			if len(kept) == 0 {
+				delete(service, "labels")
+			} else {
+				service["labels"] = kept
+			}
+		}
+
+		if ports, ok := service["ports"].([]interface{}); ok {
+			for i, port := range ports {
+				switch port := port.(type) {
+				case string:
+					if spec, err := parsePortSpec(port); err == nil && spec.target >= 0 {
+						ports[i] = spec.target
+					}
+				case map[string]interface{}:
+					delete(port, "published")
+				}
+			}
+		}
+	}
+
+	networks, _ := compose["networks"].(map[string]interface{})
+	for _, value := range networks {
+		if network, ok := value.(map[string]interface{}); ok {
+			if name, ok := network["name"].(string); ok {
+				network["name"] = strings.TrimPrefix(name, projectID+"_")
+			}
+		}
+	}
+}
+
+// supactlConfigTomlKeys are the config.toml keys older supactl versions rewrote
+var supactlConfigTomlKeys = map[string]bool{
+	"project_id":  true,
+	"port":        true,
+	"shadow_port": true,
+	"smtp_port":   true,
+	"pop3_port":   true,
+}
+
+// sameConfigTomlIgnoringSupactl compares config.toml line by line, allowing differences
+// only on lines that assign one of supactlConfigTomlKeys
+func sameConfigTomlIgnoringSupactl(pristine, current string) bool {
+	a := strings.Split(strings.TrimRight(pristine, "\n"), "\n")
+	b := strings.Split(strings.TrimRight(current, "\n"), "\n")
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if strings.TrimSpace(a[i]) == strings.TrimSpace(b[i]) {
+			continue
+		}
+		keyA, _, _ := strings.Cut(a[i], "=")
+		keyB, _, _ := strings.Cut(b[i], "=")
+		keyA, keyB = strings.TrimSpace(keyA), strings.TrimSpace(keyB)
+		if keyA != keyB || !supactlConfigTomlKeys[keyA] {
+			return false
+		}
+	}
+	return true
+}
+
+// restoreUpstreamFiles checks out the pristine upstream versions of files older supactl versions modified
+func restoreUpstreamFiles(directory string) error {
+	repoDir := filepath.Join(directory, "supabase")
+
+	for _, file := range upstreamModifiedFiles {
+		// Skip files that don't exist in this upstream version
+		if _, err := os.Stat(filepath.Join(repoDir, file)); os.IsNotExist(err) {
+			continue
+		}
+
+		cmd := exec.Command("git", "-C", repoDir, "checkout", "--", file)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to restore %s from git: %w\n%s", file, err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,446 @@
+package local
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/testutil"
+	"gopkg.in/yaml.v3"
+)
+
+func testPorts() *Ports {
+	return &Ports{
+		API:       55321,
+		DB:        55322,
+		Shadow:    55320,
+		Studio:    55323,
+		Inbucket:  55324,
+		SMTP:      55325,
+		POP3:      55326,
+		Pooler:    55329,
+		Analytics: 55327,
+		KongHTTPS: 55764,
+	}
+}
+
+// testProject returns a project in directory with the test ports
+func testProject(directory string) *Project {
+	return &Project{Directory: directory, Ports: *testPorts()}
+}
+
+// setupFixtureProject lays out a project directory whose upstream compose file is the named fixture
+func setupFixtureProject(t *testing.T, fixture string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "compose", fixture))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	directory := t.TempDir()
+	dockerDir := GetDockerDir(directory)
+	if err := os.MkdirAll(dockerDir, 0755); err != nil {
+		t.Fatalf("failed to create docker dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dockerDir, ComposeFileName), data, 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	return directory
+}
+
+func testReadFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	return string(data)
+}
+
+func TestWriteComposeOverride_Fixtures(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		wantPorts   map[string]map[int]string
+		wantMissing []string
+	}{
+		{
+			fixture: "supabase-2022-10.yml",
+			wantPorts: map[string]map[int]string{
+				"kong":   {8000: "55321", 8443: "55764"},
+				"db":     {5432: "55322"},
+				"studio": {3000: "55323"},
+			},
+		},
+		{
+			fixture: "supabase-2024-03.yml",
+			wantPorts: map[string]map[int]string{
+				"kong":      {8000: "55321", 8443: "55764"},
+				"analytics": {4000: "55327"},
+			},
+		},
+		{
+			fixture: "supabase-2025-06.yml",
+			wantPorts: map[string]map[int]string{
+				"kong":      {8000: "55321", 8443: "55764"},
+				"supavisor": {5432: "55322", 6543: "55329"},
+				"analytics": {4000: "55327"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			directory := setupFixtureProject(t, tt.fixture)
+			upstreamPath := filepath.Join(GetDockerDir(directory), ComposeFileName)
+			before := testReadFile(t, upstreamPath)
+
+			missing, err := WriteComposeOverride("myproj", testProject(directory))
+			if err != nil {
+				t.Fatalf("WriteComposeOverride failed: %v", err)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+
+			if after := testReadFile(t, upstreamPath); after != before {
+				t.Error("upstream docker-compose.yml was modified")
+			}
+
+			overridePath := filepath.Join(GetDockerDir(directory), ComposeOverrideFileName)
+			override, err := LoadComposeFile(overridePath)
+			if err != nil {
+				t.Fatalf("failed to load override: %v", err)
+			}
+
+			for service, ports := range tt.wantPorts {
+				for target, want := range ports {
+					got, ok := override.PublishedPort(service, target)
+					if !ok || got != want {
+						t.Errorf("%s:%d published = %q (found %v), want %q", service, target, got, ok, want)
+					}
+				}
+			}
+
+			upstream, err := LoadComposeFile(upstreamPath)
+			if err != nil {
+				t.Fatalf("failed to load upstream: %v", err)
+			}
+			for _, service := range upstream.Services() {
+				if name := override.ContainerName(service); !strings.HasPrefix(name, "myproj-") {
+					t.Errorf("container_name of %s = %q, want myproj- prefix", service, name)
+				}
+			}
+
+			content := testReadFile(t, overridePath)
+			if !strings.Contains(content, "# Generated by supactl") {
+				t.Error("override is missing its header comment")
+			}
+			if !strings.Contains(content, "ports: !override") {
+				t.Error("override ports should replace the upstream list")
+			}
+			if strings.Count(content, ProjectLabel+": myproj") != len(upstream.Services()) {
+				t.Errorf("expected every service to carry the %s label", ProjectLabel)
+			}
+		})
+	}
+}
+
+func TestWriteComposeOverride_MissingServices(t *testing.T) {
+	directory := t.TempDir()
+	content := `services:
+  kong:
+    image: kong:2.8.1
+    ports:
+      - 8000:8000
+  db:
+    image: supabase/postgres
+`
+	if err := os.MkdirAll(GetDockerDir(directory), 0755); err != nil {
+		t.Fatalf("failed to create docker dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(GetDockerDir(directory), ComposeFileName), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+
+	missing, err := WriteComposeOverride("myproj", testProject(directory))
+	if err != nil {
+		t.Fatalf("WriteComposeOverride failed: %v", err)
+	}
+
+	want := []string{"studio", "auth", "rest", "realtime", "storage", "meta", "kong (port 8443)", "supavisor/db (port 5432)"}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %v, want %v", missing, want)
+	}
+}
+
+func TestWriteComposeOverride_Networks(t *testing.T) {
+	directory := t.TempDir()
+	content := `services:
+  db:
+    image: supabase/postgres
+    networks:
+      - default
+      - back
+networks:
+  default:
+  back:
+    name: supabase_back
+  edge:
+    external: true
+    name: edge
+`
+	testutil.CreateTestFile(t, GetDockerDir(directory), ComposeFileName, content)
+
+	if _, err := WriteComposeOverride("myproj", testProject(directory)); err != nil {
+		t.Fatalf("WriteComposeOverride failed: %v", err)
+	}
+
+	override, err := LoadComposeFile(filepath.Join(GetDockerDir(directory), ComposeOverrideFileName))
+	if err != nil {
+		t.Fatalf("failed to load override: %v", err)
+	}
+	if got, want := override.Networks(), []string{"back"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("override networks = %v, want %v", got, want)
+	}
+	if got := override.NetworkName("back"); got != "myproj_supabase_back" {
+		t.Errorf("back network name = %q, want myproj_supabase_back", got)
+	}
+
+	// Regenerating keeps the prefix single
+	if _, err := WriteComposeOverride("myproj", testProject(directory)); err != nil {
+		t.Fatalf("WriteComposeOverride failed: %v", err)
+	}
+	override, _ = LoadComposeFile(filepath.Join(GetDockerDir(directory), ComposeOverrideFileName))
+	if got := override.NetworkName("back"); got != "myproj_supabase_back" {
+		t.Errorf("back network name after regenerating = %q", got)
+	}
+}
+
+func TestComposeArgs(t *testing.T) {
+	directory := setupFixtureProject(t, "supabase-2025-06.yml")
+
+	got := ComposeArgs("myproj", directory, "ps", "-q")
+	want := []string{"compose", "-p", "myproj", "-f", ComposeFileName, "ps", "-q"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComposeArgs() without override = %v, want %v", got, want)
+	}
+
+	if _, err := WriteComposeOverride("myproj", testProject(directory)); err != nil {
+		t.Fatalf("WriteComposeOverride failed: %v", err)
+	}
+
+	got = ComposeArgs("myproj", directory, "ps", "-q")
+	want = []string{"compose", "-p", "myproj", "-f", ComposeFileName, "-f", ComposeOverrideFileName, "ps", "-q"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComposeArgs() with override = %v, want %v", got, want)
+	}
+}
+
+func TestMigrateProject(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	directory := setupFixtureProject(t, "supabase-2025-06.yml")
+	repoDir := filepath.Join(directory, "supabase")
+	upstreamPath := filepath.Join(GetDockerDir(directory), ComposeFileName)
+	pristine := testReadFile(t, upstreamPath)
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "upstream"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", repoDir}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	// Simulate an older supactl version rewriting the upstream file in place
+	compose, err := LoadComposeFile(upstreamPath)
+	if err != nil {
+		t.Fatalf("failed to load compose file: %v", err)
+	}
+	if _, err := applyProjectToCompose(compose, "myproj", testPorts()); err != nil {
+		t.Fatalf("applyProjectToCompose failed: %v", err)
+	}
+	if err := compose.Save(upstreamPath); err != nil {
+		t.Fatalf("failed to save compose file: %v", err)
+	}
+
+	modified, err := IsModifiedInPlace(directory, "myproj")
+	if err != nil || !modified {
+		t.Fatalf("IsModifiedInPlace() = %v, %v, want true", modified, err)
+	}
+
+	edited, err := UpstreamEdits(directory, "myproj")
+	if err != nil || len(edited) != 0 {
+		t.Fatalf("UpstreamEdits() = %v, %v, want no edits for supactl's own changes", edited, err)
+	}
+
+	// A change of the user's own must not be discarded silently
+	rewritten := testReadFile(t, upstreamPath)
+	if err := os.WriteFile(upstreamPath, []byte(rewritten+"x-user-extension: true\n"), 0644); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+	if _, _, err := MigrateProject("myproj", testProject(directory), false); !errors.Is(err, ErrUpstreamEdited) {
+		t.Fatalf("MigrateProject() error = %v, want ErrUpstreamEdited", err)
+	}
+	if !strings.Contains(testReadFile(t, upstreamPath), "x-user-extension") {
+		t.Fatal("MigrateProject discarded user edits without permission")
+	}
+
+	restored, _, err := MigrateProject("myproj", testProject(directory), true)
+	if err != nil {
+		t.Fatalf("MigrateProject failed: %v", err)
+	}
+	if !restored {
+		t.Error("MigrateProject should report restored upstream files")
+	}
+	if testReadFile(t, upstreamPath) != pristine {
+		t.Error("upstream docker-compose.yml was not restored")
+	}
+	if !HasComposeOverride(directory) {
+		t.Error("override file was not generated")
+	}
+
+	// Migrating again is a no-op for upstream files
+	restored, _, err = MigrateProject("myproj", testProject(directory), false)
+	if err != nil || restored {
+		t.Errorf("second MigrateProject() = %v, %v, want false, nil", restored, err)
+	}
+}
+
+func TestWriteComposeOverride_ProfileAndResources(t *testing.T) {
+	directory := setupFixtureProject(t, "supabase-2025-06.yml")
+	project := testProject(directory)
+	project.Profile = ProfileMinimal
+	project.Resources = map[string]ServiceResources{
+		"db":       {Memory: "1g", CPUs: "2"},
+		"realtime": {Memory: "256m"},
+	}
+
+	if _, err := WriteComposeOverride("myproj", project); err != nil {
+		t.Fatalf("WriteComposeOverride failed: %v", err)
+	}
+
+	content := testReadFile(t, filepath.Join(directory, "supabase", "docker", ComposeOverrideFileName))
+	override, err := ParseComposeFile([]byte(content))
+	if err != nil {
+		t.Fatalf("failed to parse override: %v", err)
+	}
+
+	for _, service := range DisabledServices(ProfileMinimal) {
+		if got := override.service(service); !strings.Contains(nodeString(t, mappingValue(got, "profiles")), composeDisabledProfile) {
+			t.Errorf("%s should be moved to the %s profile", service, composeDisabledProfile)
+		}
+	}
+	if mappingValue(override.service("auth"), "profiles") != nil {
+		t.Error("auth should keep running in the minimal profile")
+	}
+
+	// Dependencies on disabled services are dropped, others are kept
+	if got, want := override.DependsOn("auth"), []string{"db"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("auth depends_on = %v, want %v", got, want)
+	}
+	if got, want := override.DependsOn("storage"), []string{"db", "rest"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("storage depends_on = %v, want %v", got, want)
+	}
+	if !strings.Contains(content, "depends_on: !override") {
+		t.Error("depends_on should replace the upstream mapping")
+	}
+
+	if !strings.Contains(content, "mem_limit: 1g") || !strings.Contains(content, "cpus: 2\n") || !strings.Contains(content, "mem_limit: 256m") {
+		t.Errorf("override is missing the resource limits:\n%s", content)
+	}
+	if strings.Count(content, "mem_limit") != 2 {
+		t.Errorf("only limited services should carry mem_limit:\n%s", content)
+	}
+
+	// The full profile disables nothing
+	project.Profile = ProfileFull
+	if err := SyncComposeOverride("myproj", project); err != nil {
+		t.Fatalf("SyncComposeOverride failed: %v", err)
+	}
+	if content := testReadFile(t, filepath.Join(directory, "supabase", "docker", ComposeOverrideFileName)); strings.Contains(content, "profiles") || strings.Contains(content, "depends_on") {
+		t.Errorf("full profile should not disable services:\n%s", content)
+	}
+}
+
+func TestSyncComposeOverride_NotMigrated(t *testing.T) {
+	directory := setupFixtureProject(t, "supabase-2025-06.yml")
+	project := testProject(directory)
+
+	// Nothing to apply: the project starts as before
+	if err := SyncComposeOverride("myproj", project); err != nil {
+		t.Errorf("SyncComposeOverride without settings failed: %v", err)
+	}
+
+	project.Resources = map[string]ServiceResources{"db": {Memory: "1g"}}
+	err := SyncComposeOverride("myproj", project)
+	if err == nil || !strings.Contains(err.Error(), "supactl local migrate myproj") {
+		t.Errorf("SyncComposeOverride error = %v, want a hint to migrate", err)
+	}
+
+	project.Resources = nil
+	project.Profile = ProfileMinimal
+	if err := SyncComposeOverride("myproj", project); err == nil {
+		t.Error("SyncComposeOverride should refuse a profile it cannot apply")
+	}
+	if HasComposeOverride(directory) {
+		t.Error("SyncComposeOverride must not generate the override of an unmigrated project")
+	}
+}
+
+// nodeString serializes a YAML node for assertions
+func nodeString(t *testing.T, node *yaml.Node) string {
+	t.Helper()
+	if node == nil {
+		return ""
+	}
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCheckServices(t *testing.T) {
+	directory := setupFixtureProject(t, "supabase-2025-06.yml")
+
+	if err := CheckServices(directory, []string{"studio", "kong"}); err != nil {
+		t.Errorf("CheckServices() = %v", err)
+	}
+	err := CheckServices(directory, []string{"studio", "pgadmin"})
+	if err == nil || !strings.Contains(err.Error(), "'pgadmin'") || !strings.Contains(err.Error(), "realtime") {
+		t.Errorf("CheckServices() = %v, want an error naming pgadmin and the available services", err)
+	}
+}
+
+func TestSameConfigTomlIgnoringSupactl(t *testing.T) {
+	pristine := "project_id = \"supabase\"\n\n[api]\nenabled = true\nport = 54321\n"
+
+	tests := []struct {
+		name    string
+		current string
+		want    bool
+	}{
+		{"unchanged", pristine, true},
+		{"supactl keys rewritten", "project_id = \"myproj\"\n\n[api]\nenabled = true\nport = 55321", true},
+		{"other key changed", "project_id = \"myproj\"\n\n[api]\nenabled = false\nport = 55321\n", false},
+		{"line added", pristine + "max_rows = 10\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameConfigTomlIgnoringSupactl(pristine, tt.current); got != tt.want {
+				t.Errorf("sameConfigTomlIgnoringSupactl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package local
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Profiles select which compose services of a project start
+const (
+	ProfileMinimal  = "minimal"
+	ProfileStandard = "standard"
+	ProfileFull     = "full"
+)
+
+// Profiles lists the supported profiles, smallest first
+var Profiles = []string{ProfileMinimal, ProfileStandard, ProfileFull}
+
+// profileDisabledServices are the services each profile keeps from starting.
+// Services a Supabase version does not define are ignored.
+var profileDisabledServices = map[string][]string{
+	ProfileMinimal:  {"analytics", "vector", "imgproxy", "functions"},
+	ProfileStandard: {"analytics", "vector"},
+	ProfileFull:     nil,
+}
+
+// composeDisabledProfile is the compose profile disabled services are moved to. It is never
+// activated, so 'docker compose up' skips them.
+const composeDisabledProfile = "supactl-disabled"
+
+// memoryLimitPattern matches the byte values Docker accepts for memory limits (512m, 1g, 1.5GiB)
+var memoryLimitPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)? ?[kKmMgGtT]?[iI]?[bB]?$`)
+
+// ValidateProfile checks that profile is a supported profile name
+func ValidateProfile(profile string) error {
+	if _, ok := profileDisabledServices[profile]; !ok {
+		return fmt.Errorf("unknown profile '%s' (use %s)", profile, strings.Join(Profiles, ", "))
+	}
+	return nil
+}
+
+// DisabledServices returns the services a profile keeps from starting; empty means ProfileFull
+func DisabledServices(profile string) []string {
+	return profileDisabledServices[profile]
+}
+
+// ValidateMemoryLimit checks a memory limit such as 512m or 1g
+func ValidateMemoryLimit(value string) error {
+	if !memoryLimitPattern.MatchString(value) {
+		return fmt.Errorf("invalid memory limit '%s' (use a size like 512m or 1g)", value)
+	}
+	return nil
+}
+
+// ValidateCPULimit checks a CPU limit, a positive number of CPUs such as 0.5 or 2
+func ValidateCPULimit(value string) error {
+	cpus, err := strconv.ParseFloat(value, 64)
+	if err != nil || cpus <= 0 {
+		return fmt.Errorf("invalid CPU limit '%s' (use a positive number like 0.5 or 2)", value)
+	}
+	return nil
+}
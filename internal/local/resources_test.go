@@ -0,0 +1,43 @@
+package local
+
+import "testing"
+
+func TestValidateProfile(t *testing.T) {
+	for _, profile := range Profiles {
+		if err := ValidateProfile(profile); err != nil {
+			t.Errorf("ValidateProfile(%q) = %v", profile, err)
+		}
+	}
+	for _, profile := range []string{"", "tiny", "FULL"} {
+		if err := ValidateProfile(profile); err == nil {
+			t.Errorf("ValidateProfile(%q) expected error", profile)
+		}
+	}
+	if DisabledServices("") != nil || DisabledServices(ProfileFull) != nil {
+		t.Error("the full profile should not disable services")
+	}
+}
+
+func TestValidateLimits(t *testing.T) {
+	for _, value := range []string{"512m", "1g", "1.5GiB", "2048", "256MB"} {
+		if err := ValidateMemoryLimit(value); err != nil {
+			t.Errorf("ValidateMemoryLimit(%q) = %v", value, err)
+		}
+	}
+	for _, value := range []string{"", "lots", "1x", "-1g", "1g "} {
+		if err := ValidateMemoryLimit(value); err == nil {
+			t.Errorf("ValidateMemoryLimit(%q) expected error", value)
+		}
+	}
+
+	for _, value := range []string{"0.5", "2", "1.25"} {
+		if err := ValidateCPULimit(value); err != nil {
+			t.Errorf("ValidateCPULimit(%q) = %v", value, err)
+		}
+	}
+	for _, value := range []string{"", "0", "-1", "two"} {
+		if err := ValidateCPULimit(value); err == nil {
+			t.Errorf("ValidateCPULimit(%q) expected error", value)
+		}
+	}
+}
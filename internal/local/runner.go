@@ -0,0 +1,167 @@
+package local
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/qubitquilt/supactl/internal/metrics"
+)
+
+// Docker runners selectable per local context
+const (
+	// RunnerCLI shells out to the docker CLI for every operation
+	RunnerCLI = "cli"
+
+	// RunnerAPI talks to the Docker Engine API over its unix socket, using the docker CLI
+	// only to create and remove compose projects
+	RunnerAPI = "api"
+)
+
+// DefaultDockerSocket is the Docker Engine socket used when neither the context nor
+// DOCKER_HOST names one
+const DefaultDockerSocket = "/var/run/docker.sock"
+
+// CommandExecutor runs external commands. It is the seam between supactl and the
+// programs it shells out to, so tests can record commands instead of running them.
+type CommandExecutor interface {
+	// Run runs a command in dir, streaming its output to the terminal
+	Run(dir, name string, args ...string) error
+
+	// Output runs a command in dir and returns its standard output
+	Output(dir, name string, args ...string) ([]byte, error)
+
+	// CombinedOutput runs a command in dir and returns its standard output and standard error
+	CombinedOutput(dir, name string, args ...string) ([]byte, error)
+
+	// RunInteractive runs a command attached to the terminal's input and output, with env
+	// added to its environment
+	RunInteractive(env []string, name string, args ...string) error
+}
+
+// ComposeRunner manages the containers of a project's compose services
+type ComposeRunner interface {
+	// Up creates and starts the given services (or all services if none), along with the
+	// services they depend on. Containers whose configuration changed are recreated.
+	Up(projectID, directory string, services ...string) error
+
+	// Stop stops the given services (or all services if none) without removing them
+	Stop(projectID, directory string, services ...string) error
+
+	// Restart restarts the given services (or all services if none)
+	Restart(projectID, directory string, services ...string) error
+
+	// Down stops and removes the project's containers, and its volumes if removeVolumes is set
+	Down(projectID, directory string, removeVolumes bool) error
+
+	// Running reports whether any of the project's containers is running
+	Running(projectID, directory string) (bool, error)
+
+	// RunningServices returns the sorted names of the project's services that have a running container
+	RunningServices(projectID, directory string) ([]string, error)
+
+	// Logs returns the most recent lines of the logs of the project's services
+	Logs(projectID, directory string, lines int) (string, error)
+
+	// Stats returns the CPU and memory usage of the project's running containers
+	Stats(projectID, directory string) ([]metrics.Service, error)
+}
+
+// OSExecutor runs commands with os/exec
+type OSExecutor struct{}
+
+// Run runs a command in dir, streaming its output to the terminal
+func (OSExecutor) Run(dir, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// Output runs a command in dir and returns its standard output
+func (OSExecutor) Output(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	return cmd.Output()
+}
+
+// CombinedOutput runs a command in dir and returns its standard output and standard error
+func (OSExecutor) CombinedOutput(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// RunInteractive runs a command attached to the terminal's input and output, with env
+// added to its environment
+func (OSExecutor) RunInteractive(env []string, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// QuietExecutor runs commands like OSExecutor but never writes to the terminal, for callers
+// that own the screen such as the dashboard. The output of a failing command is reported
+// in its error instead.
+type QuietExecutor struct{}
+
+// Run runs a command in dir, capturing its output
+func (QuietExecutor) Run(dir, name string, args ...string) error {
+	_, err := QuietExecutor{}.CombinedOutput(dir, name, args...)
+	return err
+}
+
+// Output runs a command in dir and returns its standard output
+func (QuietExecutor) Output(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	return output, quietError(err, stderr.Bytes())
+}
+
+// CombinedOutput runs a command in dir and returns its standard output and standard error
+func (QuietExecutor) CombinedOutput(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	return output, quietError(err, output)
+}
+
+// RunInteractive fails: an interactive command needs the terminal
+func (QuietExecutor) RunInteractive(env []string, name string, args ...string) error {
+	return fmt.Errorf("cannot run %s interactively here", name)
+}
+
+// quietError adds the last line a failed command printed, usually the reason, to its error
+func quietError(err error, output []byte) error {
+	if err == nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return fmt.Errorf("%w: %s", err, last)
+	}
+	return err
+}
+
+// NewComposeRunner returns the runner of the given kind (RunnerCLI when empty), running
+// docker commands through executor. socket is the Docker Engine socket for RunnerAPI;
+// empty for DOCKER_HOST or DefaultDockerSocket.
+func NewComposeRunner(kind, socket string, executor CommandExecutor) (ComposeRunner, error) {
+	switch kind {
+	case "", RunnerCLI:
+		return NewCLIRunner(executor), nil
+	case RunnerAPI:
+		return NewEngineRunner(socket, NewCLIRunner(executor))
+	default:
+		return nil, fmt.Errorf("unknown docker runner '%s' (use %s or %s)", kind, RunnerCLI, RunnerAPI)
+	}
+}
@@ -0,0 +1,156 @@
+package local
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/metrics"
+	"github.com/qubitquilt/supactl/internal/testutil"
+)
+
+func TestCLIRunner_Running(t *testing.T) {
+	exec := &testutil.RecordingExecutor{}
+	runner := NewCLIRunner(exec)
+
+	running, err := runner.Running("myproj", "/srv/myproj")
+	if err != nil || running {
+		t.Errorf("Running() = %v, %v; want false with no container IDs", running, err)
+	}
+
+	exec.On("docker compose -p myproj", "abc123\n", nil)
+	if running, err := runner.Running("myproj", "/srv/myproj"); err != nil || !running {
+		t.Errorf("Running() = %v, %v; want true", running, err)
+	}
+
+	calls := exec.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 commands, got %v", exec.CommandLines())
+	}
+	if want := GetDockerDir("/srv/myproj"); calls[0].Dir != want {
+		t.Errorf("ran in %q, want %q", calls[0].Dir, want)
+	}
+	if want := "docker compose -p myproj -f " + ComposeFileName + " ps -q"; calls[0].String() != want {
+		t.Errorf("ran %q, want %q", calls[0].String(), want)
+	}
+}
+
+func TestCLIRunner_Logs(t *testing.T) {
+	exec := &testutil.RecordingExecutor{}
+	exec.On("docker compose", "db | ready\n", nil)
+	runner := NewCLIRunner(exec)
+
+	logs, err := runner.Logs("myproj", "/srv/myproj", 50)
+	if err != nil {
+		t.Fatalf("Logs failed: %v", err)
+	}
+	if logs != "db | ready\n" {
+		t.Errorf("Logs() = %q", logs)
+	}
+	if got := exec.CommandLines()[0]; !strings.HasSuffix(got, " logs --tail 50") {
+		t.Errorf("ran %q", got)
+	}
+
+	exec.On("docker compose", "", errors.New("exit status 1"))
+	if _, err := runner.Logs("myproj", "/srv/myproj", 50); err == nil || !strings.Contains(err.Error(), "failed to get logs") {
+		t.Errorf("expected a wrapped error, got %v", err)
+	}
+}
+
+func TestCLIRunner_Stats(t *testing.T) {
+	exec := &testutil.RecordingExecutor{}
+	exec.On("docker compose", "myproj-db-1\tdb\n", nil)
+	exec.On("docker stats", `{"Name":"myproj-db-1","CPUPerc":"12.50%","MemUsage":"100MiB / 1GiB"}`+"\n", nil)
+	runner := NewCLIRunner(exec)
+
+	services, err := runner.Stats("myproj", "/srv/myproj")
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	want := []metrics.Service{{Name: "db", CPUPercent: 12.5, MemoryBytes: 100 << 20, MemoryLimitBytes: 1 << 30}}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("Stats() = %+v, want %+v", services, want)
+	}
+	if got := exec.CommandLines()[1]; got != "docker stats --no-stream --format {{json .}} myproj-db-1" {
+		t.Errorf("ran %q", got)
+	}
+}
+
+func TestCLIRunner_StatsNotRunning(t *testing.T) {
+	exec := &testutil.RecordingExecutor{}
+	services, err := NewCLIRunner(exec).Stats("myproj", "/srv/myproj")
+	if err != nil || services != nil {
+		t.Errorf("Stats() = %v, %v; want no services", services, err)
+	}
+	if len(exec.Calls()) != 1 {
+		t.Errorf("docker stats should not run without containers, ran %v", exec.CommandLines())
+	}
+}
+
+func TestNewComposeRunner(t *testing.T) {
+	for _, kind := range []string{"", RunnerCLI} {
+		runner, err := NewComposeRunner(kind, "", OSExecutor{})
+		if err != nil {
+			t.Fatalf("NewComposeRunner(%q) failed: %v", kind, err)
+		}
+		if _, ok := runner.(*CLIRunner); !ok {
+			t.Errorf("NewComposeRunner(%q) = %T, want *CLIRunner", kind, runner)
+		}
+	}
+
+	runner, err := NewComposeRunner(RunnerAPI, "/tmp/docker.sock", OSExecutor{})
+	if err != nil {
+		t.Fatalf("NewComposeRunner(api) failed: %v", err)
+	}
+	if _, ok := runner.(*EngineRunner); !ok {
+		t.Errorf("NewComposeRunner(api) = %T, want *EngineRunner", runner)
+	}
+
+	if _, err := NewComposeRunner("podman", "", OSExecutor{}); err == nil {
+		t.Error("expected an error for an unknown runner")
+	}
+}
+
+func TestQuietExecutor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	// Nothing may reach the terminal
+	stdout, stderr := os.Stdout, os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout, os.Stderr = w, w
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	exec := QuietExecutor{}
+	runErr := exec.Run("", "sh", "-c", "echo pulling; echo 'port is already allocated' >&2; exit 3")
+	okErr := exec.Run("", "sh", "-c", "echo done")
+	output, outputErr := exec.Output("", "sh", "-c", "echo id; echo warning >&2")
+
+	w.Close()
+	os.Stdout, os.Stderr = stdout, stderr
+	leaked, _ := io.ReadAll(r)
+
+	if len(leaked) != 0 {
+		t.Errorf("QuietExecutor wrote to the terminal: %q", leaked)
+	}
+	if runErr == nil || !strings.Contains(runErr.Error(), "exit status 3: port is already allocated") {
+		t.Errorf("Run error = %v, want the command's last line", runErr)
+	}
+	if okErr != nil {
+		t.Errorf("Run failed: %v", okErr)
+	}
+	if outputErr != nil || string(output) != "id\n" {
+		t.Errorf("Output() = %q, %v", output, outputErr)
+	}
+	if err := exec.RunInteractive(nil, "psql"); err == nil {
+		t.Error("RunInteractive should fail")
+	}
+}
@@ -31,8 +31,9 @@ func ValidateProjectID(projectID string) error {
 	return nil
 }
 
-// CloneSupabaseRepo clones the Supabase repository into the specified directory
-func CloneSupabaseRepo(directory string) error {
+// CloneSupabaseRepo clones the Supabase repository into the specified directory.
+// version is a branch or tag to check out; empty for the default branch.
+func CloneSupabaseRepo(directory, version string) error {
 	// Check if directory already exists
 	if _, err := os.Stat(directory); !os.IsNotExist(err) {
 		return fmt.Errorf("directory already exists: %s", directory)
@@ -44,8 +45,14 @@ func CloneSupabaseRepo(directory string) error {
 	}
 
 	// Clone the repository with depth 1 (shallow clone)
-	fmt.Printf("Cloning Supabase repository into %s...\n", directory)
-	cmd := exec.Command("git", "clone", "--depth", "1", supabaseRepoURL, filepath.Join(directory, "supabase"))
+	args := []string{"clone", "--depth", "1"}
+	if version != "" {
+		fmt.Printf("Cloning Supabase repository (%s) into %s...\n", version, directory)
+		args = append(args, "--branch", version)
+	} else {
+		fmt.Printf("Cloning Supabase repository into %s...\n", directory)
+	}
+	cmd := exec.Command("git", append(args, supabaseRepoURL, filepath.Join(directory, "supabase"))...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -96,47 +103,43 @@ func SetupEnvFile(directory string, secrets *Secrets, ports *Ports) error {
 	return nil
 }
 
-// SetupConfigurationFiles updates docker-compose.yml and config.toml
-func SetupConfigurationFiles(directory, projectID string, ports *Ports) error {
-	// Update docker-compose.yml
-	composePath := filepath.Join(directory, "supabase", "docker", "docker-compose.yml")
-	if _, err := os.Stat(composePath); err == nil {
-		fmt.Println("Updating docker-compose.yml...")
-		if err := UpdateDockerComposeFile(composePath, projectID, ports); err != nil {
-			return err
-		}
-	} else {
-		fmt.Printf("Warning: docker-compose.yml not found at %s\n", composePath)
+// SetupConfigurationFiles generates the docker-compose.supactl.yml override for a project.
+// The upstream docker-compose.yml and config.toml are left untouched.
+func SetupConfigurationFiles(projectID string, project *Project) error {
+	fmt.Printf("Generating %s...\n", ComposeOverrideFileName)
+	missing, err := WriteComposeOverride(projectID, project)
+	if err != nil {
+		return err
 	}
-
-	// Update config.toml (if it exists)
-	configPath := filepath.Join(directory, "supabase", "supabase", "config.toml")
-	if _, err := os.Stat(configPath); err == nil {
-		fmt.Println("Updating config.toml...")
-		if err := UpdateConfigToml(configPath, projectID, ports); err != nil {
-			return err
-		}
-	} else {
-		fmt.Println("Note: config.toml not found (this is normal for newer Supabase versions)")
+	for _, service := range missing {
+		fmt.Printf("Warning: expected service %s not found in %s\n", service, ComposeFileName)
 	}
 
 	return nil
 }
 
-// SetupProject orchestrates the full project setup process
-func SetupProject(projectID, directory string, db *Database) (*Secrets, error) {
+// SetupProject orchestrates the full project setup process. version selects the
+// Supabase branch or tag to check out; empty for the default branch. profile selects
+// which services start; empty for ProfileFull.
+func SetupProject(projectID, directory, version, profile string, db *Database) (*Secrets, error) {
 	// Validate project ID
 	if err := ValidateProjectID(projectID); err != nil {
 		return nil, err
 	}
 
+	if profile != "" {
+		if err := ValidateProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if project already exists in database
 	if db.ProjectExists(projectID) {
 		return nil, fmt.Errorf("project '%s' already exists", projectID)
 	}
 
 	// Clone Supabase repository
-	if err := CloneSupabaseRepo(directory); err != nil {
+	if err := CloneSupabaseRepo(directory, version); err != nil {
 		return nil, err
 	}
 
@@ -154,6 +157,8 @@ func SetupProject(projectID, directory string, db *Database) (*Secrets, error) {
 		os.RemoveAll(directory)
 		return nil, err
 	}
+	project.Profile = profile
+	db.Projects[projectID] = *project
 
 	// Setup .env file
 	if err := SetupEnvFile(directory, secrets, &project.Ports); err != nil {
@@ -163,7 +168,7 @@ func SetupProject(projectID, directory string, db *Database) (*Secrets, error) {
 	}
 
 	// Setup configuration files
-	if err := SetupConfigurationFiles(directory, projectID, &project.Ports); err != nil {
+	if err := SetupConfigurationFiles(projectID, project); err != nil {
 		os.RemoveAll(directory)
 		db.RemoveProject(projectID)
 		return nil, err
@@ -16,8 +16,19 @@ type Ports struct {
 
 // Project represents a local Supabase project configuration
 type Project struct {
-	Directory string `json:"directory"`
-	Ports     Ports  `json:"ports"`
+	Directory string            `json:"directory"`
+	Ports     Ports             `json:"ports"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	// Profile selects which services start; empty means ProfileFull
+	Profile string `json:"profile,omitempty"`
+	// Resources are the resource limits of services, keyed by compose service
+	Resources map[string]ServiceResources `json:"resources,omitempty"`
+}
+
+// ServiceResources are the resource limits of a compose service, in Docker's notation
+type ServiceResources struct {
+	Memory string `json:"memory,omitempty"`
+	CPUs   string `json:"cpus,omitempty"`
 }
 
 // Database represents the local projects database structure
@@ -0,0 +1,170 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qubitquilt/supactl/internal/provider"
+)
+
+// Observe collects the current state of all instances of a provider. Configuration is
+// only fetched for instances whose spec manages it.
+func Observe(p provider.InstanceProvider, m *Manifest) (map[string]*ObservedInstance, error) {
+	if err := checkCapabilities(p, m); err != nil {
+		return nil, err
+	}
+
+	instances, err := p.ListInstances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	observed := make(map[string]*ObservedInstance, len(instances))
+	for _, inst := range instances {
+		observed[inst.Name] = &ObservedInstance{Status: inst.Status, Labels: inst.Labels}
+	}
+
+	for _, spec := range m.Instances {
+		current, exists := observed[spec.Name]
+		if !exists || spec.Config == nil {
+			continue
+		}
+
+		config, err := p.(provider.ConfigProvider).GetConfig(spec.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configuration of '%s': %w", spec.Name, err)
+		}
+		current.Config = config
+	}
+
+	return observed, nil
+}
+
+// checkCapabilities fails early if the manifest manages fields the provider cannot change
+func checkCapabilities(p provider.InstanceProvider, m *Manifest) error {
+	_, canConfig := p.(provider.ConfigProvider)
+	_, canLabel := p.(provider.LabelProvider)
+
+	for _, spec := range m.Instances {
+		if spec.Config != nil && !canConfig {
+			return fmt.Errorf("instance '%s': the %s provider does not support configuration", spec.Name, p.ProviderType())
+		}
+		if spec.Labels != nil && !canLabel {
+			return fmt.Errorf("instance '%s': the %s provider does not support labels", spec.Name, p.ProviderType())
+		}
+	}
+
+	return nil
+}
+
+// Apply executes a plan, reporting progress to out. All actions are attempted;
+// the returned error reports how many of them failed.
+func Apply(p provider.InstanceProvider, plan *Plan, out io.Writer) error {
+	failed := 0
+	for _, action := range plan.Actions {
+		if err := applyAction(p, action, out); err != nil {
+			fmt.Fprintf(out, "%s: failed to %s: %v\n", action.Name, action.Type, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d actions failed", failed, len(plan.Actions))
+	}
+	return nil
+}
+
+// applyAction makes the changes of a single action. Configuration is written before
+// the instance is started so new instances come up with their final settings.
+func applyAction(p provider.InstanceProvider, action Action, out io.Writer) error {
+	if action.Type == ActionDelete {
+		if err := p.DeleteInstance(action.Name); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s: deleted\n", action.Name)
+		return nil
+	}
+
+	// Status before any state change
+	status := ""
+	if action.Type == ActionCreate {
+		inst, err := p.CreateInstance(action.Name)
+		if err != nil {
+			return err
+		}
+		status = inst.Status
+		fmt.Fprintf(out, "%s: created\n", action.Name)
+	} else if action.State != "" {
+		// The plan only changes state between running and stopped
+		status = StateRunning
+		if action.State == StateRunning {
+			status = StateStopped
+		}
+	}
+
+	if action.Labels != nil {
+		if err := p.(provider.LabelProvider).SetLabels(action.Name, action.Labels); err != nil {
+			return fmt.Errorf("failed to set labels: %w", err)
+		}
+		fmt.Fprintf(out, "%s: labels updated\n", action.Name)
+	}
+
+	configChanged := len(action.ConfigSet) > 0 || len(action.ConfigUnset) > 0
+	var services []string
+	if configChanged {
+		var err error
+		services, err = p.(provider.ConfigProvider).UpdateConfig(action.Name, action.ConfigSet, action.ConfigUnset)
+		if err != nil {
+			return fmt.Errorf("failed to update configuration: %w", err)
+		}
+		fmt.Fprintf(out, "%s: configuration updated\n", action.Name)
+	}
+
+	switch {
+	case action.State == StateRunning && status == StateStopped:
+		if err := p.StartInstance(action.Name); err != nil {
+			return fmt.Errorf("failed to start: %w", err)
+		}
+		fmt.Fprintf(out, "%s: started\n", action.Name)
+	case action.State == StateStopped && status == StateRunning:
+		if err := p.StopInstance(action.Name); err != nil {
+			return fmt.Errorf("failed to stop: %w", err)
+		}
+		fmt.Fprintf(out, "%s: stopped\n", action.Name)
+	case configChanged:
+		// Running instances need a restart to pick up the new configuration
+		if err := restartForConfig(p, action.Name, services, out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restartForConfig restarts the affected services of an instance if it is running
+func restartForConfig(p provider.InstanceProvider, name string, services []string, out io.Writer) error {
+	inst, err := p.GetInstance(name)
+	if err != nil {
+		return err
+	}
+	if inst.Status != StateRunning {
+		return nil
+	}
+
+	// An empty (non-nil) list means no running service uses the changed keys
+	if services != nil && len(services) == 0 {
+		return nil
+	}
+
+	if err := p.(provider.ConfigProvider).ApplyConfig(name, services); err != nil {
+		return fmt.Errorf("failed to restart: %w", err)
+	}
+
+	if len(services) > 0 {
+		fmt.Fprintf(out, "%s: restarted %s\n", name, strings.Join(services, ", "))
+	} else {
+		fmt.Fprintf(out, "%s: restarted\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/qubitquilt/supactl/internal/local"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest format identifiers
+const (
+	APIVersion = "supactl/v1"
+	Kind       = "InstanceList"
+)
+
+// Desired instance states
+const (
+	StateRunning = "running"
+	StateStopped = "stopped"
+)
+
+// Manifest is the desired state of the instances in one context
+type Manifest struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Instances  []InstanceSpec `yaml:"instances"`
+}
+
+// InstanceSpec is the desired state of a single instance.
+// Fields that are omitted are not managed: apply leaves them as they are.
+type InstanceSpec struct {
+	Name string `yaml:"name"`
+
+	// State is "running" or "stopped"
+	State string `yaml:"state,omitempty"`
+
+	// Labels replace all labels of the instance when set (an empty map removes them)
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// Config sets environment configuration keys; a null value unsets the key.
+	// Keys that are not listed are left untouched.
+	Config map[string]*string `yaml:"config,omitempty"`
+}
+
+// Load reads and validates a manifest file. A path of "-" reads from standard input.
+func Load(path string) (*Manifest, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// Parse parses and validates manifest content. Unknown fields are rejected to catch typos.
+func Parse(data []byte) (*Manifest, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var m Manifest
+	if err := decoder.Decode(&m); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("manifest is empty")
+		}
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Validate checks the manifest header and every instance spec
+func (m *Manifest) Validate() error {
+	if m.APIVersion != APIVersion {
+		return fmt.Errorf("unsupported apiVersion '%s' (expected '%s')", m.APIVersion, APIVersion)
+	}
+	if m.Kind != Kind {
+		return fmt.Errorf("unsupported kind '%s' (expected '%s')", m.Kind, Kind)
+	}
+
+	seen := make(map[string]bool)
+	for i, spec := range m.Instances {
+		if spec.Name == "" {
+			return fmt.Errorf("instances[%d]: name is required", i)
+		}
+		if seen[spec.Name] {
+			return fmt.Errorf("instance '%s' is listed more than once", spec.Name)
+		}
+		seen[spec.Name] = true
+
+		switch spec.State {
+		case "", StateRunning, StateStopped:
+		default:
+			return fmt.Errorf("instance '%s': invalid state '%s' (expected '%s' or '%s')", spec.Name, spec.State, StateRunning, StateStopped)
+		}
+
+		for key := range spec.Config {
+			if err := local.ValidateEnvKey(key); err != nil {
+				return fmt.Errorf("instance '%s': %w", spec.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `apiVersion: supactl/v1
+kind: InstanceList
+instances:
+  - name: web
+    state: running
+    labels:
+      team: core
+    config:
+      SITE_URL: https://example.com
+      SMTP_HOST: null
+  - name: worker
+    labels: {}
+`
+	m, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(m.Instances) != 2 {
+		t.Fatalf("got %d instances, want 2", len(m.Instances))
+	}
+
+	web := m.Instances[0]
+	if web.State != StateRunning || web.Labels["team"] != "core" {
+		t.Errorf("unexpected spec: %+v", web)
+	}
+	if v := web.Config["SITE_URL"]; v == nil || *v != "https://example.com" {
+		t.Errorf("SITE_URL = %v, want https://example.com", v)
+	}
+	if v, ok := web.Config["SMTP_HOST"]; !ok || v != nil {
+		t.Errorf("SMTP_HOST should be present with a nil value, got %v (present %v)", v, ok)
+	}
+
+	worker := m.Instances[1]
+	if worker.Labels == nil || len(worker.Labels) != 0 {
+		t.Errorf("empty labels should be managed (non-nil), got %#v", worker.Labels)
+	}
+	if worker.Config != nil {
+		t.Errorf("omitted config should be unmanaged (nil), got %#v", worker.Config)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	header := "apiVersion: supactl/v1\nkind: InstanceList\n"
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"empty", "", "manifest is empty"},
+		{"wrong apiVersion", "apiVersion: v2\nkind: InstanceList\n", "unsupported apiVersion"},
+		{"wrong kind", "apiVersion: supactl/v1\nkind: Instance\n", "unsupported kind"},
+		{"unknown field", header + "instances:\n  - name: web\n    replicas: 2\n", "replicas"},
+		{"missing name", header + "instances:\n  - state: running\n", "name is required"},
+		{"duplicate name", header + "instances:\n  - name: web\n  - name: web\n", "more than once"},
+		{"invalid state", header + "instances:\n  - name: web\n    state: paused\n", "invalid state"},
+		{"invalid config key", header + "instances:\n  - name: web\n    config:\n      1BAD: x\n", "invalid key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.content))
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
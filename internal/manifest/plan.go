@@ -0,0 +1,280 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ActionType is the kind of change apply makes to an instance
+type ActionType string
+
+// Action types
+const (
+	ActionCreate ActionType = "create"
+	ActionUpdate ActionType = "update"
+	ActionDelete ActionType = "delete"
+)
+
+// ObservedInstance is the current state of an instance as reported by its provider
+type ObservedInstance struct {
+	Status string
+	Labels map[string]string
+	// Config holds the instance's configuration; it is only fetched for instances whose spec sets config
+	Config map[string]string
+}
+
+// Change is a single field difference. Old or New is nil when the field is added or removed.
+type Change struct {
+	Field string
+	Key   string
+	Old   *string
+	New   *string
+}
+
+// Action is the set of changes apply makes to one instance
+type Action struct {
+	Type    ActionType
+	Name    string
+	Changes []Change
+
+	// State is the desired state, or "" if it is not managed or already matches
+	State string
+
+	// Labels is the complete label set to write, or nil if labels don't change
+	Labels map[string]string
+
+	// ConfigSet and ConfigUnset are the configuration changes to make
+	ConfigSet   map[string]string
+	ConfigUnset []string
+}
+
+// Plan is the ordered list of actions needed to make the observed state match a manifest
+type Plan struct {
+	Actions []Action
+}
+
+// Change field names
+const (
+	fieldState  = "state"
+	fieldLabels = "labels"
+	fieldConfig = "config"
+)
+
+// ComputePlan compares a manifest with the observed instances. Instances that exist
+// but are not listed in the manifest are only deleted when prune is true.
+func ComputePlan(m *Manifest, observed map[string]*ObservedInstance, prune bool) *Plan {
+	plan := &Plan{}
+
+	listed := make(map[string]bool)
+	for _, spec := range m.Instances {
+		listed[spec.Name] = true
+
+		current, exists := observed[spec.Name]
+		if !exists {
+			plan.Actions = append(plan.Actions, createAction(spec))
+			continue
+		}
+
+		if action := updateAction(spec, current); len(action.Changes) > 0 {
+			plan.Actions = append(plan.Actions, action)
+		}
+	}
+
+	if prune {
+		var unlisted []string
+		for name := range observed {
+			if !listed[name] {
+				unlisted = append(unlisted, name)
+			}
+		}
+		sort.Strings(unlisted)
+		for _, name := range unlisted {
+			plan.Actions = append(plan.Actions, Action{Type: ActionDelete, Name: name})
+		}
+	}
+
+	return plan
+}
+
+// createAction lists every managed field of a new instance as an addition
+func createAction(spec InstanceSpec) Action {
+	action := Action{Type: ActionCreate, Name: spec.Name, State: spec.State}
+
+	if spec.State != "" {
+		action.Changes = append(action.Changes, Change{Field: fieldState, New: stringPtr(spec.State)})
+	}
+
+	if len(spec.Labels) > 0 {
+		action.Labels = spec.Labels
+		for _, key := range sortedKeys(spec.Labels) {
+			action.Changes = append(action.Changes, Change{Field: fieldLabels, Key: key, New: stringPtr(spec.Labels[key])})
+		}
+	}
+
+	for _, key := range sortedKeys(spec.Config) {
+		if value := spec.Config[key]; value != nil {
+			if action.ConfigSet == nil {
+				action.ConfigSet = make(map[string]string)
+			}
+			action.ConfigSet[key] = *value
+			action.Changes = append(action.Changes, Change{Field: fieldConfig, Key: key, New: stringPtr(*value)})
+		}
+	}
+
+	return action
+}
+
+// updateAction diffs the managed fields of an existing instance
+func updateAction(spec InstanceSpec, current *ObservedInstance) Action {
+	action := Action{Type: ActionUpdate, Name: spec.Name}
+
+	// Instances in transitional or failed states are left alone
+	if (spec.State == StateRunning && current.Status == StateStopped) ||
+		(spec.State == StateStopped && current.Status == StateRunning) {
+		action.State = spec.State
+		action.Changes = append(action.Changes, Change{Field: fieldState, Old: stringPtr(current.Status), New: stringPtr(spec.State)})
+	}
+
+	if spec.Labels != nil {
+		labelChanges := diffMaps(fieldLabels, current.Labels, spec.Labels)
+		if len(labelChanges) > 0 {
+			action.Labels = spec.Labels
+			action.Changes = append(action.Changes, labelChanges...)
+		}
+	}
+
+	for _, key := range sortedKeys(spec.Config) {
+		value := spec.Config[key]
+		old, isSet := current.Config[key]
+
+		switch {
+		case value == nil && isSet:
+			action.ConfigUnset = append(action.ConfigUnset, key)
+			action.Changes = append(action.Changes, Change{Field: fieldConfig, Key: key, Old: stringPtr(old)})
+		case value != nil && (!isSet || old != *value):
+			if action.ConfigSet == nil {
+				action.ConfigSet = make(map[string]string)
+			}
+			action.ConfigSet[key] = *value
+			change := Change{Field: fieldConfig, Key: key, New: stringPtr(*value)}
+			if isSet {
+				change.Old = stringPtr(old)
+			}
+			action.Changes = append(action.Changes, change)
+		}
+	}
+
+	return action
+}
+
+// diffMaps returns the changes needed to turn current into desired
+func diffMaps(field string, current, desired map[string]string) []Change {
+	var changes []Change
+
+	keys := sortedKeys(current)
+	for _, key := range sortedKeys(desired) {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		old, hadOld := current[key]
+		value, hasNew := desired[key]
+
+		switch {
+		case hadOld && !hasNew:
+			changes = append(changes, Change{Field: field, Key: key, Old: stringPtr(old)})
+		case !hadOld && hasNew:
+			changes = append(changes, Change{Field: field, Key: key, New: stringPtr(value)})
+		case old != value:
+			changes = append(changes, Change{Field: field, Key: key, Old: stringPtr(old), New: stringPtr(value)})
+		}
+	}
+
+	return changes
+}
+
+// HasChanges reports whether the plan contains any action
+func (p *Plan) HasChanges() bool {
+	return len(p.Actions) > 0
+}
+
+// Count returns the number of actions of the given type
+func (p *Plan) Count(actionType ActionType) int {
+	count := 0
+	for _, action := range p.Actions {
+		if action.Type == actionType {
+			count++
+		}
+	}
+	return count
+}
+
+// Write prints the plan in a kubectl/terraform-like format. mask is applied to
+// configuration values so secrets can be hidden.
+func (p *Plan) Write(w io.Writer, mask func(key, value string) string) {
+	if !p.HasChanges() {
+		fmt.Fprintln(w, "No changes. Instances match the manifest.")
+		return
+	}
+
+	for _, action := range p.Actions {
+		switch action.Type {
+		case ActionCreate:
+			fmt.Fprintf(w, "+ %s (create)\n", action.Name)
+		case ActionUpdate:
+			fmt.Fprintf(w, "~ %s (update)\n", action.Name)
+		case ActionDelete:
+			fmt.Fprintf(w, "- %s (delete)\n", action.Name)
+		}
+
+		for _, change := range action.Changes {
+			fmt.Fprintf(w, "    %s\n", formatChange(change, mask))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete.\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete))
+}
+
+// formatChange renders a change as "+ field: new", "- field: old" or "~ field: old -> new"
+func formatChange(change Change, mask func(key, value string) string) string {
+	name := change.Field
+	if change.Key != "" {
+		name += "." + change.Key
+	}
+
+	format := func(value *string) string {
+		if change.Field == fieldConfig && mask != nil {
+			return mask(change.Key, *value)
+		}
+		return *value
+	}
+
+	switch {
+	case change.Old == nil:
+		return fmt.Sprintf("+ %s: %s", name, format(change.New))
+	case change.New == nil:
+		return fmt.Sprintf("- %s: %s", name, format(change.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", name, format(change.Old), format(change.New))
+	}
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
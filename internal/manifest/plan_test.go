@@ -0,0 +1,272 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/provider"
+)
+
+func testManifest(t *testing.T) *Manifest {
+	t.Helper()
+	m, err := Parse([]byte(`apiVersion: supactl/v1
+kind: InstanceList
+instances:
+  - name: web
+    state: running
+    labels:
+      team: core
+    config:
+      SITE_URL: https://example.com
+      SMTP_HOST: null
+  - name: new
+    state: running
+    config:
+      JWT_SECRET: s3cret
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return m
+}
+
+func TestComputePlan(t *testing.T) {
+	m := testManifest(t)
+	observed := map[string]*ObservedInstance{
+		"web": {
+			Status: StateStopped,
+			Labels: map[string]string{"team": "data", "old": "x"},
+			Config: map[string]string{"SITE_URL": "http://localhost", "SMTP_HOST": "smtp"},
+		},
+		"legacy": {Status: StateRunning},
+	}
+
+	plan := ComputePlan(m, observed, false)
+	if got := []int{plan.Count(ActionCreate), plan.Count(ActionUpdate), plan.Count(ActionDelete)}; !reflect.DeepEqual(got, []int{1, 1, 0}) {
+		t.Fatalf("counts (create, update, delete) = %v, want [1 1 0]", got)
+	}
+
+	web := plan.Actions[0]
+	if web.Name != "web" || web.State != StateRunning {
+		t.Errorf("web action = %+v", web)
+	}
+	if !reflect.DeepEqual(web.Labels, map[string]string{"team": "core"}) {
+		t.Errorf("web labels = %v", web.Labels)
+	}
+	if !reflect.DeepEqual(web.ConfigSet, map[string]string{"SITE_URL": "https://example.com"}) {
+		t.Errorf("web ConfigSet = %v", web.ConfigSet)
+	}
+	if !reflect.DeepEqual(web.ConfigUnset, []string{"SMTP_HOST"}) {
+		t.Errorf("web ConfigUnset = %v", web.ConfigUnset)
+	}
+
+	if created := plan.Actions[1]; created.Type != ActionCreate || created.Name != "new" {
+		t.Errorf("second action = %+v, want create of 'new'", created)
+	}
+
+	pruned := ComputePlan(m, observed, true)
+	last := pruned.Actions[len(pruned.Actions)-1]
+	if last.Type != ActionDelete || last.Name != "legacy" {
+		t.Errorf("prune should delete 'legacy', got %+v", last)
+	}
+}
+
+func TestComputePlan_NoDrift(t *testing.T) {
+	m := testManifest(t)
+	observed := map[string]*ObservedInstance{
+		"web": {
+			Status: StateRunning,
+			Labels: map[string]string{"team": "core"},
+			Config: map[string]string{"SITE_URL": "https://example.com", "OTHER": "ignored"},
+		},
+		"new": {
+			Status: StateRunning,
+			Labels: map[string]string{"unmanaged": "kept"},
+			Config: map[string]string{"JWT_SECRET": "s3cret"},
+		},
+		// Transitional states are not drift
+		"legacy": {Status: "provisioning"},
+	}
+
+	if plan := ComputePlan(m, observed, false); plan.HasChanges() {
+		var out bytes.Buffer
+		plan.Write(&out, nil)
+		t.Errorf("expected no changes, got:\n%s", out.String())
+	}
+}
+
+func TestPlan_Write(t *testing.T) {
+	m := testManifest(t)
+	observed := map[string]*ObservedInstance{
+		"web":    {Status: StateStopped, Labels: map[string]string{"old": "x"}, Config: map[string]string{}},
+		"legacy": {Status: StateRunning},
+	}
+
+	var out bytes.Buffer
+	mask := func(key, value string) string {
+		if strings.Contains(key, "SECRET") {
+			return "********"
+		}
+		return value
+	}
+	ComputePlan(m, observed, true).Write(&out, mask)
+
+	for _, want := range []string{
+		"~ web (update)",
+		"~ state: stopped -> running",
+		"- labels.old: x",
+		"+ labels.team: core",
+		"+ config.SITE_URL: https://example.com",
+		"+ new (create)",
+		"+ config.JWT_SECRET: ********",
+		"- legacy (delete)",
+		"Plan: 1 to create, 1 to update, 1 to delete.",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "s3cret") {
+		t.Errorf("secret value leaked into plan output:\n%s", out.String())
+	}
+}
+
+// fakeProvider records the calls made by Apply
+type fakeProvider struct {
+	instances map[string]*provider.Instance
+	calls     []string
+	services  []string
+}
+
+func (f *fakeProvider) ListInstances() ([]provider.Instance, error) {
+	var list []provider.Instance
+	for _, inst := range f.instances {
+		list = append(list, *inst)
+	}
+	return list, nil
+}
+
+func (f *fakeProvider) GetInstance(name string) (*provider.Instance, error) {
+	inst, ok := f.instances[name]
+	if !ok {
+		return nil, fmt.Errorf("instance '%s' not found", name)
+	}
+	return inst, nil
+}
+
+func (f *fakeProvider) CreateInstance(name string) (*provider.Instance, error) {
+	f.calls = append(f.calls, "create "+name)
+	f.instances[name] = &provider.Instance{Name: name, Status: StateStopped}
+	return f.instances[name], nil
+}
+
+func (f *fakeProvider) DeleteInstance(name string) error {
+	f.calls = append(f.calls, "delete "+name)
+	delete(f.instances, name)
+	return nil
+}
+
+func (f *fakeProvider) StartInstance(name string) error {
+	f.calls = append(f.calls, "start "+name)
+	return nil
+}
+
+func (f *fakeProvider) StopInstance(name string) error {
+	f.calls = append(f.calls, "stop "+name)
+	return nil
+}
+
+func (f *fakeProvider) RestartInstance(name string) error {
+	f.calls = append(f.calls, "restart "+name)
+	return nil
+}
+
+func (f *fakeProvider) GetLogs(name string, lines int) (string, error) { return "", nil }
+func (f *fakeProvider) ProviderType() string                           { return "fake" }
+
+func (f *fakeProvider) GetConfig(name string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (f *fakeProvider) KnownConfigKeys(name string) (map[string]bool, error) { return nil, nil }
+
+func (f *fakeProvider) UpdateConfig(name string, set map[string]string, unset []string) ([]string, error) {
+	f.calls = append(f.calls, "config "+name)
+	return f.services, nil
+}
+
+func (f *fakeProvider) ApplyConfig(name string, services []string) error {
+	f.calls = append(f.calls, fmt.Sprintf("apply-config %s %v", name, services))
+	return nil
+}
+
+func (f *fakeProvider) SetLabels(name string, labels map[string]string) error {
+	f.calls = append(f.calls, "labels "+name)
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	m := testManifest(t)
+	fake := &fakeProvider{
+		instances: map[string]*provider.Instance{
+			"web":    {Name: "web", Status: StateStopped},
+			"legacy": {Name: "legacy", Status: StateRunning},
+		},
+	}
+
+	observed, err := Observe(fake, m)
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := Apply(fake, ComputePlan(m, observed, true), &out); err != nil {
+		t.Fatalf("Apply failed: %v\n%s", err, out.String())
+	}
+
+	// Configuration is written before instances are started
+	want := []string{
+		"labels web", "config web", "start web",
+		"create new", "config new", "start new",
+		"delete legacy",
+	}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("calls = %v, want %v", fake.calls, want)
+	}
+}
+
+func TestApply_RestartsRunningInstanceOnConfigChange(t *testing.T) {
+	m, err := Parse([]byte(`apiVersion: supactl/v1
+kind: InstanceList
+instances:
+  - name: web
+    config:
+      SITE_URL: https://example.com
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fake := &fakeProvider{
+		instances: map[string]*provider.Instance{"web": {Name: "web", Status: StateRunning}},
+		services:  []string{"auth"},
+	}
+
+	observed, err := Observe(fake, m)
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := Apply(fake, ComputePlan(m, observed, false), &out); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	want := []string{"config web", "apply-config web [auth]"}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("calls = %v, want %v", fake.calls, want)
+	}
+}
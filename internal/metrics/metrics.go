@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Service is the resource usage of one service (container) of an instance
+type Service struct {
+	Name             string  `json:"name"`
+	CPUPercent       float64 `json:"cpu_percent"`
+	MemoryBytes      uint64  `json:"memory_bytes"`
+	MemoryLimitBytes uint64  `json:"memory_limit_bytes,omitempty"`
+}
+
+// Database is the usage of an instance's Postgres database, which is where its data lives
+type Database struct {
+	SizeBytes   uint64 `json:"size_bytes"`
+	Connections int    `json:"connections"`
+}
+
+// Instance is a snapshot of an instance's resource usage
+type Instance struct {
+	Name             string    `json:"name"`
+	CPUPercent       float64   `json:"cpu_percent"`
+	MemoryBytes      uint64    `json:"memory_bytes"`
+	MemoryLimitBytes uint64    `json:"memory_limit_bytes,omitempty"`
+	Services         []Service `json:"services,omitempty"`
+	// Database is nil if the database could not be queried
+	Database    *Database `json:"database,omitempty"`
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+// Aggregate builds an instance snapshot from the usage of its services. CPU and memory are
+// summed; the memory limit is the largest one, since containers share the host's memory.
+func Aggregate(name string, services []Service) *Instance {
+	inst := &Instance{Name: name, Services: services}
+	for _, svc := range services {
+		inst.CPUPercent += svc.CPUPercent
+		inst.MemoryBytes += svc.MemoryBytes
+		if svc.MemoryLimitBytes > inst.MemoryLimitBytes {
+			inst.MemoryLimitBytes = svc.MemoryLimitBytes
+		}
+	}
+	sort.Slice(inst.Services, func(i, j int) bool { return inst.Services[i].Name < inst.Services[j].Name })
+	return inst
+}
+
+// dockerStatsLine is a line of 'docker stats --format "{{json .}}"'
+type dockerStatsLine struct {
+	Name     string `json:"Name"`
+	CPUPerc  string `json:"CPUPerc"`
+	MemUsage string `json:"MemUsage"`
+}
+
+// ParseDockerStats parses the output of 'docker stats --no-stream --format "{{json .}}"'.
+// services maps container names to the compose service they run; containers that are
+// not in it keep their container name.
+func ParseDockerStats(output []byte, services map[string]string) ([]Service, error) {
+	var result []Service
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var stats dockerStatsLine
+		if err := json.Unmarshal([]byte(line), &stats); err != nil {
+			return nil, fmt.Errorf("failed to parse docker stats: %w", err)
+		}
+
+		svc := Service{Name: stats.Name}
+		if name, ok := services[stats.Name]; ok {
+			svc.Name = name
+		}
+
+		cpu, err := ParsePercent(stats.CPUPerc)
+		if err != nil {
+			return nil, fmt.Errorf("container '%s': %w", stats.Name, err)
+		}
+		svc.CPUPercent = cpu
+
+		used, limit, _ := strings.Cut(stats.MemUsage, "/")
+		if svc.MemoryBytes, err = ParseSize(used); err != nil {
+			return nil, fmt.Errorf("container '%s': %w", stats.Name, err)
+		}
+		if strings.TrimSpace(limit) != "" {
+			if svc.MemoryLimitBytes, err = ParseSize(limit); err != nil {
+				return nil, fmt.Errorf("container '%s': %w", stats.Name, err)
+			}
+		}
+
+		result = append(result, svc)
+	}
+
+	return result, scanner.Err()
+}
+
+// ParsePercent parses a percentage such as "12.34%". Docker reports "--" for
+// containers that are not running, which is read as zero.
+func ParsePercent(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "--" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage '%s'", value)
+	}
+	return f, nil
+}
+
+// sizeUnits are the suffixes docker uses, longest first so "MiB" is not read as "B"
+var sizeUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"kB", 1e3}, {"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"B", 1},
+}
+
+// ParseSize parses a size such as "512MiB", "1.5GiB" or "20kB" into bytes
+func ParseSize(value string) (uint64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "--" {
+		return 0, nil
+	}
+
+	for _, unit := range sizeUnits {
+		if number, ok := strings.CutSuffix(value, unit.suffix); ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil || f < 0 {
+				return 0, fmt.Errorf("invalid size '%s'", value)
+			}
+			return uint64(f * unit.multiplier), nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid size '%s'", value)
+}
+
+// FormatBytes formats a size with binary units, like docker does
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]uint64{
+		"0B":       0,
+		"512B":     512,
+		"1KiB":     1024,
+		"12.5MiB":  13107200,
+		"1.5GiB":   1610612736,
+		"20kB":     20000,
+		"3MB":      3000000,
+		" 7.6GiB ": 8160437862,
+		"--":       0,
+	}
+
+	for input, want := range tests {
+		got, err := ParseSize(input)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"12", "MiB", "-1MiB", "1.2.3GiB"} {
+		if _, err := ParseSize(input); err == nil {
+			t.Errorf("ParseSize(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseDockerStats(t *testing.T) {
+	output := []byte(`{"BlockIO":"0B / 0B","CPUPerc":"1.50%","Container":"abc","ID":"abc","MemPerc":"1.00%","MemUsage":"100MiB / 2GiB","Name":"my-app-db","NetIO":"1kB / 2kB","PIDs":"10"}
+{"CPUPerc":"0.25%","MemUsage":"50MiB / 2GiB","Name":"my-app-kong"}
+
+{"CPUPerc":"--","MemUsage":"-- / --","Name":"stray"}
+`)
+	services := map[string]string{"my-app-db": "db", "my-app-kong": "kong"}
+
+	stats, err := ParseDockerStats(output, services)
+	if err != nil {
+		t.Fatalf("ParseDockerStats failed: %v", err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 services, got %+v", stats)
+	}
+	if stats[0].Name != "db" || stats[0].CPUPercent != 1.5 || stats[0].MemoryBytes != 100<<20 || stats[0].MemoryLimitBytes != 2<<30 {
+		t.Errorf("unexpected db stats: %+v", stats[0])
+	}
+	if stats[2].Name != "stray" || stats[2].CPUPercent != 0 || stats[2].MemoryBytes != 0 {
+		t.Errorf("unexpected stats for a stopped container: %+v", stats[2])
+	}
+
+	if _, err := ParseDockerStats([]byte("not json\n"), nil); err == nil {
+		t.Error("expected error for invalid output")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	inst := Aggregate("my-app", []Service{
+		{Name: "kong", CPUPercent: 0.5, MemoryBytes: 50, MemoryLimitBytes: 1000},
+		{Name: "db", CPUPercent: 1.5, MemoryBytes: 100, MemoryLimitBytes: 2000},
+	})
+
+	if inst.Name != "my-app" || inst.CPUPercent != 2 || inst.MemoryBytes != 150 || inst.MemoryLimitBytes != 2000 {
+		t.Errorf("unexpected aggregate: %+v", inst)
+	}
+	if inst.Services[0].Name != "db" || inst.Services[1].Name != "kong" {
+		t.Errorf("services not sorted: %+v", inst.Services)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[uint64]string{
+		0:         "0B",
+		1023:      "1023B",
+		1024:      "1.0KiB",
+		100 << 20: "100.0MiB",
+		3 << 29:   "1.5GiB",
+	}
+	for input, want := range tests {
+		if got := FormatBytes(input); got != want {
+			t.Errorf("FormatBytes(%d) = %s, want %s", input, got, want)
+		}
+	}
+}
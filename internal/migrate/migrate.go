@@ -0,0 +1,143 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDir is where migrations live, relative to a linked directory (same layout as the Supabase CLI)
+var DefaultDir = filepath.Join("supabase", "migrations")
+
+// versionFormat is the timestamp format of migration versions
+const versionFormat = "20060102150405"
+
+// migrationFileRegex matches "<version>_<name>.sql" and "<version>_<name>.down.sql"
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_-]+?)(\.down)?\.sql$`)
+
+// Migration is a versioned SQL migration with an optional down script
+type Migration struct {
+	Version  string
+	Name     string
+	UpPath   string
+	DownPath string
+}
+
+// FileName returns the base name of the up script
+func (m Migration) FileName() string {
+	return filepath.Base(m.UpPath)
+}
+
+// LoadDir reads the migrations in dir, sorted by version. Files that don't follow the
+// naming scheme are ignored.
+func LoadDir(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("migrations directory not found: %s", dir)
+		}
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[string]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, name, isDown := match[1], match[2], match[3] != ""
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration version %s is used by both '%s' and '%s'", version, m.Name, name)
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if isDown {
+			m.DownPath = path
+		} else {
+			m.UpPath = path
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpPath == "" {
+			return nil, fmt.Errorf("migration %s_%s has a down script but no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// New creates an empty migration (and its down script) in dir, versioned with the given time
+func New(dir, name string, now time.Time) (*Migration, error) {
+	return Create(dir, name, fmt.Sprintf("-- Migration: %s\n", name), fmt.Sprintf("-- Revert migration: %s\n", name), now)
+}
+
+// Create writes a migration with the given up script to dir, versioned with the given time.
+// The down script is only written if down is not empty.
+func Create(dir, name, up, down string, now time.Time) (*Migration, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9_]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return nil, fmt.Errorf("migration name must contain letters or digits")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create migrations directory: %w", err)
+	}
+
+	version := now.UTC().Format(versionFormat)
+	m := &Migration{
+		Version: version,
+		Name:    name,
+		UpPath:  filepath.Join(dir, fmt.Sprintf("%s_%s.sql", version, name)),
+	}
+	if down != "" {
+		m.DownPath = filepath.Join(dir, fmt.Sprintf("%s_%s.down.sql", version, name))
+	}
+
+	for _, path := range []string{m.UpPath, m.DownPath} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("migration already exists: %s", path)
+		}
+	}
+
+	if err := os.WriteFile(m.UpPath, []byte(up), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write migration: %w", err)
+	}
+	if m.DownPath != "" {
+		if err := os.WriteFile(m.DownPath, []byte(down), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write migration: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+// readScript returns the contents of a migration script
+func readScript(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	return string(data), nil
+}
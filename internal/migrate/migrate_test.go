@@ -0,0 +1,176 @@
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeStore keeps applied versions in memory and records executed scripts
+type fakeStore struct {
+	applied []string
+	scripts []string
+	failOn  string
+}
+
+func (f *fakeStore) Applied() ([]string, error) {
+	return append([]string(nil), f.applied...), nil
+}
+
+func (f *fakeStore) Apply(m Migration, script string) error {
+	if m.Version == f.failOn {
+		return errors.New("syntax error")
+	}
+	f.applied = append(f.applied, m.Version)
+	f.scripts = append(f.scripts, script)
+	return nil
+}
+
+func (f *fakeStore) Revert(m Migration, script string) error {
+	f.applied = f.applied[:len(f.applied)-1]
+	f.scripts = append(f.scripts, script)
+	return nil
+}
+
+func writeMigrations(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"20240102000000_add_index.sql":         "create index;",
+		"20240101000000_create_todos.sql":      "create table todos();",
+		"20240101000000_create_todos.down.sql": "drop table todos;",
+		"README.md":                            "ignored",
+		"notes.sql":                            "ignored",
+	})
+
+	migrations, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("got %d migrations, want 2", len(migrations))
+	}
+	if migrations[0].Name != "create_todos" || migrations[0].DownPath == "" {
+		t.Errorf("first migration = %+v", migrations[0])
+	}
+	if migrations[1].Name != "add_index" || migrations[1].DownPath != "" {
+		t.Errorf("second migration = %+v", migrations[1])
+	}
+
+	dir = writeMigrations(t, map[string]string{
+		"20240101000000_a.sql": "",
+		"20240101000000_b.sql": "",
+	})
+	if _, err := LoadDir(dir); err == nil || !strings.Contains(err.Error(), "used by both") {
+		t.Errorf("duplicate versions should fail, got %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "supabase", "migrations")
+	now := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+
+	m, err := New(dir, "Create Todos!", now)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if filepath.Base(m.UpPath) != "20240301123045_create_todos.sql" {
+		t.Errorf("up path = %s", m.UpPath)
+	}
+	if filepath.Base(m.DownPath) != "20240301123045_create_todos.down.sql" {
+		t.Errorf("down path = %s", m.DownPath)
+	}
+
+	if _, err := New(dir, "create todos", now); err == nil {
+		t.Error("creating the same migration twice should fail")
+	}
+	if _, err := New(dir, "!!!", now); err == nil {
+		t.Error("a name without letters or digits should fail")
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"20240101000000_one.sql":      "create table one();",
+		"20240101000000_one.down.sql": "drop table one;",
+		"20240102000000_two.sql":      "create table two();",
+		"20240102000000_two.down.sql": "drop table two;",
+		"20240103000000_three.sql":    "create table three();",
+	})
+	migrations, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+
+	store := &fakeStore{applied: []string{"20240101000000"}}
+
+	var out bytes.Buffer
+	count, err := Up(store, migrations, true, &out)
+	if err != nil || count != 2 {
+		t.Fatalf("Up(dry-run) = %d, %v", count, err)
+	}
+	if len(store.applied) != 1 {
+		t.Error("dry run should not apply migrations")
+	}
+	if !strings.Contains(out.String(), "-- 20240102000000_two.sql\ncreate table two();") {
+		t.Errorf("dry run should print pending SQL:\n%s", out.String())
+	}
+
+	store.failOn = "20240103000000"
+	count, err = Up(store, migrations, false, &out)
+	if err == nil || count != 1 {
+		t.Fatalf("Up() = %d, %v; want 1 applied before the failure", count, err)
+	}
+
+	store.failOn = ""
+	if _, err := Up(store, migrations, false, &out); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if !reflect.DeepEqual(store.applied, []string{"20240101000000", "20240102000000", "20240103000000"}) {
+		t.Errorf("applied = %v", store.applied)
+	}
+
+	// The newest migration has no down script
+	if _, err := Down(store, migrations, 1, false, &out); err == nil || !strings.Contains(err.Error(), "no down script") {
+		t.Errorf("Down without a down script should fail, got %v", err)
+	}
+
+	store.applied = store.applied[:2]
+	count, err = Down(store, migrations, 2, false, &out)
+	if err != nil || count != 2 {
+		t.Fatalf("Down() = %d, %v", count, err)
+	}
+	if len(store.applied) != 0 {
+		t.Errorf("applied after down = %v", store.applied)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	migrations := []Migration{{Version: "1", Name: "one"}, {Version: "2", Name: "two"}}
+
+	got := Status(migrations, []string{"1", "0"})
+	want := []StatusEntry{
+		{Version: "1", Name: "one", Applied: true},
+		{Version: "2", Name: "two"},
+		{Version: "0", Applied: true, Missing: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Status() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package migrate
+
+import (
+	"fmt"
+	"io"
+)
+
+// StatusEntry describes one migration known locally, in the database, or both
+type StatusEntry struct {
+	Version string
+	Name    string
+	Applied bool
+	// Missing is true for versions recorded in the database without a local file
+	Missing bool
+}
+
+// Status compares local migrations with the applied versions
+func Status(migrations []Migration, applied []string) []StatusEntry {
+	isApplied := make(map[string]bool, len(applied))
+	for _, version := range applied {
+		isApplied[version] = true
+	}
+
+	local := make(map[string]bool, len(migrations))
+	var entries []StatusEntry
+	for _, m := range migrations {
+		local[m.Version] = true
+		entries = append(entries, StatusEntry{Version: m.Version, Name: m.Name, Applied: isApplied[m.Version]})
+	}
+
+	for _, version := range applied {
+		if !local[version] {
+			entries = append(entries, StatusEntry{Version: version, Applied: true, Missing: true})
+		}
+	}
+
+	return entries
+}
+
+// Pending returns the migrations that have not been applied, in order
+func Pending(migrations []Migration, applied []string) []Migration {
+	isApplied := make(map[string]bool, len(applied))
+	for _, version := range applied {
+		isApplied[version] = true
+	}
+
+	var pending []Migration
+	for _, m := range migrations {
+		if !isApplied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// Up applies all pending migrations in order, each in its own transaction, and returns
+// how many were applied. With dryRun the pending SQL is printed instead of executed.
+func Up(store Store, migrations []Migration, dryRun bool, out io.Writer) (int, error) {
+	applied, err := store.Applied()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range Pending(migrations, applied) {
+		script, err := readScript(m.UpPath)
+		if err != nil {
+			return count, err
+		}
+
+		if dryRun {
+			fmt.Fprintf(out, "-- %s\n%s\n", m.FileName(), script)
+			count++
+			continue
+		}
+
+		fmt.Fprintf(out, "Applying %s...\n", m.FileName())
+		if err := store.Apply(m, script); err != nil {
+			return count, fmt.Errorf("migration %s failed: %w", m.FileName(), err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Down reverts the last steps applied migrations, newest first, using their down scripts.
+// With dryRun the SQL is printed instead of executed.
+func Down(store Store, migrations []Migration, steps int, dryRun bool, out io.Writer) (int, error) {
+	applied, err := store.Applied()
+	if err != nil {
+		return 0, err
+	}
+
+	byVersion := make(map[string]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	count := 0
+	for i := len(applied) - 1; i >= 0 && count < steps; i-- {
+		m, ok := byVersion[applied[i]]
+		if !ok {
+			return count, fmt.Errorf("applied migration %s has no local file", applied[i])
+		}
+		if m.DownPath == "" {
+			return count, fmt.Errorf("migration %s has no down script", m.FileName())
+		}
+
+		script, err := readScript(m.DownPath)
+		if err != nil {
+			return count, err
+		}
+
+		if dryRun {
+			fmt.Fprintf(out, "-- %s\n%s\n", m.FileName(), script)
+			count++
+			continue
+		}
+
+		fmt.Fprintf(out, "Reverting %s...\n", m.FileName())
+		if err := store.Revert(m, script); err != nil {
+			return count, fmt.Errorf("reverting %s failed: %w", m.FileName(), err)
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,105 @@
+package migrate
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Store records which migrations have been applied to a database
+type Store interface {
+	// Applied returns the applied versions in ascending order
+	Applied() ([]string, error)
+
+	// Apply runs an up script and records the version, atomically
+	Apply(m Migration, script string) error
+
+	// Revert runs a down script and removes the version record, atomically
+	Revert(m Migration, script string) error
+}
+
+// The tracking table is compatible with the one the Supabase CLI uses
+const (
+	createTrackingTableSQL = `CREATE SCHEMA IF NOT EXISTS supabase_migrations;
+CREATE TABLE IF NOT EXISTS supabase_migrations.schema_migrations (
+	version text NOT NULL PRIMARY KEY,
+	statements text[],
+	name text
+)`
+	trackingTableExistsSQL = `SELECT to_regclass('supabase_migrations.schema_migrations') IS NOT NULL`
+)
+
+// SQLStore is a Store backed by the supabase_migrations.schema_migrations table
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore creates a store on an open database connection
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// Applied returns the applied versions. A missing tracking table means nothing has been applied;
+// it is only created once a migration runs, so status and dry runs don't modify the database.
+func (s *SQLStore) Applied() ([]string, error) {
+	var exists bool
+	if err := s.db.QueryRow(trackingTableExistsSQL).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check migrations table: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	rows, err := s.db.Query(`SELECT version FROM supabase_migrations.schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []string
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+// Apply runs an up script in a transaction together with its version record
+func (s *SQLStore) Apply(m Migration, script string) error {
+	if _, err := s.db.Exec(createTrackingTableSQL); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	return s.inTransaction(script, `INSERT INTO supabase_migrations.schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+}
+
+// Revert runs a down script in a transaction together with removing its version record
+func (s *SQLStore) Revert(m Migration, script string) error {
+	return s.inTransaction(script, `DELETE FROM supabase_migrations.schema_migrations WHERE version = $1`, m.Version)
+}
+
+// inTransaction runs a script followed by a bookkeeping statement, rolling both back on failure
+func (s *SQLStore) inTransaction(script, record string, args ...interface{}) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Scripts run without parameters so they may contain several statements
+	if _, err := tx.Exec(script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
+}
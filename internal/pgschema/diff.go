@@ -0,0 +1,380 @@
+package pgschema
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeKind describes whether an object was added, removed or modified
+type ChangeKind string
+
+// Change kinds, used as diff prefixes
+const (
+	Added    ChangeKind = "+"
+	Removed  ChangeKind = "-"
+	Modified ChangeKind = "~"
+)
+
+// Change is a difference in one database object
+type Change struct {
+	Kind    ChangeKind
+	Object  string
+	Name    string
+	Details []string
+}
+
+// Diff is the set of changes between two schemas and the SQL that applies them
+type Diff struct {
+	Changes []Change
+
+	// statements are grouped by phase so dependent objects are created in a valid order;
+	// functions come before tables, whose defaults and checks may call them
+	drops      []string
+	dropTables []string
+	functions  []string
+	creates    []string
+	alters     []string
+	indexes    []string
+	security   []string
+	triggers   []string
+	dropFuncs  []string
+}
+
+// Compare returns the changes that turn from into to
+func Compare(from, to *Schema) *Diff {
+	d := &Diff{}
+	d.compareTables(from, to)
+	d.compareIndexes(from, to)
+	d.comparePolicies(from, to)
+	d.compareFunctions(from, to)
+	d.compareTriggers(from, to)
+	return d
+}
+
+// Empty reports whether the schemas are identical
+func (d *Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// SQL returns a migration script that applies the diff
+func (d *Diff) SQL() string {
+	var b strings.Builder
+	for _, group := range [][]string{d.drops, d.dropTables, d.functions, d.creates, d.alters, d.indexes, d.security, d.triggers, d.dropFuncs} {
+		for _, stmt := range group {
+			b.WriteString(strings.TrimRight(stmt, "; \n"))
+			b.WriteString(";\n\n")
+		}
+	}
+	return b.String()
+}
+
+// WriteText prints a readable summary of the changes
+func (d *Diff) WriteText(w io.Writer) {
+	for _, change := range d.Changes {
+		fmt.Fprintf(w, "%s %s %s\n", change.Kind, change.Object, change.Name)
+		for _, detail := range change.Details {
+			fmt.Fprintf(w, "    %s\n", detail)
+		}
+	}
+}
+
+func (d *Diff) add(kind ChangeKind, object, name string, details ...string) {
+	d.Changes = append(d.Changes, Change{Kind: kind, Object: object, Name: name, Details: details})
+}
+
+func (d *Diff) compareTables(from, to *Schema) {
+	for _, key := range unionKeys(from.Tables, to.Tables) {
+		before, after := from.Tables[key], to.Tables[key]
+		switch {
+		case after == nil:
+			d.add(Removed, "table", key)
+			d.dropTables = append(d.dropTables, fmt.Sprintf("DROP TABLE %s", key))
+		case before == nil:
+			d.add(Added, "table", key)
+			d.creates = append(d.creates, createTableSQL(after))
+			for _, c := range after.Constraints {
+				d.indexes = append(d.indexes, addConstraintSQL(key, c))
+			}
+			if after.RLSEnabled {
+				d.security = append(d.security, fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY", key))
+			}
+		default:
+			if details := d.alterTable(before, after); len(details) > 0 {
+				d.add(Modified, "table", key, details...)
+			}
+		}
+	}
+}
+
+// alterTable diffs the columns, constraints and RLS setting of a table that exists on both sides
+func (d *Diff) alterTable(before, after *Table) []string {
+	key := after.QualifiedName()
+	var details []string
+
+	oldColumns := make(map[string]Column)
+	for _, c := range before.Columns {
+		oldColumns[c.Name] = c
+	}
+	newColumns := make(map[string]bool)
+
+	for _, c := range after.Columns {
+		newColumns[c.Name] = true
+		prev, exists := oldColumns[c.Name]
+		if !exists {
+			details = append(details, fmt.Sprintf("+ column %s %s", c.Name, c.Type))
+			d.alters = append(d.alters, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", key, columnSQL(c)))
+			continue
+		}
+
+		column := QuoteIdent(c.Name)
+		if prev.Type != c.Type {
+			details = append(details, fmt.Sprintf("~ column %s type: %s -> %s", c.Name, prev.Type, c.Type))
+			d.alters = append(d.alters, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s", key, column, c.Type, column, c.Type))
+		}
+		if prev.NotNull != c.NotNull {
+			details = append(details, fmt.Sprintf("~ column %s not null: %t -> %t", c.Name, prev.NotNull, c.NotNull))
+			action := "DROP NOT NULL"
+			if c.NotNull {
+				action = "SET NOT NULL"
+			}
+			d.alters = append(d.alters, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", key, column, action))
+		}
+		if prev.Default != c.Default {
+			details = append(details, fmt.Sprintf("~ column %s default: %s -> %s", c.Name, orNone(prev.Default), orNone(c.Default)))
+			if c.Default == "" {
+				d.alters = append(d.alters, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", key, column))
+			} else {
+				d.alters = append(d.alters, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", key, column, c.Default))
+			}
+		}
+	}
+
+	for _, c := range before.Columns {
+		if !newColumns[c.Name] {
+			details = append(details, fmt.Sprintf("- column %s", c.Name))
+			d.alters = append(d.alters, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", key, QuoteIdent(c.Name)))
+		}
+	}
+
+	oldConstraints := make(map[string]string)
+	for _, c := range before.Constraints {
+		oldConstraints[c.Name] = c.Definition
+	}
+	newConstraints := make(map[string]bool)
+	for _, c := range after.Constraints {
+		newConstraints[c.Name] = true
+		prev, exists := oldConstraints[c.Name]
+		switch {
+		case !exists:
+			details = append(details, fmt.Sprintf("+ constraint %s %s", c.Name, c.Definition))
+			d.indexes = append(d.indexes, addConstraintSQL(key, c))
+		case prev != c.Definition:
+			details = append(details, fmt.Sprintf("~ constraint %s: %s -> %s", c.Name, prev, c.Definition))
+			d.drops = append(d.drops, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", key, QuoteIdent(c.Name)))
+			d.indexes = append(d.indexes, addConstraintSQL(key, c))
+		}
+	}
+	for _, c := range before.Constraints {
+		if !newConstraints[c.Name] {
+			details = append(details, fmt.Sprintf("- constraint %s", c.Name))
+			d.drops = append(d.drops, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", key, QuoteIdent(c.Name)))
+		}
+	}
+
+	if before.RLSEnabled != after.RLSEnabled {
+		details = append(details, fmt.Sprintf("~ row level security: %s -> %s", enabled(before.RLSEnabled), enabled(after.RLSEnabled)))
+		action := "DISABLE"
+		if after.RLSEnabled {
+			action = "ENABLE"
+		}
+		d.security = append(d.security, fmt.Sprintf("ALTER TABLE %s %s ROW LEVEL SECURITY", key, action))
+	}
+
+	return details
+}
+
+func (d *Diff) compareIndexes(from, to *Schema) {
+	for _, key := range unionKeys(from.Indexes, to.Indexes) {
+		before, after := from.Indexes[key], to.Indexes[key]
+		switch {
+		case after == nil:
+			d.add(Removed, "index", key)
+			d.drops = append(d.drops, fmt.Sprintf("DROP INDEX %s", key))
+		case before == nil:
+			d.add(Added, "index", key)
+			d.indexes = append(d.indexes, after.Definition)
+		case before.Definition != after.Definition:
+			d.add(Modified, "index", key, "- "+before.Definition, "+ "+after.Definition)
+			d.drops = append(d.drops, fmt.Sprintf("DROP INDEX %s", key))
+			d.indexes = append(d.indexes, after.Definition)
+		}
+	}
+}
+
+func (d *Diff) comparePolicies(from, to *Schema) {
+	for _, key := range unionKeys(from.Policies, to.Policies) {
+		before, after := from.Policies[key], to.Policies[key]
+		switch {
+		case after == nil:
+			d.add(Removed, "policy", key)
+			d.drops = append(d.drops, dropPolicySQL(before))
+		case before == nil:
+			d.add(Added, "policy", key)
+			d.security = append(d.security, createPolicySQL(after))
+		case !reflect.DeepEqual(before, after):
+			d.add(Modified, "policy", key, "- "+createPolicySQL(before), "+ "+createPolicySQL(after))
+			d.drops = append(d.drops, dropPolicySQL(before))
+			d.security = append(d.security, createPolicySQL(after))
+		}
+	}
+}
+
+func (d *Diff) compareFunctions(from, to *Schema) {
+	for _, key := range unionKeys(from.Functions, to.Functions) {
+		before, after := from.Functions[key], to.Functions[key]
+		switch {
+		case after == nil:
+			d.add(Removed, "function", key)
+			d.dropFuncs = append(d.dropFuncs, dropFunctionSQL(key, before))
+		case before == nil:
+			d.add(Added, "function", key)
+			d.functions = append(d.functions, after.Definition)
+		case resultSignature(before) != resultSignature(after):
+			d.add(Modified, "function", key, "~ result: "+resultSignature(before)+" -> "+resultSignature(after))
+			d.functions = append(d.functions, dropFunctionSQL(key, before), after.Definition)
+		case before.Definition != after.Definition:
+			d.add(Modified, "function", key)
+			// pg_get_functiondef emits CREATE OR REPLACE, so the definition replaces the previous one in place
+			d.functions = append(d.functions, after.Definition)
+		}
+	}
+}
+
+// resultSignature describes what a function returns: its result type and output parameters.
+// CREATE OR REPLACE cannot change it.
+func resultSignature(f *Function) string {
+	if f.Procedure {
+		return "procedure"
+	}
+	result := f.Returns
+	if f.ReturnsSet {
+		result = "SETOF " + result
+	}
+	if outputs := f.Outputs(); len(outputs) > 0 {
+		params := make([]string, len(outputs))
+		for i, p := range outputs {
+			params[i] = strings.TrimSpace(p.Name + " " + p.Type)
+		}
+		result += " (" + strings.Join(params, ", ") + ")"
+	}
+	return result
+}
+
+func dropFunctionSQL(key string, f *Function) string {
+	if f.Procedure {
+		return "DROP PROCEDURE " + key
+	}
+	return "DROP FUNCTION " + key
+}
+
+func (d *Diff) compareTriggers(from, to *Schema) {
+	for _, key := range unionKeys(from.Triggers, to.Triggers) {
+		before, after := from.Triggers[key], to.Triggers[key]
+		switch {
+		case after == nil:
+			d.add(Removed, "trigger", key)
+			d.drops = append(d.drops, dropTriggerSQL(before))
+		case before == nil:
+			d.add(Added, "trigger", key)
+			d.triggers = append(d.triggers, after.Definition)
+		case before.Definition != after.Definition:
+			d.add(Modified, "trigger", key, "- "+before.Definition, "+ "+after.Definition)
+			d.drops = append(d.drops, dropTriggerSQL(before))
+			d.triggers = append(d.triggers, after.Definition)
+		}
+	}
+}
+
+func createTableSQL(t *Table) string {
+	columns := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		columns[i] = "    " + columnSQL(c)
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n)", t.QualifiedName(), strings.Join(columns, ",\n"))
+}
+
+func columnSQL(c Column) string {
+	sql := QuoteIdent(c.Name) + " " + c.Type
+	if c.NotNull {
+		sql += " NOT NULL"
+	}
+	if c.Default != "" {
+		sql += " DEFAULT " + c.Default
+	}
+	return sql
+}
+
+func addConstraintSQL(table string, c Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s", table, QuoteIdent(c.Name), c.Definition)
+}
+
+func createPolicySQL(p *Policy) string {
+	roles := make([]string, len(p.Roles))
+	for i, role := range p.Roles {
+		roles[i] = role
+		if role != "public" {
+			roles[i] = QuoteIdent(role)
+		}
+	}
+
+	sql := fmt.Sprintf("CREATE POLICY %s ON %s AS %s FOR %s TO %s",
+		QuoteIdent(p.Name), qualify(p.Schema, p.Table), p.Permissive, p.Command, strings.Join(roles, ", "))
+	if p.Using != "" {
+		sql += fmt.Sprintf(" USING (%s)", p.Using)
+	}
+	if p.Check != "" {
+		sql += fmt.Sprintf(" WITH CHECK (%s)", p.Check)
+	}
+	return sql
+}
+
+func dropPolicySQL(p *Policy) string {
+	return fmt.Sprintf("DROP POLICY %s ON %s", QuoteIdent(p.Name), qualify(p.Schema, p.Table))
+}
+
+func dropTriggerSQL(t *Trigger) string {
+	return fmt.Sprintf("DROP TRIGGER %s ON %s", QuoteIdent(t.Name), qualify(t.Schema, t.Table))
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range []map[string]V{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+func enabled(on bool) string {
+	if on {
+		return "enabled"
+	}
+	return "disabled"
+}
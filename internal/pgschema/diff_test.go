@@ -0,0 +1,170 @@
+package pgschema
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func sourceSchema() *Schema {
+	s := NewSchema()
+	s.Tables[`public.todos`] = &Table{
+		Schema: "public", Name: "todos",
+		Columns: []Column{
+			{Name: "id", Type: "bigint", NotNull: true},
+			{Name: "title", Type: "character varying(100)"},
+			{Name: "legacy", Type: "integer"},
+		},
+		Constraints: []Constraint{{Name: "todos_pkey", Definition: "PRIMARY KEY (id)"}},
+	}
+	s.Tables[`public.old_table`] = &Table{Schema: "public", Name: "old_table", Columns: []Column{{Name: "id", Type: "integer"}}}
+	s.Indexes[`public.todos_title_idx`] = &Index{Schema: "public", Table: "todos", Name: "todos_title_idx",
+		Definition: "CREATE INDEX todos_title_idx ON public.todos USING btree (title)"}
+	s.Functions[`public.touch()`] = &Function{Schema: "public", Name: "touch",
+		Definition: "CREATE OR REPLACE FUNCTION public.touch() RETURNS trigger LANGUAGE plpgsql AS $$ BEGIN RETURN NEW; END $$"}
+	return s
+}
+
+func targetSchema() *Schema {
+	s := NewSchema()
+	s.Tables[`public.todos`] = &Table{
+		Schema: "public", Name: "todos", RLSEnabled: true,
+		Columns: []Column{
+			{Name: "id", Type: "bigint", NotNull: true},
+			{Name: "title", Type: "text", NotNull: true, Default: "''::text"},
+			{Name: "user id", Type: "uuid"},
+		},
+		Constraints: []Constraint{{Name: "todos_pkey", Definition: "PRIMARY KEY (id)"}},
+	}
+	s.Tables[`public.profiles`] = &Table{
+		Schema: "public", Name: "profiles",
+		Columns:     []Column{{Name: "id", Type: "uuid", NotNull: true}},
+		Constraints: []Constraint{{Name: "profiles_pkey", Definition: "PRIMARY KEY (id)"}},
+	}
+	policy := &Policy{Schema: "public", Table: "todos", Name: "Users read own", Permissive: "PERMISSIVE",
+		Command: "SELECT", Roles: []string{"authenticated"}, Using: `(auth.uid() = "user id")`}
+	s.Policies[policy.Key()] = policy
+	trigger := &Trigger{Schema: "public", Table: "todos", Name: "touch_todos",
+		Definition: "CREATE TRIGGER touch_todos BEFORE UPDATE ON public.todos FOR EACH ROW EXECUTE FUNCTION public.touch()"}
+	s.Triggers[trigger.Key()] = trigger
+	s.Functions[`public.touch()`] = &Function{Schema: "public", Name: "touch",
+		Definition: "CREATE OR REPLACE FUNCTION public.touch() RETURNS trigger LANGUAGE plpgsql AS $$ BEGIN NEW.updated_at = now(); RETURN NEW; END $$"}
+	return s
+}
+
+func TestCompare_Text(t *testing.T) {
+	diff := Compare(sourceSchema(), targetSchema())
+	if diff.Empty() {
+		t.Fatal("expected changes")
+	}
+
+	var out bytes.Buffer
+	diff.WriteText(&out)
+
+	for _, want := range []string{
+		"- table public.old_table",
+		"+ table public.profiles",
+		"~ table public.todos",
+		"+ column user id uuid",
+		"- column legacy",
+		"~ column title type: character varying(100) -> text",
+		"~ row level security: disabled -> enabled",
+		"- index public.todos_title_idx",
+		`+ policy public.todos."Users read own"`,
+		"~ function public.touch()",
+		"+ trigger public.todos.touch_todos",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("diff missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestCompare_SQL(t *testing.T) {
+	sql := Compare(sourceSchema(), targetSchema()).SQL()
+
+	ordered := []string{
+		"DROP INDEX public.todos_title_idx;",
+		"DROP TABLE public.old_table;",
+		"CREATE OR REPLACE FUNCTION public.touch()",
+		"CREATE TABLE public.profiles (\n    id uuid NOT NULL\n);",
+		"ALTER TABLE public.todos ALTER COLUMN title TYPE text USING title::text;",
+		"ALTER TABLE public.todos ALTER COLUMN title SET NOT NULL;",
+		"ALTER TABLE public.todos ALTER COLUMN title SET DEFAULT ''::text;",
+		`ALTER TABLE public.todos ADD COLUMN "user id" uuid;`,
+		"ALTER TABLE public.todos DROP COLUMN legacy;",
+		"ALTER TABLE public.profiles ADD CONSTRAINT profiles_pkey PRIMARY KEY (id);",
+		"ALTER TABLE public.todos ENABLE ROW LEVEL SECURITY;",
+		`CREATE POLICY "Users read own" ON public.todos AS PERMISSIVE FOR SELECT TO authenticated USING ((auth.uid() = "user id"));`,
+		"CREATE TRIGGER touch_todos BEFORE UPDATE ON public.todos",
+	}
+
+	last := -1
+	for _, stmt := range ordered {
+		i := strings.Index(sql, stmt)
+		if i < 0 {
+			t.Errorf("SQL missing %q:\n%s", stmt, sql)
+			continue
+		}
+		if i < last {
+			t.Errorf("%q is out of order:\n%s", stmt, sql)
+		}
+		last = i
+	}
+}
+
+func TestCompare_FunctionResultChanged(t *testing.T) {
+	from, to := NewSchema(), NewSchema()
+	from.Functions[`public.stats(uuid)`] = &Function{Schema: "public", Name: "stats", Returns: "integer",
+		Params:     []Param{{Name: "user_id", Type: "uuid", Mode: "i"}},
+		Definition: "CREATE OR REPLACE FUNCTION public.stats(user_id uuid) RETURNS integer LANGUAGE sql AS $$ SELECT 1 $$"}
+	to.Functions[`public.stats(uuid)`] = &Function{Schema: "public", Name: "stats", Returns: "record", ReturnsSet: true,
+		Params:     []Param{{Name: "user_id", Type: "uuid", Mode: "i"}, {Name: "total", Type: "integer", Mode: "t"}},
+		Definition: "CREATE OR REPLACE FUNCTION public.stats(user_id uuid) RETURNS TABLE(total integer) LANGUAGE sql AS $$ SELECT 1 $$"}
+
+	diff := Compare(from, to)
+	var out bytes.Buffer
+	diff.WriteText(&out)
+	if want := "~ result: integer -> SETOF record (total integer)"; !strings.Contains(out.String(), want) {
+		t.Errorf("diff missing %q:\n%s", want, out.String())
+	}
+
+	sql := diff.SQL()
+	drop := strings.Index(sql, "DROP FUNCTION public.stats(uuid);")
+	create := strings.Index(sql, "CREATE OR REPLACE FUNCTION public.stats(user_id uuid) RETURNS TABLE")
+	if drop < 0 || create < drop {
+		t.Errorf("expected the function to be dropped before it is recreated:\n%s", sql)
+	}
+
+	// Body-only changes are still replaced in place
+	to.Functions[`public.stats(uuid)`] = &Function{Schema: "public", Name: "stats", Returns: "integer",
+		Params:     []Param{{Name: "user_id", Type: "uuid", Mode: "i"}},
+		Definition: "CREATE OR REPLACE FUNCTION public.stats(user_id uuid) RETURNS integer LANGUAGE sql AS $$ SELECT 2 $$"}
+	if sql := Compare(from, to).SQL(); strings.Contains(sql, "DROP FUNCTION") {
+		t.Errorf("body-only change should not drop the function:\n%s", sql)
+	}
+}
+
+func TestCompare_Identical(t *testing.T) {
+	if diff := Compare(targetSchema(), targetSchema()); !diff.Empty() {
+		var out bytes.Buffer
+		diff.WriteText(&out)
+		t.Errorf("identical schemas should not differ:\n%s", out.String())
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	tests := map[string]string{
+		"todos":     "todos",
+		"user":      `"user"`,
+		"UserName":  `"UserName"`,
+		"user id":   `"user id"`,
+		`say "hi"`:  `"say ""hi"""`,
+		"_private1": "_private1",
+	}
+	for in, want := range tests {
+		if got := QuoteIdent(in); got != want {
+			t.Errorf("QuoteIdent(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package pgschema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// plainIdentRegex matches identifiers that don't need quoting
+var plainIdentRegex = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+
+// reservedWords are keywords that must be quoted when used as identifiers
+var reservedWords = map[string]bool{
+	"all": true, "and": true, "any": true, "as": true, "check": true, "column": true,
+	"constraint": true, "create": true, "default": true, "desc": true, "do": true,
+	"else": true, "end": true, "for": true, "foreign": true, "from": true, "grant": true,
+	"group": true, "in": true, "limit": true, "not": true, "null": true, "on": true,
+	"or": true, "order": true, "primary": true, "references": true, "select": true,
+	"table": true, "to": true, "union": true, "unique": true, "user": true, "using": true,
+	"when": true, "where": true, "with": true,
+}
+
+// QuoteIdent quotes an identifier if Postgres requires it
+func QuoteIdent(name string) string {
+	if plainIdentRegex.MatchString(name) && !reservedWords[name] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
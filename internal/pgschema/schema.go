@@ -0,0 +1,406 @@
+package pgschema
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Schema is the introspected structure of one or more Postgres schemas.
+// Objects are keyed by their qualified name ("schema.name").
+type Schema struct {
+	Tables    map[string]*Table
+	Views     map[string]*View
+	Enums     map[string]*Enum
+	Indexes   map[string]*Index
+	Policies  map[string]*Policy
+	Functions map[string]*Function
+	Triggers  map[string]*Trigger
+}
+
+// Table is a regular or partitioned table
+type Table struct {
+	Schema      string
+	Name        string
+	RLSEnabled  bool
+	Columns     []Column
+	Constraints []Constraint
+}
+
+// View is a view or materialized view
+type View struct {
+	Schema       string
+	Name         string
+	Materialized bool
+	Definition   string
+	Columns      []Column
+}
+
+// Enum is an enum type and its values in sort order
+type Enum struct {
+	Schema string
+	Name   string
+	Values []string
+}
+
+// Column is a table or view column
+type Column struct {
+	Name      string
+	Type      string
+	NotNull   bool
+	Default   string
+	Position  int
+	Identity  bool
+	Generated bool
+}
+
+// Constraint is a primary key, unique, foreign key, check or exclusion constraint
+type Constraint struct {
+	Name       string
+	Definition string
+}
+
+// Index is an index that does not back a constraint
+type Index struct {
+	Schema     string
+	Table      string
+	Name       string
+	Definition string
+}
+
+// Policy is a row level security policy
+type Policy struct {
+	Schema     string
+	Table      string
+	Name       string
+	Permissive string
+	Command    string
+	Roles      []string
+	Using      string
+	Check      string
+}
+
+// Function is a function or procedure, keyed by its identity signature
+type Function struct {
+	Schema     string
+	Name       string
+	Arguments  string
+	Definition string
+	Procedure  bool
+	Returns    string
+	ReturnsSet bool
+	Params     []Param
+}
+
+// Param is a function parameter. Mode is i (in), o (out), b (inout), v (variadic) or t (table column).
+type Param struct {
+	Name       string
+	Type       string
+	Mode       string
+	HasDefault bool
+}
+
+// Inputs returns the parameters a caller passes to the function
+func (f *Function) Inputs() []Param {
+	var params []Param
+	for _, p := range f.Params {
+		if p.Mode == "i" || p.Mode == "b" || p.Mode == "v" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// Outputs returns the OUT and TABLE parameters that make up the function's result row
+func (f *Function) Outputs() []Param {
+	var params []Param
+	for _, p := range f.Params {
+		if p.Mode == "o" || p.Mode == "b" || p.Mode == "t" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// Trigger is a user-defined trigger
+type Trigger struct {
+	Schema     string
+	Table      string
+	Name       string
+	Definition string
+}
+
+// NewSchema returns an empty schema
+func NewSchema() *Schema {
+	return &Schema{
+		Tables:    make(map[string]*Table),
+		Views:     make(map[string]*View),
+		Enums:     make(map[string]*Enum),
+		Indexes:   make(map[string]*Index),
+		Policies:  make(map[string]*Policy),
+		Functions: make(map[string]*Function),
+		Triggers:  make(map[string]*Trigger),
+	}
+}
+
+// Introspection queries. They read pg_catalog directly and only cover the requested schemas.
+const (
+	tablesQuery = `SELECT n.nspname, c.relname, c.relrowsecurity
+FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p') AND n.nspname = ANY($1)`
+
+	viewsQuery = `SELECT n.nspname, c.relname, c.relkind = 'm', pg_get_viewdef(c.oid)
+FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('v', 'm') AND n.nspname = ANY($1)`
+
+	enumsQuery = `SELECT n.nspname, t.typname, array_agg(e.enumlabel ORDER BY e.enumsortorder)::text[]
+FROM pg_type t
+JOIN pg_enum e ON e.enumtypid = t.oid
+JOIN pg_namespace n ON n.oid = t.typnamespace
+WHERE n.nspname = ANY($1)
+GROUP BY n.nspname, t.typname`
+
+	columnsQuery = `SELECT n.nspname, c.relname, a.attname, format_type(a.atttypid, a.atttypmod),
+	a.attnotnull, COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), a.attnum,
+	a.attidentity <> '', a.attgenerated <> ''
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE c.relkind IN ('r', 'p', 'v', 'm') AND a.attnum > 0 AND NOT a.attisdropped AND n.nspname = ANY($1)
+ORDER BY n.nspname, c.relname, a.attnum`
+
+	constraintsQuery = `SELECT n.nspname, c.relname, con.conname, pg_get_constraintdef(con.oid)
+FROM pg_constraint con
+JOIN pg_class c ON c.oid = con.conrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE con.contype IN ('p', 'u', 'f', 'c', 'x') AND n.nspname = ANY($1)
+ORDER BY n.nspname, c.relname, con.conname`
+
+	indexesQuery = `SELECT n.nspname, t.relname, i.relname, pg_get_indexdef(i.oid)
+FROM pg_index x
+JOIN pg_class i ON i.oid = x.indexrelid
+JOIN pg_class t ON t.oid = x.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE n.nspname = ANY($1)
+AND NOT EXISTS (
+	SELECT 1 FROM pg_constraint con
+	WHERE con.conindid = x.indexrelid AND con.conrelid = x.indrelid AND con.contype IN ('p', 'u', 'x')
+)`
+
+	policiesQuery = `SELECT schemaname, tablename, policyname, permissive, roles::text[], cmd,
+	COALESCE(qual, ''), COALESCE(with_check, '')
+FROM pg_policies
+WHERE schemaname = ANY($1)`
+
+	// Parameter arrays list all parameters (including OUT and TABLE columns) in declaration order
+	functionsQuery = `SELECT n.nspname, p.proname, pg_get_function_identity_arguments(p.oid), pg_get_functiondef(p.oid),
+	p.prokind = 'p', format_type(p.prorettype, NULL), p.proretset, p.pronargdefaults,
+	ARRAY(SELECT COALESCE(p.proargnames[a.i], '') FROM unnest(COALESCE(p.proallargtypes, p.proargtypes::oid[])) WITH ORDINALITY AS a(t, i) ORDER BY a.i)::text[],
+	ARRAY(SELECT format_type(a.t, NULL) FROM unnest(COALESCE(p.proallargtypes, p.proargtypes::oid[])) WITH ORDINALITY AS a(t, i) ORDER BY a.i)::text[],
+	ARRAY(SELECT COALESCE(p.proargmodes[a.i], 'i')::text FROM unnest(COALESCE(p.proallargtypes, p.proargtypes::oid[])) WITH ORDINALITY AS a(t, i) ORDER BY a.i)::text[]
+FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace
+WHERE p.prokind IN ('f', 'p') AND n.nspname = ANY($1)
+AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = p.oid AND d.deptype = 'e')`
+
+	triggersQuery = `SELECT n.nspname, c.relname, t.tgname, pg_get_triggerdef(t.oid)
+FROM pg_trigger t
+JOIN pg_class c ON c.oid = t.tgrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE NOT t.tgisinternal AND n.nspname = ANY($1)`
+)
+
+// Introspect reads the tables, views, enums, columns, constraints, indexes, RLS policies,
+// functions and triggers of the given schemas
+func Introspect(db *sql.DB, schemas []string) (*Schema, error) {
+	s := NewSchema()
+	names := pq.Array(schemas)
+
+	err := queryRows(db, tablesQuery, names, func(rows *sql.Rows) error {
+		t := &Table{}
+		if err := rows.Scan(&t.Schema, &t.Name, &t.RLSEnabled); err != nil {
+			return err
+		}
+		s.Tables[qualify(t.Schema, t.Name)] = t
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tables: %w", err)
+	}
+
+	err = queryRows(db, viewsQuery, names, func(rows *sql.Rows) error {
+		v := &View{}
+		if err := rows.Scan(&v.Schema, &v.Name, &v.Materialized, &v.Definition); err != nil {
+			return err
+		}
+		s.Views[qualify(v.Schema, v.Name)] = v
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read views: %w", err)
+	}
+
+	err = queryRows(db, enumsQuery, names, func(rows *sql.Rows) error {
+		e := &Enum{}
+		if err := rows.Scan(&e.Schema, &e.Name, pq.Array(&e.Values)); err != nil {
+			return err
+		}
+		s.Enums[qualify(e.Schema, e.Name)] = e
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enums: %w", err)
+	}
+
+	err = queryRows(db, columnsQuery, names, func(rows *sql.Rows) error {
+		var schema, table string
+		var c Column
+		if err := rows.Scan(&schema, &table, &c.Name, &c.Type, &c.NotNull, &c.Default, &c.Position, &c.Identity, &c.Generated); err != nil {
+			return err
+		}
+		if t, ok := s.Tables[qualify(schema, table)]; ok {
+			t.Columns = append(t.Columns, c)
+		} else if v, ok := s.Views[qualify(schema, table)]; ok {
+			v.Columns = append(v.Columns, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	err = queryRows(db, constraintsQuery, names, func(rows *sql.Rows) error {
+		var schema, table string
+		var c Constraint
+		if err := rows.Scan(&schema, &table, &c.Name, &c.Definition); err != nil {
+			return err
+		}
+		if t, ok := s.Tables[qualify(schema, table)]; ok {
+			t.Constraints = append(t.Constraints, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %w", err)
+	}
+
+	err = queryRows(db, indexesQuery, names, func(rows *sql.Rows) error {
+		i := &Index{}
+		if err := rows.Scan(&i.Schema, &i.Table, &i.Name, &i.Definition); err != nil {
+			return err
+		}
+		s.Indexes[qualify(i.Schema, i.Name)] = i
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+
+	err = queryRows(db, policiesQuery, names, func(rows *sql.Rows) error {
+		p := &Policy{}
+		if err := rows.Scan(&p.Schema, &p.Table, &p.Name, &p.Permissive, pq.Array(&p.Roles), &p.Command, &p.Using, &p.Check); err != nil {
+			return err
+		}
+		s.Policies[p.Key()] = p
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies: %w", err)
+	}
+
+	err = queryRows(db, functionsQuery, names, func(rows *sql.Rows) error {
+		f := &Function{}
+		var defaults int
+		var paramNames, paramTypes, paramModes []string
+		if err := rows.Scan(&f.Schema, &f.Name, &f.Arguments, &f.Definition, &f.Procedure, &f.Returns, &f.ReturnsSet,
+			&defaults, pq.Array(&paramNames), pq.Array(&paramTypes), pq.Array(&paramModes)); err != nil {
+			return err
+		}
+		f.Params = buildParams(paramNames, paramTypes, paramModes, defaults)
+		s.Functions[f.Signature()] = f
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read functions: %w", err)
+	}
+
+	err = queryRows(db, triggersQuery, names, func(rows *sql.Rows) error {
+		t := &Trigger{}
+		if err := rows.Scan(&t.Schema, &t.Table, &t.Name, &t.Definition); err != nil {
+			return err
+		}
+		s.Triggers[t.Key()] = t
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read triggers: %w", err)
+	}
+
+	return s, nil
+}
+
+// buildParams combines the parameter arrays of a function. The last defaults input
+// parameters have default values.
+func buildParams(names, types, modes []string, defaults int) []Param {
+	params := make([]Param, len(types))
+	for i := range types {
+		params[i] = Param{Type: types[i], Mode: "i"}
+		if i < len(names) {
+			params[i].Name = names[i]
+		}
+		if i < len(modes) {
+			params[i].Mode = modes[i]
+		}
+	}
+
+	for i := len(params) - 1; i >= 0 && defaults > 0; i-- {
+		if p := params[i]; p.Mode == "i" || p.Mode == "b" || p.Mode == "v" {
+			params[i].HasDefault = true
+			defaults--
+		}
+	}
+
+	return params
+}
+
+// queryRows runs a query with the schema list and calls scan for every row
+func queryRows(db *sql.DB, query string, schemas interface{}, scan func(*sql.Rows) error) error {
+	rows, err := db.Query(query, schemas)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Signature returns the qualified identity of a function, e.g. public.add(a integer, b integer)
+func (f *Function) Signature() string {
+	return fmt.Sprintf("%s(%s)", qualify(f.Schema, f.Name), f.Arguments)
+}
+
+// qualify returns a quoted, schema-qualified identifier
+func qualify(schema, name string) string {
+	return QuoteIdent(schema) + "." + QuoteIdent(name)
+}
+
+// Key returns the qualified identity of a policy: its table and name
+func (p *Policy) Key() string {
+	return qualify(p.Schema, p.Table) + "." + QuoteIdent(p.Name)
+}
+
+// Key returns the qualified identity of a trigger: its table and name
+func (t *Trigger) Key() string {
+	return qualify(t.Schema, t.Table) + "." + QuoteIdent(t.Name)
+}
+
+// QualifiedName returns the quoted, schema-qualified table name
+func (t *Table) QualifiedName() string {
+	return qualify(t.Schema, t.Name)
+}
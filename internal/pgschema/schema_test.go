@@ -0,0 +1,31 @@
+package pgschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildParams(t *testing.T) {
+	// f(a integer, b text DEFAULT '', OUT total bigint)
+	params := buildParams([]string{"a", "b", "total"}, []string{"integer", "text", "bigint"}, []string{"i", "i", "o"}, 1)
+	f := &Function{Params: params}
+
+	wantInputs := []Param{
+		{Name: "a", Type: "integer", Mode: "i"},
+		{Name: "b", Type: "text", Mode: "i", HasDefault: true},
+	}
+	if got := f.Inputs(); !reflect.DeepEqual(got, wantInputs) {
+		t.Errorf("Inputs() = %+v, want %+v", got, wantInputs)
+	}
+
+	wantOutputs := []Param{{Name: "total", Type: "bigint", Mode: "o"}}
+	if got := f.Outputs(); !reflect.DeepEqual(got, wantOutputs) {
+		t.Errorf("Outputs() = %+v, want %+v", got, wantOutputs)
+	}
+
+	// Unnamed parameters of functions without OUT parameters have no names or modes
+	params = buildParams(nil, []string{"uuid"}, nil, 0)
+	if want := []Param{{Type: "uuid", Mode: "i"}}; !reflect.DeepEqual(params, want) {
+		t.Errorf("buildParams() = %+v, want %+v", params, want)
+	}
+}
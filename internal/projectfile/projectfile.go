@@ -0,0 +1,219 @@
+package projectfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/qubitquilt/supactl/internal/local"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the project file in a repository
+const FileName = "supactl.yaml"
+
+// Default paths, relative to the directory holding the project file
+var (
+	DefaultMigrationsDir = filepath.Join("supabase", "migrations")
+	DefaultFunctionsDir  = filepath.Join("supabase", "functions")
+	DefaultSeedFile      = filepath.Join("supabase", "seed.sql")
+)
+
+// Config holds the per-repository defaults declared in supactl.yaml.
+// Empty fields fall back to the global configuration.
+type Config struct {
+	// Context is the preferred context for commands run in the repository
+	Context string `yaml:"context,omitempty"`
+
+	// Instance is the default instance for commands that take an instance name
+	Instance string `yaml:"instance,omitempty"`
+
+	// SupabaseVersion is the branch or tag of supabase/supabase checked out for new local instances
+	SupabaseVersion string `yaml:"supabase_version,omitempty"`
+
+	Paths Paths `yaml:"paths,omitempty"`
+
+	// Env is applied to the instance's configuration when it is created or started
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Dir is the directory holding the project file
+	Dir string `yaml:"-"`
+}
+
+// Paths are relative to the directory holding the project file
+type Paths struct {
+	Migrations string   `yaml:"migrations,omitempty"`
+	Functions  string   `yaml:"functions,omitempty"`
+	Seed       []string `yaml:"seed,omitempty"`
+}
+
+// Find returns the path of the project file in dir or its closest parent that has
+// one, like git does for .git. It returns an empty path if there is none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve directory: %w", err)
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Discover finds and loads the project file for the current directory.
+// It returns nil without error if there is none.
+func Discover() (*Config, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	path, err := Find(wd)
+	if err != nil || path == "" {
+		return nil, err
+	}
+
+	return Load(path)
+}
+
+// Load reads and validates a project file. Unknown fields are rejected to catch typos.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var c Config
+	if err := decoder.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	c.Dir = filepath.Dir(path)
+	return &c, nil
+}
+
+// Validate checks the instance name and env keys
+func (c *Config) Validate() error {
+	if c.Instance != "" && strings.Contains(c.Instance, "/") {
+		return fmt.Errorf("instance '%s' must not contain '/'; set the context separately", c.Instance)
+	}
+	for key := range c.Env {
+		if err := local.ValidateEnvKey(key); err != nil {
+			return fmt.Errorf("env: %w", err)
+		}
+	}
+	return nil
+}
+
+// InstanceRef returns the default instance as context/instance, or the bare
+// instance name if no context is set. It is empty if no instance is set.
+func (c *Config) InstanceRef() string {
+	if c.Instance == "" || c.Context == "" {
+		return c.Instance
+	}
+	return c.Context + "/" + c.Instance
+}
+
+// MigrationsDir returns the migrations directory
+func (c *Config) MigrationsDir() string {
+	return c.resolve(c.Paths.Migrations, DefaultMigrationsDir)
+}
+
+// FunctionsDir returns the edge functions directory
+func (c *Config) FunctionsDir() string {
+	return c.resolve(c.Paths.Functions, DefaultFunctionsDir)
+}
+
+// SeedFiles returns the seed files loaded after a database reset
+func (c *Config) SeedFiles() []string {
+	if len(c.Paths.Seed) == 0 {
+		return []string{c.resolve("", DefaultSeedFile)}
+	}
+
+	files := make([]string, len(c.Paths.Seed))
+	for i, seed := range c.Paths.Seed {
+		files[i] = c.resolve(seed, "")
+	}
+	return files
+}
+
+// resolve makes a configured path, or the fallback if it is empty, relative to the project directory
+func (c *Config) resolve(path, fallback string) string {
+	if path == "" {
+		path = fallback
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.Dir, filepath.FromSlash(path))
+}
+
+// scaffoldTemplate is the project file written by Scaffold
+var scaffoldTemplate = template.Must(template.New(FileName).Parse(`# supactl project defaults. Commit this file; CLI flags override it, and it
+# overrides the global configuration in ~/.supacontrol/config.json.
+
+# Context used by commands run anywhere in this repository
+{{if .Context}}context: {{.Context}}{{else}}# context: local{{end}}
+
+# Instance used when a command's instance argument is omitted
+{{if .Instance}}instance: {{.Instance}}{{else}}# instance: my-project{{end}}
+
+# Branch or tag of github.com/supabase/supabase checked out for new local instances
+{{if .SupabaseVersion}}supabase_version: {{.SupabaseVersion}}{{else}}# supabase_version: master{{end}}
+
+# Paths relative to this file (defaults shown). Listed seed files must exist;
+# the default seed file is only loaded if it does.
+# paths:
+#   migrations: supabase/migrations
+#   functions: supabase/functions
+#   seed:
+#     - supabase/seed.sql
+
+# Configuration applied to the instance when it is created or started
+# env:
+#   SITE_URL: http://localhost:3000
+`))
+
+// Scaffold writes a commented project file to dir. It fails if one already exists unless force is set.
+func Scaffold(dir string, c *Config, force bool) (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, FileName)
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+
+	var buf bytes.Buffer
+	if err := scaffoldTemplate.Execute(&buf, c); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", FileName, err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return path, nil
+}
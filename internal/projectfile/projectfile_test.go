@@ -0,0 +1,146 @@
+package projectfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "apps", "web", "src")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := Find(nested)
+	if err != nil || path != "" {
+		t.Fatalf("Find() without project file = %q, %v", path, err)
+	}
+
+	writeFile(t, filepath.Join(root, FileName), "instance: app\n")
+	path, err = Find(nested)
+	if err != nil || path != filepath.Join(root, FileName) {
+		t.Errorf("Find() = %q, %v; want root project file", path, err)
+	}
+
+	// The closest project file wins
+	writeFile(t, filepath.Join(root, "apps", "web", FileName), "instance: web\n")
+	path, err = Find(nested)
+	if err != nil || path != filepath.Join(root, "apps", "web", FileName) {
+		t.Errorf("Find() = %q, %v; want nested project file", path, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	writeFile(t, path, `
+context: prod
+instance: app
+supabase_version: v1.0.0
+paths:
+  migrations: db/migrations
+  seed:
+    - db/seed.sql
+    - db/fixtures.sql
+env:
+  SITE_URL: https://app.example.com
+`)
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if c.Context != "prod" || c.Instance != "app" || c.SupabaseVersion != "v1.0.0" || c.Dir != dir {
+		t.Errorf("unexpected config: %+v", c)
+	}
+	if c.InstanceRef() != "prod/app" {
+		t.Errorf("InstanceRef() = %s", c.InstanceRef())
+	}
+	if c.MigrationsDir() != filepath.Join(dir, "db", "migrations") {
+		t.Errorf("MigrationsDir() = %s", c.MigrationsDir())
+	}
+	if c.FunctionsDir() != filepath.Join(dir, "supabase", "functions") {
+		t.Errorf("FunctionsDir() = %s", c.FunctionsDir())
+	}
+	wantSeeds := []string{filepath.Join(dir, "db", "seed.sql"), filepath.Join(dir, "db", "fixtures.sql")}
+	if !reflect.DeepEqual(c.SeedFiles(), wantSeeds) {
+		t.Errorf("SeedFiles() = %v, want %v", c.SeedFiles(), wantSeeds)
+	}
+	if c.Env["SITE_URL"] != "https://app.example.com" {
+		t.Errorf("unexpected env: %v", c.Env)
+	}
+}
+
+func TestLoad_Empty(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	writeFile(t, path, "# nothing yet\n")
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.InstanceRef() != "" || c.SeedFiles()[0] != filepath.Join(dir, "supabase", "seed.sql") {
+		t.Errorf("unexpected defaults: %+v", c)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":   "instnace: app\n",
+		"invalid env key": "env:\n  lower-case: x\n",
+		"qualified name":  "instance: prod/app\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			writeFile(t, path, content)
+			if _, err := Load(path); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := Scaffold(dir, &Config{Context: "local", Instance: "my-app"}, false)
+	if err != nil {
+		t.Fatalf("Scaffold failed: %v", err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("scaffolded file does not load: %v", err)
+	}
+	if c.Context != "local" || c.Instance != "my-app" || c.SupabaseVersion != "" {
+		t.Errorf("unexpected config: %+v", c)
+	}
+	if c.MigrationsDir() != filepath.Join(dir, "supabase", "migrations") {
+		t.Errorf("MigrationsDir() = %s", c.MigrationsDir())
+	}
+
+	if _, err := Scaffold(dir, &Config{}, false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an error for an existing file, got %v", err)
+	}
+	if _, err := Scaffold(dir, &Config{Instance: "other"}, true); err != nil {
+		t.Errorf("Scaffold with force failed: %v", err)
+	}
+}
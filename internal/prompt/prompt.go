@@ -0,0 +1,178 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// ErrNonInteractive is returned when input is needed but prompting is disabled
+var ErrNonInteractive = errors.New("input required but prompting is disabled")
+
+// Prompter asks the user for input
+type Prompter interface {
+	// Confirm asks a yes/no question
+	Confirm(message string, defaultValue bool) (bool, error)
+
+	// Password asks for a secret without echoing it. Empty answers are rejected.
+	Password(message string) (string, error)
+
+	// Select asks the user to pick one of options and returns its index
+	Select(message string, options []string) (int, error)
+}
+
+// Terminal prompts on the terminal. When Interactive is false every prompt fails
+// with ErrNonInteractive, except confirmations that AssumeYes answers.
+type Terminal struct {
+	AssumeYes   bool
+	Interactive bool
+}
+
+// NewTerminal creates a terminal prompter. Prompting is disabled when
+// nonInteractive is set or stdin is not a terminal.
+func NewTerminal(assumeYes, nonInteractive bool) *Terminal {
+	return &Terminal{
+		AssumeYes:   assumeYes,
+		Interactive: !nonInteractive && IsTerminal(os.Stdin),
+	}
+}
+
+// IsTerminal reports whether f is connected to a terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Confirm asks a yes/no question, answering yes without asking if AssumeYes is set
+func (t *Terminal) Confirm(message string, defaultValue bool) (bool, error) {
+	if t.AssumeYes {
+		return true, nil
+	}
+	if !t.Interactive {
+		return false, fmt.Errorf("%w: %s (pass --yes to confirm)", ErrNonInteractive, message)
+	}
+
+	var confirmed bool
+	prompt := &survey.Confirm{
+		Message: message,
+		Default: defaultValue,
+	}
+	if err := survey.AskOne(prompt, &confirmed); err != nil {
+		return false, err
+	}
+	return confirmed, nil
+}
+
+// Password asks for a secret without echoing it
+func (t *Terminal) Password(message string) (string, error) {
+	if !t.Interactive {
+		return "", fmt.Errorf("%w: %s", ErrNonInteractive, message)
+	}
+
+	var answer string
+	prompt := &survey.Password{
+		Message: message,
+	}
+	if err := survey.AskOne(prompt, &answer, survey.WithValidator(survey.Required)); err != nil {
+		return "", err
+	}
+	return answer, nil
+}
+
+// Select asks the user to pick one of options
+func (t *Terminal) Select(message string, options []string) (int, error) {
+	if !t.Interactive {
+		return 0, fmt.Errorf("%w: %s", ErrNonInteractive, message)
+	}
+
+	var index int
+	prompt := &survey.Select{
+		Message: message,
+		Options: options,
+	}
+	if err := survey.AskOne(prompt, &index); err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// Scripted answers prompts from a fixed list of answers, for tests. Answers are
+// consumed in order: a bool for Confirm, a string for Password and an int or the
+// option text for Select. Asked records the message of every prompt.
+type Scripted struct {
+	Answers []interface{}
+	Asked   []string
+}
+
+// Confirm returns the next scripted answer, which must be a bool
+func (s *Scripted) Confirm(message string, defaultValue bool) (bool, error) {
+	answer, err := s.next(message)
+	if err != nil {
+		return false, err
+	}
+	confirmed, ok := answer.(bool)
+	if !ok {
+		return false, fmt.Errorf("scripted answer for %q is %T, want bool", message, answer)
+	}
+	return confirmed, nil
+}
+
+// Password returns the next scripted answer, which must be a string
+func (s *Scripted) Password(message string) (string, error) {
+	answer, err := s.next(message)
+	if err != nil {
+		return "", err
+	}
+	value, ok := answer.(string)
+	if !ok {
+		return "", fmt.Errorf("scripted answer for %q is %T, want string", message, answer)
+	}
+	return value, nil
+}
+
+// Select returns the next scripted answer, an index or the text of an option
+func (s *Scripted) Select(message string, options []string) (int, error) {
+	answer, err := s.next(message)
+	if err != nil {
+		return 0, err
+	}
+
+	switch value := answer.(type) {
+	case int:
+		if value < 0 || value >= len(options) {
+			return 0, fmt.Errorf("scripted answer for %q is out of range: %d", message, value)
+		}
+		return value, nil
+	case string:
+		for i, option := range options {
+			if option == value {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("scripted answer for %q is not an option: %s", message, value)
+	default:
+		return 0, fmt.Errorf("scripted answer for %q is %T, want int or string", message, answer)
+	}
+}
+
+// next records a prompt and pops the next answer
+func (s *Scripted) next(message string) (interface{}, error) {
+	s.Asked = append(s.Asked, message)
+	if len(s.Answers) == 0 {
+		return nil, fmt.Errorf("%w: no scripted answer for %q", ErrNonInteractive, message)
+	}
+	answer := s.Answers[0]
+	s.Answers = s.Answers[1:]
+	return answer, nil
+}
+
+// Compile-time checks to ensure both prompters implement Prompter
+var (
+	_ Prompter = (*Terminal)(nil)
+	_ Prompter = (*Scripted)(nil)
+)
@@ -0,0 +1,83 @@
+package prompt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTerminal_NonInteractive(t *testing.T) {
+	p := &Terminal{Interactive: false}
+
+	if _, err := p.Confirm("Delete?", false); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("Confirm error = %v, want ErrNonInteractive", err)
+	}
+	if _, err := p.Password("API key:"); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("Password error = %v, want ErrNonInteractive", err)
+	}
+	if _, err := p.Select("Project:", []string{"a"}); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("Select error = %v, want ErrNonInteractive", err)
+	}
+}
+
+func TestTerminal_AssumeYes(t *testing.T) {
+	p := &Terminal{AssumeYes: true, Interactive: false}
+
+	confirmed, err := p.Confirm("Delete?", false)
+	if err != nil || !confirmed {
+		t.Errorf("Confirm = %v, %v; want true, nil", confirmed, err)
+	}
+
+	// --yes only answers confirmations
+	if _, err := p.Password("API key:"); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("Password error = %v, want ErrNonInteractive", err)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "stdin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Error("a regular file is not a terminal")
+	}
+}
+
+func TestScripted(t *testing.T) {
+	p := &Scripted{Answers: []interface{}{true, "secret", 1, "beta"}}
+
+	if confirmed, err := p.Confirm("Delete?", false); err != nil || !confirmed {
+		t.Errorf("Confirm = %v, %v", confirmed, err)
+	}
+	if value, err := p.Password("API key:"); err != nil || value != "secret" {
+		t.Errorf("Password = %q, %v", value, err)
+	}
+	if index, err := p.Select("Project:", []string{"alpha", "beta"}); err != nil || index != 1 {
+		t.Errorf("Select by index = %d, %v", index, err)
+	}
+	if index, err := p.Select("Project:", []string{"alpha", "beta"}); err != nil || index != 1 {
+		t.Errorf("Select by option = %d, %v", index, err)
+	}
+
+	if _, err := p.Confirm("Again?", false); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("expected ErrNonInteractive when answers run out, got %v", err)
+	}
+	if len(p.Asked) != 5 || p.Asked[0] != "Delete?" {
+		t.Errorf("unexpected Asked: %v", p.Asked)
+	}
+}
+
+func TestScripted_WrongType(t *testing.T) {
+	p := &Scripted{Answers: []interface{}{"yes", 5}}
+
+	if _, err := p.Confirm("Delete?", false); err == nil {
+		t.Error("expected error for non-bool answer")
+	}
+	if _, err := p.Select("Project:", []string{"a"}); err == nil {
+		t.Error("expected error for out-of-range answer")
+	}
+}
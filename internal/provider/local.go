@@ -4,17 +4,24 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 	"time"
 
+	"github.com/qubitquilt/supactl/internal/functions"
 	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/qubitquilt/supactl/internal/metrics"
 )
 
 // LocalProvider implements InstanceProvider for local Docker-based instances
 type LocalProvider struct {
 	db *local.Database
+
+	// SupabaseVersion is the Supabase branch or tag checked out for new instances;
+	// empty for the default branch
+	SupabaseVersion string
+
+	// Runner manages the instances' containers; the docker CLI by default
+	Runner local.ComposeRunner
 }
 
 // NewLocalProvider creates a new local provider
@@ -24,7 +31,7 @@ func NewLocalProvider() (*LocalProvider, error) {
 		return nil, fmt.Errorf("failed to load local database: %w", err)
 	}
 
-	return &LocalProvider{db: db}, nil
+	return &LocalProvider{db: db, Runner: local.NewCLIRunner(local.OSExecutor{})}, nil
 }
 
 // reloadDatabase reloads the database from disk (for operations that might have changed it)
@@ -38,10 +45,10 @@ func (p *LocalProvider) reloadDatabase() error {
 }
 
 // mapProjectToInstance converts a local project to a unified instance
-func mapProjectToInstance(name string, project *local.Project) *Instance {
+func (p *LocalProvider) mapProjectToInstance(name string, project *local.Project) *Instance {
 	// Determine status by checking if containers are running
 	status := "stopped"
-	if isProjectRunning(name, project.Directory) {
+	if p.isRunning(name, project.Directory) {
 		status = "running"
 	}
 
@@ -55,6 +62,7 @@ func mapProjectToInstance(name string, project *local.Project) *Instance {
 		Directory: project.Directory,
 		DBPort:    project.Ports.DB,
 		CreatedAt: time.Time{}, // Local instances don't track creation time
+		Labels:    project.Labels,
 	}
 }
 
@@ -74,19 +82,10 @@ func getHostIP() string {
 	return "localhost"
 }
 
-// isProjectRunning checks if a project's containers are running
-func isProjectRunning(projectID, directory string) bool {
-	dockerDir := filepath.Join(directory, "supabase", "docker")
-	cmd := exec.Command("docker", "compose", "-p", projectID, "ps", "-q")
-	cmd.Dir = dockerDir
-
-	output, err := cmd.Output()
-	if err != nil {
-		return false
-	}
-
-	// If there are container IDs in the output, project is running
-	return len(strings.TrimSpace(string(output))) > 0
+// isRunning checks if a project's containers are running
+func (p *LocalProvider) isRunning(projectID, directory string) bool {
+	running, err := p.Runner.Running(projectID, directory)
+	return err == nil && running
 }
 
 // ListInstances returns all local instances
@@ -97,7 +96,7 @@ func (p *LocalProvider) ListInstances() ([]Instance, error) {
 
 	instances := make([]Instance, 0, len(p.db.Projects))
 	for name, project := range p.db.Projects {
-		inst := mapProjectToInstance(name, &project)
+		inst := p.mapProjectToInstance(name, &project)
 		instances = append(instances, *inst)
 	}
 
@@ -115,13 +114,31 @@ func (p *LocalProvider) GetInstance(name string) (*Instance, error) {
 		return nil, err
 	}
 
-	return mapProjectToInstance(name, project), nil
+	return p.mapProjectToInstance(name, project), nil
 }
 
-// CreateInstance creates a new local instance
-// Note: This is a simplified version. For full setup, use the existing local add command
+// CreateInstance creates a new local instance in ~/<name>, like 'supactl local add'.
+// The instance is created stopped.
 func (p *LocalProvider) CreateInstance(name string) (*Instance, error) {
-	return nil, fmt.Errorf("creating local instances requires additional parameters. Use 'supactl local add' command instead")
+	if err := p.reloadDatabase(); err != nil {
+		return nil, err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	if _, err := local.SetupProject(name, filepath.Join(homeDir, name), p.SupabaseVersion, "", p.db); err != nil {
+		return nil, err
+	}
+
+	project, err := p.db.GetProject(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.mapProjectToInstance(name, project), nil
 }
 
 // DeleteInstance removes a local instance from the database
@@ -161,14 +178,19 @@ func (p *LocalProvider) StartInstance(name string) error {
 	}
 
 	// Check if already running
-	if isProjectRunning(name, project.Directory) {
+	if p.isRunning(name, project.Directory) {
 		return fmt.Errorf("instance '%s' is already running", name)
 	}
 
-	return local.DockerComposeUp(name, project.Directory)
+	if err := local.SyncComposeOverride(name, project); err != nil {
+		return err
+	}
+
+	return p.Runner.Up(name, project.Directory)
 }
 
-// StopInstance stops a local instance
+// StopInstance stops a local instance. Its containers and volumes are kept, so it starts
+// again with its data; DownInstance removes the containers.
 func (p *LocalProvider) StopInstance(name string) error {
 	if err := p.reloadDatabase(); err != nil {
 		return err
@@ -180,11 +202,11 @@ func (p *LocalProvider) StopInstance(name string) error {
 	}
 
 	// Check if running
-	if !isProjectRunning(name, project.Directory) {
+	if !p.isRunning(name, project.Directory) {
 		return fmt.Errorf("instance '%s' is not running", name)
 	}
 
-	return local.DockerComposeDown(name, project.Directory)
+	return p.Runner.Stop(name, project.Directory)
 }
 
 // RestartInstance restarts a local instance
@@ -198,19 +220,72 @@ func (p *LocalProvider) RestartInstance(name string) error {
 		return err
 	}
 
-	dockerDir := filepath.Join(project.Directory, "supabase", "docker")
+	return p.Runner.Restart(name, project.Directory)
+}
 
-	// Run docker compose restart
-	cmd := exec.Command("docker", "compose", "-p", name, "restart")
-	cmd.Dir = dockerDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// StartServices starts services of a local instance, along with the services they depend on
+func (p *LocalProvider) StartServices(name string, services []string) error {
+	project, err := p.getProject(name)
+	if err != nil {
+		return err
+	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to restart instance: %w", err)
+	if err := local.CheckServices(project.Directory, services); err != nil {
+		return err
+	}
+	if err := local.SyncComposeOverride(name, project); err != nil {
+		return err
 	}
 
-	return nil
+	return p.Runner.Up(name, project.Directory, services...)
+}
+
+// StopServices stops services of a local instance, keeping their containers
+func (p *LocalProvider) StopServices(name string, services []string) error {
+	project, err := p.getProject(name)
+	if err != nil {
+		return err
+	}
+
+	if err := local.CheckServices(project.Directory, services); err != nil {
+		return err
+	}
+
+	return p.Runner.Stop(name, project.Directory, services...)
+}
+
+// RestartServices restarts services of a local instance
+func (p *LocalProvider) RestartServices(name string, services []string) error {
+	project, err := p.getProject(name)
+	if err != nil {
+		return err
+	}
+
+	if err := local.CheckServices(project.Directory, services); err != nil {
+		return err
+	}
+
+	return p.Runner.Restart(name, project.Directory, services...)
+}
+
+// RunningServices returns the sorted names of the services of a local instance that are running
+func (p *LocalProvider) RunningServices(name string) ([]string, error) {
+	project, err := p.getProject(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Runner.RunningServices(name, project.Directory)
+}
+
+// DownInstance stops and removes the containers of a local instance, and its volumes if removeVolumes is set
+func (p *LocalProvider) DownInstance(name string, removeVolumes bool) error {
+	project, err := p.getProject(name)
+	if err != nil {
+		return err
+	}
+
+	return p.Runner.Down(name, project.Directory, removeVolumes)
 }
 
 // GetLogs retrieves logs for a local instance
@@ -224,24 +299,202 @@ func (p *LocalProvider) GetLogs(name string, lines int) (string, error) {
 		return "", err
 	}
 
-	dockerDir := filepath.Join(project.Directory, "supabase", "docker")
+	return p.Runner.Logs(name, project.Directory, lines)
+}
+
+// ProviderType returns "local"
+func (p *LocalProvider) ProviderType() string {
+	return ProviderTypeLocal
+}
+
+// GetConfig returns the values of a local instance's .env file
+func (p *LocalProvider) GetConfig(name string) (map[string]string, error) {
+	project, err := p.getProject(name)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := local.LoadProjectEnv(project.Directory)
+	if err != nil {
+		return nil, err
+	}
 
-	// Run docker compose logs
-	cmd := exec.Command("docker", "compose", "-p", name, "logs", "--tail", fmt.Sprintf("%d", lines))
-	cmd.Dir = dockerDir
+	return env.Values(), nil
+}
 
-	output, err := cmd.CombinedOutput()
+// KnownConfigKeys returns the keys declared in the instance's .env.example (plus those already in .env)
+func (p *LocalProvider) KnownConfigKeys(name string) (map[string]bool, error) {
+	project, err := p.getProject(name)
 	if err != nil {
-		return "", fmt.Errorf("failed to get logs: %w", err)
+		return nil, err
 	}
 
-	return string(output), nil
+	return local.KnownEnvKeys(project.Directory)
 }
 
-// ProviderType returns "local"
-func (p *LocalProvider) ProviderType() string {
-	return ProviderTypeLocal
+// UpdateConfig rewrites the instance's .env file and returns the compose services that use the changed keys
+func (p *LocalProvider) UpdateConfig(name string, set map[string]string, unset []string) ([]string, error) {
+	project, err := p.getProject(name)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := local.LoadProjectEnv(project.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]string, 0, len(set)+len(unset))
+	for key, value := range set {
+		env.Set(key, value)
+		changed = append(changed, key)
+	}
+	for _, key := range unset {
+		if env.Unset(key) {
+			changed = append(changed, key)
+		}
+	}
+
+	if err := env.Save(local.GetEnvPath(project.Directory)); err != nil {
+		return nil, err
+	}
+
+	services, err := local.AffectedServices(project.Directory, changed)
+	if err != nil {
+		return nil, err
+	}
+	if services == nil {
+		services = []string{}
+	}
+
+	return services, nil
+}
+
+// ApplyConfig recreates the given services so they pick up the new .env values
+func (p *LocalProvider) ApplyConfig(name string, services []string) error {
+	project, err := p.getProject(name)
+	if err != nil {
+		return err
+	}
+
+	return p.Runner.Up(name, project.Directory, services...)
 }
 
-// Compile-time check to ensure LocalProvider implements InstanceProvider
-var _ InstanceProvider = (*LocalProvider)(nil)
+// SetLabels replaces the labels stored for a local instance
+func (p *LocalProvider) SetLabels(name string, labels map[string]string) error {
+	if err := p.reloadDatabase(); err != nil {
+		return err
+	}
+
+	project, ok := p.db.Projects[name]
+	if !ok {
+		return fmt.Errorf("project '%s' not found", name)
+	}
+
+	if len(labels) == 0 {
+		labels = nil
+	}
+	project.Labels = labels
+	p.db.Projects[name] = project
+
+	if err := local.SaveDatabase(p.db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	return nil
+}
+
+// ListFunctions returns the functions installed in the project's functions volume
+func (p *LocalProvider) ListFunctions(name string) ([]functions.Info, error) {
+	project, err := p.getProject(name)
+	if err != nil {
+		return nil, err
+	}
+
+	functionsDir := local.GetFunctionsDir(project.Directory)
+	if _, err := os.Stat(functionsDir); os.IsNotExist(err) {
+		return []functions.Info{}, nil
+	}
+
+	return functions.ListInstalled(functionsDir)
+}
+
+// DeployFunctions installs functions into the project's functions volume and
+// restarts the functions service if the project is running
+func (p *LocalProvider) DeployFunctions(name string, sources []functions.Source) error {
+	project, err := p.getProject(name)
+	if err != nil {
+		return err
+	}
+
+	if err := functions.Install(local.GetFunctionsDir(project.Directory), sources); err != nil {
+		return err
+	}
+
+	return p.restartFunctions(name, project.Directory)
+}
+
+// DeleteFunction removes a function from the project's functions volume and
+// restarts the functions service if the project is running
+func (p *LocalProvider) DeleteFunction(name, function string) error {
+	project, err := p.getProject(name)
+	if err != nil {
+		return err
+	}
+
+	if err := functions.Uninstall(local.GetFunctionsDir(project.Directory), function); err != nil {
+		return err
+	}
+
+	return p.restartFunctions(name, project.Directory)
+}
+
+// restartFunctions restarts the edge runtime so it drops cached workers
+func (p *LocalProvider) restartFunctions(projectID, directory string) error {
+	if !p.isRunning(projectID, directory) {
+		return nil
+	}
+	return p.Runner.Restart(projectID, directory, "functions")
+}
+
+// GetMetrics returns the CPU and memory usage of the project's containers. Database usage
+// is not included; the database is queried by the caller.
+func (p *LocalProvider) GetMetrics(name string) (*metrics.Instance, error) {
+	project, err := p.getProject(name)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := p.Runner.Stats(name, project.Directory)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("instance '%s' is not running", name)
+	}
+
+	inst := metrics.Aggregate(name, services)
+	inst.CollectedAt = time.Now()
+	return inst, nil
+}
+
+// getProject loads the database from disk and returns the named project. It leaves p.db
+// alone, so it is safe for concurrent use (top collects several instances' metrics at once).
+func (p *LocalProvider) getProject(name string) (*local.Project, error) {
+	db, err := local.LoadDatabase()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload local database: %w", err)
+	}
+
+	return db.GetProject(name)
+}
+
+// Compile-time checks to ensure LocalProvider implements the provider interfaces
+var (
+	_ InstanceProvider  = (*LocalProvider)(nil)
+	_ ConfigProvider    = (*LocalProvider)(nil)
+	_ LabelProvider     = (*LocalProvider)(nil)
+	_ FunctionsProvider = (*LocalProvider)(nil)
+	_ MetricsProvider   = (*LocalProvider)(nil)
+	_ ServiceProvider   = (*LocalProvider)(nil)
+)
@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/local"
+	"github.com/qubitquilt/supactl/internal/testutil"
+)
+
+// newTestLocalProvider registers a project "app" in a temporary home directory
+func newTestLocalProvider(t *testing.T) *LocalProvider {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	directory := filepath.Join(home, "app")
+	testutil.CreateTestFile(t, local.GetDockerDir(directory), local.ComposeFileName, "services:\n  db:\n    image: supabase/postgres\n  realtime:\n    image: supabase/realtime\n")
+
+	db, err := local.LoadDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.AddProject("app", directory); err != nil {
+		t.Fatal(err)
+	}
+	if err := local.SaveDatabase(db); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewLocalProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// Regression test: StopInstance used to run 'down -v', deleting the instance's data volumes
+func TestLocalProvider_StopKeepsVolumes(t *testing.T) {
+	// The fake prints a container ID, so the instance looks running
+	calls := testutil.FakeCommand(t, "docker", "abc123\n")
+	p := newTestLocalProvider(t)
+	base := []string{"compose", "-p", "app", "-f", local.ComposeFileName}
+
+	if err := p.StopInstance("app"); err != nil {
+		t.Fatalf("StopInstance failed: %v", err)
+	}
+	if err := p.StopServices("app", []string{"realtime"}); err != nil {
+		t.Fatalf("StopServices failed: %v", err)
+	}
+	if err := p.DownInstance("app", false); err != nil {
+		t.Fatalf("DownInstance failed: %v", err)
+	}
+
+	want := [][]string{
+		append(append([]string{}, base...), "ps", "-q"),
+		append(append([]string{}, base...), "stop"),
+		append(append([]string{}, base...), "stop", "realtime"),
+		append(append([]string{}, base...), "down", "--remove-orphans"),
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("docker runs = %q, want %q", got, want)
+	}
+
+	if err := p.StopServices("app", []string{"nope"}); err == nil {
+		t.Error("StopServices should reject unknown services")
+	}
+	if len(calls()) != len(want) {
+		t.Error("unknown services should be rejected before running docker")
+	}
+}
+
+func TestLocalProvider_DownRemovesVolumesOnRequest(t *testing.T) {
+	calls := testutil.FakeCommand(t, "docker", "")
+	p := newTestLocalProvider(t)
+
+	if err := p.DownInstance("app", true); err != nil {
+		t.Fatalf("DownInstance failed: %v", err)
+	}
+
+	want := [][]string{{"compose", "-p", "app", "-f", local.ComposeFileName, "down", "--remove-orphans", "-v"}}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("docker runs = %q, want %q", got, want)
+	}
+}
+
+// newRecordingLocalProvider returns a test provider whose docker commands are recorded
+func newRecordingLocalProvider(t *testing.T) (*LocalProvider, *testutil.RecordingExecutor) {
+	t.Helper()
+	p := newTestLocalProvider(t)
+	exec := &testutil.RecordingExecutor{}
+	p.Runner = local.NewCLIRunner(exec)
+	return p, exec
+}
+
+func TestLocalProvider_StartInstanceAlreadyRunning(t *testing.T) {
+	p, exec := newRecordingLocalProvider(t)
+	exec.On("docker compose -p app -f "+local.ComposeFileName+" ps -q", "abc123\n", nil)
+
+	err := p.StartInstance("app")
+	if err == nil || err.Error() != "instance 'app' is already running" {
+		t.Fatalf("StartInstance() = %v, want already running", err)
+	}
+	if got := exec.CommandLines(); len(got) != 1 {
+		t.Errorf("only ps should run, got %q", got)
+	}
+}
+
+func TestLocalProvider_StopInstanceNotRunning(t *testing.T) {
+	p, exec := newRecordingLocalProvider(t)
+
+	if err := p.StopInstance("app"); err == nil {
+		t.Fatal("StopInstance should fail when no container is running")
+	}
+	want := []string{"docker compose -p app -f " + local.ComposeFileName + " ps -q"}
+	if got := exec.CommandLines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
+
+func TestLocalProvider_RestartAndLogs(t *testing.T) {
+	p, exec := newRecordingLocalProvider(t)
+	exec.On("docker compose -p app -f "+local.ComposeFileName+" logs", "db | ready\n", nil)
+
+	if err := p.RestartInstance("app"); err != nil {
+		t.Fatalf("RestartInstance failed: %v", err)
+	}
+	if err := p.RestartServices("app", []string{"db"}); err != nil {
+		t.Fatalf("RestartServices failed: %v", err)
+	}
+	logs, err := p.GetLogs("app", 20)
+	if err != nil {
+		t.Fatalf("GetLogs failed: %v", err)
+	}
+	if logs != "db | ready\n" {
+		t.Errorf("GetLogs() = %q", logs)
+	}
+
+	base := "docker compose -p app -f " + local.ComposeFileName
+	want := []string{base + " restart", base + " restart db", base + " logs --tail 20"}
+	if got := exec.CommandLines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+	if dir, want := exec.Calls()[0].Dir, local.GetDockerDir(filepath.Join(os.Getenv("HOME"), "app")); dir != want {
+		t.Errorf("docker ran in %q, want %q", dir, want)
+	}
+}
+
+func TestLocalProvider_RunningServices(t *testing.T) {
+	p, exec := newRecordingLocalProvider(t)
+	exec.On("docker compose -p app -f "+local.ComposeFileName+" ps --services --status running", "realtime\ndb\n", nil)
+
+	services, err := p.RunningServices("app")
+	if err != nil {
+		t.Fatalf("RunningServices failed: %v", err)
+	}
+	if !reflect.DeepEqual(services, []string{"db", "realtime"}) {
+		t.Errorf("RunningServices() = %v, want [db realtime]", services)
+	}
+}
+
+func TestLocalProvider_GetMetricsNotRunning(t *testing.T) {
+	p, _ := newRecordingLocalProvider(t)
+
+	if _, err := p.GetMetrics("app"); err == nil || err.Error() != "instance 'app' is not running" {
+		t.Errorf("GetMetrics() = %v, want not running", err)
+	}
+}
+
+// Regression test: top collects metrics concurrently, and GetMetrics used to replace the
+// provider's database while other goroutines read it
+func TestLocalProvider_GetMetricsConcurrent(t *testing.T) {
+	p, _ := newRecordingLocalProvider(t)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 16)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = p.GetMetrics("app")
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil || err.Error() != "instance 'app' is not running" {
+			t.Errorf("GetMetrics() #%d = %v, want not running", i, err)
+		}
+	}
+}
+
+func TestLocalProvider_UnknownInstance(t *testing.T) {
+	p, exec := newRecordingLocalProvider(t)
+
+	if err := p.RestartInstance("nope"); err == nil {
+		t.Error("RestartInstance should fail for an unknown instance")
+	}
+	if _, err := p.GetLogs("nope", 10); err == nil {
+		t.Error("GetLogs should fail for an unknown instance")
+	}
+	if got := exec.CommandLines(); len(got) != 0 {
+		t.Errorf("docker should not run for unknown instances, got %q", got)
+	}
+}
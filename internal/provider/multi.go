@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ContextInstances is the result of listing the instances of one context
+type ContextInstances struct {
+	Context   string
+	Instances []Instance
+	Err       error
+}
+
+// ListAcrossContexts lists the instances of several contexts concurrently. open creates the
+// provider of a context; each context gets at most timeout to open and list its instances.
+// Results are returned in the order of contexts, with failures reported per context.
+func ListAcrossContexts(contexts []string, open func(context string) (InstanceProvider, error), timeout time.Duration) []ContextInstances {
+	results := make([]ContextInstances, len(contexts))
+
+	var wg sync.WaitGroup
+	for i, name := range contexts {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i] = listWithTimeout(name, open, timeout)
+		}(i, name)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// listWithTimeout lists one context's instances, giving up after timeout. A provider that
+// does not return in time keeps running in the background; its result is discarded.
+func listWithTimeout(context string, open func(context string) (InstanceProvider, error), timeout time.Duration) ContextInstances {
+	done := make(chan ContextInstances, 1)
+	go func() {
+		p, err := open(context)
+		if err != nil {
+			done <- ContextInstances{Context: context, Err: err}
+			return
+		}
+		instances, err := p.ListInstances()
+		done <- ContextInstances{Context: context, Instances: instances, Err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(timeout):
+		return ContextInstances{Context: context, Err: fmt.Errorf("timed out after %s", timeout)}
+	}
+}
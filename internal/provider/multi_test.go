@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubProvider returns fixed instances, optionally after a delay
+type stubProvider struct {
+	InstanceProvider
+	instances []Instance
+	delay     time.Duration
+}
+
+func (s *stubProvider) ListInstances() ([]Instance, error) {
+	time.Sleep(s.delay)
+	return s.instances, nil
+}
+
+func TestListAcrossContexts(t *testing.T) {
+	providers := map[string]*stubProvider{
+		"local": {instances: []Instance{{Name: "a"}, {Name: "b"}}},
+		"prod":  {instances: []Instance{{Name: "c"}}, delay: 10 * time.Millisecond},
+		"slow":  {instances: []Instance{{Name: "d"}}, delay: time.Second},
+	}
+	open := func(context string) (InstanceProvider, error) {
+		if p, ok := providers[context]; ok {
+			return p, nil
+		}
+		return nil, errors.New("missing credentials")
+	}
+
+	start := time.Now()
+	results := ListAcrossContexts([]string{"prod", "broken", "local", "slow"}, open, 200*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Errorf("listing took %s; contexts should be queried concurrently with a timeout", elapsed)
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+
+	for i, want := range []struct {
+		context   string
+		instances int
+		wantErr   bool
+	}{
+		{"prod", 1, false},
+		{"broken", 0, true},
+		{"local", 2, false},
+		{"slow", 0, true},
+	} {
+		got := results[i]
+		if got.Context != want.context || len(got.Instances) != want.instances || (got.Err != nil) != want.wantErr {
+			t.Errorf("results[%d] = {%s, %d instances, err %v}, want {%s, %d instances, err %v}",
+				i, got.Context, len(got.Instances), got.Err, want.context, want.instances, want.wantErr)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package provider
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/audit"
+	"github.com/qubitquilt/supactl/internal/functions"
+	"github.com/qubitquilt/supactl/internal/metrics"
+)
 
 // Instance represents a unified Supabase instance across both remote and local providers.
 // This abstraction allows the CLI to work with instances regardless of their backend.
@@ -12,6 +19,9 @@ type Instance struct {
 	APIURL    string    `json:"api_url"`
 	CreatedAt time.Time `json:"created_at,omitempty"`
 
+	// Labels are user-defined key/value pairs, e.g. set from a manifest by 'supactl apply'
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// Remote-specific fields (optional, populated only for remote instances)
 	KongURL     string `json:"kong_url,omitempty"`
 	AnonKey     string `json:"anon_key,omitempty"`
@@ -23,6 +33,16 @@ type Instance struct {
 	DBPort    int    `json:"db_port,omitempty"`
 }
 
+// GatewayURL returns the base URL of the instance's API gateway (Kong), which serves
+// /rest/v1, /auth/v1 and /storage/v1
+func (i *Instance) GatewayURL() string {
+	if i.KongURL != "" {
+		return strings.TrimRight(i.KongURL, "/")
+	}
+	base := strings.TrimRight(i.APIURL, "/")
+	return strings.TrimSuffix(base, "/rest/v1")
+}
+
 // InstanceProvider defines the abstract contract for managing Supabase instances.
 // This interface is implemented by RemoteProvider (SupaControl API) and LocalProvider (Docker).
 type InstanceProvider interface {
@@ -41,7 +61,7 @@ type InstanceProvider interface {
 	// StartInstance starts a stopped instance
 	StartInstance(name string) error
 
-	// StopInstance stops a running instance
+	// StopInstance stops a running instance, keeping its data
 	StopInstance(name string) error
 
 	// RestartInstance restarts an instance (stop + start)
@@ -54,6 +74,73 @@ type InstanceProvider interface {
 	ProviderType() string
 }
 
+// ConfigProvider is implemented by providers that can read and change an instance's
+// environment configuration (SMTP settings, site URL, JWT expiry, OAuth providers, ...)
+type ConfigProvider interface {
+	// GetConfig returns the instance's configuration values
+	GetConfig(name string) (map[string]string, error)
+
+	// KnownConfigKeys returns the keys the instance accepts, or nil if the provider does not restrict them
+	KnownConfigKeys(name string) (map[string]bool, error)
+
+	// UpdateConfig sets and unsets configuration keys and returns the services affected by the change.
+	// A nil result means the provider cannot tell which services are affected.
+	UpdateConfig(name string, set map[string]string, unset []string) ([]string, error)
+
+	// ApplyConfig restarts the given services (or the whole instance if none) to apply configuration changes
+	ApplyConfig(name string, services []string) error
+}
+
+// LabelProvider is implemented by providers that can store labels on an instance
+type LabelProvider interface {
+	// SetLabels replaces all labels of an instance
+	SetLabels(name string, labels map[string]string) error
+}
+
+// FunctionsProvider is implemented by providers that can deploy edge functions to an instance
+type FunctionsProvider interface {
+	// ListFunctions returns the functions deployed to an instance
+	ListFunctions(name string) ([]functions.Info, error)
+
+	// DeployFunctions creates or replaces functions and makes the edge runtime serve them
+	DeployFunctions(name string, sources []functions.Source) error
+
+	// DeleteFunction removes a function from an instance
+	DeleteFunction(name, function string) error
+}
+
+// MetricsProvider is implemented by providers that can report an instance's resource usage
+type MetricsProvider interface {
+	// GetMetrics returns a snapshot of the CPU, memory, disk and connection usage of a running instance
+	GetMetrics(name string) (*metrics.Instance, error)
+}
+
+// ServiceProvider is implemented by providers that can act on individual services of an
+// instance and remove an instance's containers
+type ServiceProvider interface {
+	// StartServices starts the given services, along with the services they depend on
+	StartServices(name string, services []string) error
+
+	// StopServices stops the given services, keeping their containers and data
+	StopServices(name string, services []string) error
+
+	// RestartServices restarts the given services
+	RestartServices(name string, services []string) error
+
+	// RunningServices returns the sorted names of the instance's services that are running
+	RunningServices(name string) ([]string, error)
+
+	// DownInstance stops and removes an instance's containers. The volumes holding the
+	// instance's data are only removed if removeVolumes is set.
+	DownInstance(name string, removeVolumes bool) error
+}
+
+// AuditProvider is implemented by providers that keep a server-side audit trail
+type AuditProvider interface {
+	// ListAuditEvents returns the recorded operations selected by the filter, oldest first
+	ListAuditEvents(filter audit.Filter) ([]audit.Record, error)
+}
+
 // ProviderType constants
 const (
 	ProviderTypeRemote = "remote"
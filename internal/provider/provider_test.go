@@ -0,0 +1,20 @@
+package provider
+
+import "testing"
+
+func TestInstance_GatewayURL(t *testing.T) {
+	tests := []struct {
+		instance Instance
+		want     string
+	}{
+		{Instance{APIURL: "http://192.168.1.10:8000/rest/v1/"}, "http://192.168.1.10:8000"},
+		{Instance{APIURL: "https://project.example.com"}, "https://project.example.com"},
+		{Instance{APIURL: "https://project.example.com/rest/v1", KongURL: "https://kong.example.com/"}, "https://kong.example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.instance.GatewayURL(); got != tt.want {
+			t.Errorf("GatewayURL() = %s, want %s", got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package provider
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/qubitquilt/supactl/internal/api"
+	"github.com/qubitquilt/supactl/internal/audit"
+	"github.com/qubitquilt/supactl/internal/functions"
+	"github.com/qubitquilt/supactl/internal/metrics"
 )
 
 // RemoteProvider implements InstanceProvider for remote SupaControl server instances
@@ -18,22 +22,26 @@ func NewRemoteProvider(serverURL, apiKey string) *RemoteProvider {
 	}
 }
 
-// mapAPIInstanceToInstance converts an API instance to a unified provider instance
-func mapAPIInstanceToInstance(apiInstance *api.Instance) *Instance {
-	// Parse created_at timestamp if available
-	var createdAt time.Time
-	if apiInstance.CreatedAt != "" {
-		// Try RFC3339 format first
-		t, err := time.Parse(time.RFC3339, apiInstance.CreatedAt)
-		if err != nil {
-			// Try alternative format
-			t, err = time.Parse("2006-01-02 15:04:05", apiInstance.CreatedAt)
-		}
-		if err == nil {
-			createdAt = t
-		}
+// parseTimestamp parses a server timestamp, returning the zero time if it is empty or malformed
+func parseTimestamp(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	// Try RFC3339 format first
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		// Try alternative format
+		t, err = time.Parse("2006-01-02 15:04:05", value)
+	}
+	if err != nil {
+		return time.Time{}
 	}
+	return t
+}
 
+// mapAPIInstanceToInstance converts an API instance to a unified provider instance
+func mapAPIInstanceToInstance(apiInstance *api.Instance) *Instance {
 	return &Instance{
 		Name:        apiInstance.Name,
 		Status:      apiInstance.Status,
@@ -43,7 +51,8 @@ func mapAPIInstanceToInstance(apiInstance *api.Instance) *Instance {
 		AnonKey:     apiInstance.AnonKey,
 		ServiceKey:  apiInstance.ServiceKey,
 		DatabaseURL: apiInstance.DatabaseURL,
-		CreatedAt:   createdAt,
+		CreatedAt:   parseTimestamp(apiInstance.CreatedAt),
+		Labels:      apiInstance.Labels,
 	}
 }
 
@@ -117,5 +126,132 @@ func (p *RemoteProvider) ValidateConnection() error {
 	return p.client.LoginTest()
 }
 
-// Compile-time check to ensure RemoteProvider implements InstanceProvider
-var _ InstanceProvider = (*RemoteProvider)(nil)
+// GetConfig retrieves the configuration of a remote instance
+func (p *RemoteProvider) GetConfig(name string) (map[string]string, error) {
+	return p.client.GetInstanceConfig(name)
+}
+
+// KnownConfigKeys returns nil: the SupaControl server validates keys itself
+func (p *RemoteProvider) KnownConfigKeys(name string) (map[string]bool, error) {
+	return nil, nil
+}
+
+// UpdateConfig updates the configuration of a remote instance
+func (p *RemoteProvider) UpdateConfig(name string, set map[string]string, unset []string) ([]string, error) {
+	if err := p.client.UpdateInstanceConfig(name, set, unset); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// ApplyConfig restarts a remote instance; the server does not support per-service restarts
+func (p *RemoteProvider) ApplyConfig(name string, services []string) error {
+	return p.client.RestartInstance(name)
+}
+
+// SetLabels replaces the labels of a remote instance
+func (p *RemoteProvider) SetLabels(name string, labels map[string]string) error {
+	return p.client.SetInstanceLabels(name, labels)
+}
+
+// ListFunctions returns the functions deployed to a remote instance
+func (p *RemoteProvider) ListFunctions(name string) ([]functions.Info, error) {
+	apiFunctions, err := p.client.ListFunctions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]functions.Info, len(apiFunctions))
+	for i, fn := range apiFunctions {
+		infos[i] = functions.Info{Name: fn.Name, UpdatedAt: parseTimestamp(fn.UpdatedAt)}
+	}
+
+	return infos, nil
+}
+
+// DeployFunctions uploads functions to a remote instance, one request per function
+func (p *RemoteProvider) DeployFunctions(name string, sources []functions.Source) error {
+	for _, source := range sources {
+		files := make([]api.FunctionFile, len(source.Files))
+		for i, file := range source.Files {
+			files[i] = api.FunctionFile{Path: file.Path, Content: file.Content}
+		}
+
+		if err := p.client.DeployFunction(name, source.Name, files); err != nil {
+			return fmt.Errorf("failed to deploy '%s': %w", source.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteFunction deletes a function from a remote instance
+func (p *RemoteProvider) DeleteFunction(name, function string) error {
+	return p.client.DeleteFunction(name, function)
+}
+
+// GetMetrics returns the resource usage reported by SupaControl for a remote instance
+func (p *RemoteProvider) GetMetrics(name string) (*metrics.Instance, error) {
+	apiMetrics, err := p.client.GetInstanceMetrics(name)
+	if err != nil {
+		return nil, err
+	}
+
+	inst := &metrics.Instance{
+		Name:             name,
+		CPUPercent:       apiMetrics.CPUPercent,
+		MemoryBytes:      apiMetrics.MemoryBytes,
+		MemoryLimitBytes: apiMetrics.MemoryLimitBytes,
+		CollectedAt:      parseTimestamp(apiMetrics.CollectedAt),
+	}
+	for _, svc := range apiMetrics.Services {
+		inst.Services = append(inst.Services, metrics.Service{
+			Name:             svc.Name,
+			CPUPercent:       svc.CPUPercent,
+			MemoryBytes:      svc.MemoryBytes,
+			MemoryLimitBytes: svc.MemoryLimitBytes,
+		})
+	}
+	if apiMetrics.Database != nil {
+		inst.Database = &metrics.Database{SizeBytes: apiMetrics.Database.SizeBytes, Connections: apiMetrics.Database.Connections}
+	}
+	if inst.CollectedAt.IsZero() {
+		inst.CollectedAt = time.Now()
+	}
+
+	return inst, nil
+}
+
+// ListAuditEvents returns the audit trail kept by the SupaControl server
+func (p *RemoteProvider) ListAuditEvents(filter audit.Filter) ([]audit.Record, error) {
+	events, err := p.client.ListAuditEvents(filter.Instance, filter.Since, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]audit.Record, len(events))
+	for i, event := range events {
+		records[i] = audit.Record{
+			Time:       parseTimestamp(event.Time),
+			User:       event.User,
+			Provider:   ProviderTypeRemote,
+			Instance:   event.Instance,
+			Action:     event.Action,
+			Result:     event.Result,
+			Error:      event.Error,
+			DurationMS: event.DurationMS,
+		}
+	}
+
+	return audit.Limit(records, filter.Limit), nil
+}
+
+// Compile-time checks to ensure RemoteProvider implements the provider interfaces
+var (
+	_ InstanceProvider  = (*RemoteProvider)(nil)
+	_ ConfigProvider    = (*RemoteProvider)(nil)
+	_ LabelProvider     = (*RemoteProvider)(nil)
+	_ FunctionsProvider = (*RemoteProvider)(nil)
+	_ MetricsProvider   = (*RemoteProvider)(nil)
+	_ AuditProvider     = (*RemoteProvider)(nil)
+)
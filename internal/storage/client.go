@@ -0,0 +1,291 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client talks to the Storage API of a Supabase instance through its API gateway
+type Client struct {
+	BaseURL    string
+	ServiceKey string
+	HTTPClient *http.Client
+}
+
+// Bucket is a storage bucket
+type Bucket struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Public           bool      `json:"public"`
+	FileSizeLimit    *int64    `json:"file_size_limit,omitempty"`
+	AllowedMimeTypes []string  `json:"allowed_mime_types,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CreateBucketRequest is the body of a bucket creation request
+type CreateBucketRequest struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Public           bool     `json:"public"`
+	FileSizeLimit    *int64   `json:"file_size_limit,omitempty"`
+	AllowedMimeTypes []string `json:"allowed_mime_types,omitempty"`
+}
+
+// Object is an entry returned when listing a bucket. Folders have no ID.
+type Object struct {
+	Name      string                 `json:"name"`
+	ID        *string                `json:"id"`
+	UpdatedAt *time.Time             `json:"updated_at"`
+	Metadata  map[string]interface{} `json:"metadata"`
+}
+
+// IsFolder reports whether the entry is a folder (a common prefix) rather than a file
+func (o Object) IsFolder() bool {
+	return o.ID == nil
+}
+
+// Size returns the object size in bytes from its metadata
+func (o Object) Size() int64 {
+	if size, ok := o.Metadata["size"].(float64); ok {
+		return int64(size)
+	}
+	return 0
+}
+
+// errorResponse is the error body returned by the Storage API
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// listPageSize is the number of entries requested per list call
+const listPageSize = 1000
+
+// NewClient creates a Storage API client for an instance's gateway URL
+func NewClient(gatewayURL, serviceKey string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(gatewayURL, "/") + "/storage/v1",
+		ServiceKey: serviceKey,
+		HTTPClient: &http.Client{
+			Timeout: 5 * time.Minute,
+		},
+	}
+}
+
+// ListBuckets returns all buckets
+func (c *Client) ListBuckets() ([]Bucket, error) {
+	var buckets []Bucket
+	if err := c.doJSON("GET", "/bucket", nil, &buckets); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// CreateBucket creates a bucket
+func (c *Client) CreateBucket(req CreateBucketRequest) error {
+	if req.ID == "" {
+		req.ID = req.Name
+	}
+	return c.doJSON("POST", "/bucket", req, nil)
+}
+
+// EmptyBucket deletes all objects of a bucket
+func (c *Client) EmptyBucket(bucket string) error {
+	return c.doJSON("POST", "/bucket/"+url.PathEscape(bucket)+"/empty", nil, nil)
+}
+
+// DeleteBucket deletes an empty bucket
+func (c *Client) DeleteBucket(bucket string) error {
+	return c.doJSON("DELETE", "/bucket/"+url.PathEscape(bucket), nil, nil)
+}
+
+// List returns the files and folders directly under a prefix of a bucket
+func (c *Client) List(bucket, prefix string) ([]Object, error) {
+	var all []Object
+	for offset := 0; ; offset += listPageSize {
+		body := map[string]interface{}{
+			"prefix": prefix,
+			"limit":  listPageSize,
+			"offset": offset,
+			"sortBy": map[string]string{"column": "name", "order": "asc"},
+		}
+
+		var page []Object
+		if err := c.doJSON("POST", "/object/list/"+url.PathEscape(bucket), body, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+
+		if len(page) < listPageSize {
+			return all, nil
+		}
+	}
+}
+
+// ListRecursive returns the paths of all files under a prefix of a bucket
+func (c *Client) ListRecursive(bucket, prefix string) ([]string, error) {
+	prefix = strings.Trim(prefix, "/")
+
+	entries, err := c.List(bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		path := joinPath(prefix, entry.Name)
+		if !entry.IsFolder() {
+			paths = append(paths, path)
+			continue
+		}
+
+		nested, err := c.ListRecursive(bucket, path)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, nested...)
+	}
+
+	return paths, nil
+}
+
+// Upload stores an object, replacing an existing object at the same path
+func (c *Client) Upload(bucket, path string, body io.Reader, contentType string) error {
+	req, err := c.newRequest("POST", objectURL(bucket, path), body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("x-upsert", "true")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseError(resp)
+	}
+	return nil
+}
+
+// Download writes an object to w
+func (c *Client) Download(bucket, path string, w io.Writer) error {
+	req, err := c.newRequest("GET", objectURL(bucket, path), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseError(resp)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to read object: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes objects from a bucket
+func (c *Client) Remove(bucket string, paths []string) error {
+	return c.doJSON("DELETE", "/object/"+url.PathEscape(bucket), map[string][]string{"prefixes": paths}, nil)
+}
+
+// doJSON sends a request with an optional JSON body and decodes the JSON response into out
+func (c *Client) doJSON(method, endpoint string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := c.newRequest(method, endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseError(resp)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// newRequest creates an authenticated request for an endpoint below /storage/v1
+func (c *Client) newRequest(method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.BaseURL+endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.ServiceKey)
+	req.Header.Set("apikey", c.ServiceKey)
+	return req, nil
+}
+
+// parseError turns an error response into an error
+func parseError(resp *http.Response) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("HTTP %d: failed to read error response", resp.StatusCode)
+	}
+
+	var errResp errorResponse
+	if err := json.Unmarshal(data, &errResp); err == nil && errResp.Message != "" {
+		return fmt.Errorf("%s", errResp.Message)
+	}
+
+	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
+
+// objectURL returns the endpoint of an object, escaping each path segment
+func objectURL(bucket, path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "/object/" + url.PathEscape(bucket) + "/" + strings.Join(segments, "/")
+}
+
+// joinPath joins object path segments, ignoring empty ones
+func joinPath(parts ...string) string {
+	var segments []string
+	for _, part := range parts {
+		if part = strings.Trim(part, "/"); part != "" {
+			segments = append(segments, part)
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/testutil"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		arg        string
+		wantBucket string
+		wantPath   string
+		wantErr    bool
+	}{
+		{"ss:///avatars/users/a.png", "avatars", "users/a.png", false},
+		{"avatars/users/", "avatars", "users", false},
+		{"ss:///avatars", "avatars", "", false},
+		{"ss:///", "", "", true},
+	}
+
+	for _, tt := range tests {
+		bucket, path, err := ParsePath(tt.arg)
+		if (err != nil) != tt.wantErr || bucket != tt.wantBucket || path != tt.wantPath {
+			t.Errorf("ParsePath(%q) = %q, %q, %v; want %q, %q (error %v)", tt.arg, bucket, path, err, tt.wantBucket, tt.wantPath, tt.wantErr)
+		}
+	}
+}
+
+func TestClient_Buckets(t *testing.T) {
+	ms := testutil.NewMockServer()
+	defer ms.Close()
+
+	ms.On("GET", "/storage/v1/bucket", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer service-key" || r.Header.Get("apikey") != "service-key" {
+			testutil.RespondJSON(w, http.StatusUnauthorized, map[string]string{"message": "Invalid JWT"})
+			return
+		}
+		testutil.RespondJSON(w, http.StatusOK, []Bucket{{ID: "avatars", Name: "avatars", Public: true}})
+	})
+	ms.On("POST", "/storage/v1/bucket", func(w http.ResponseWriter, r *http.Request) {
+		var req CreateBucketRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ID != "docs" || req.Name != "docs" || req.Public {
+			t.Errorf("unexpected create request: %+v", req)
+		}
+		testutil.RespondJSON(w, http.StatusOK, map[string]string{"name": "docs"})
+	})
+	ms.On("DELETE", "/storage/v1/bucket/docs", func(w http.ResponseWriter, r *http.Request) {
+		testutil.RespondJSON(w, http.StatusBadRequest, map[string]string{"error": "InvalidRequest", "message": "The bucket you tried to delete is not empty"})
+	})
+
+	client := NewClient(ms.URL()+"/", "service-key")
+
+	buckets, err := client.ListBuckets()
+	if err != nil {
+		t.Fatalf("ListBuckets failed: %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "avatars" || !buckets[0].Public {
+		t.Errorf("ListBuckets() = %+v", buckets)
+	}
+
+	if err := client.CreateBucket(CreateBucketRequest{Name: "docs"}); err != nil {
+		t.Errorf("CreateBucket failed: %v", err)
+	}
+
+	err = client.DeleteBucket("docs")
+	if err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("DeleteBucket error = %v, want the API message", err)
+	}
+}
+
+// fakeBucket serves the list, upload and download endpoints of one bucket from memory
+type fakeBucket struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (f *fakeBucket) register(ms *testutil.MockServer) {
+	ms.On("POST", "/storage/v1/object/list/files", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Prefix string `json:"prefix"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		seen := make(map[string]bool)
+		var entries []map[string]interface{}
+		for path := range f.objects {
+			rest := path
+			if req.Prefix != "" {
+				if !strings.HasPrefix(path, req.Prefix+"/") {
+					continue
+				}
+				rest = strings.TrimPrefix(path, req.Prefix+"/")
+			}
+			name, _, isFolder := strings.Cut(rest, "/")
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			entry := map[string]interface{}{"name": name, "id": nil}
+			if !isFolder {
+				entry["id"] = "id-" + path
+			}
+			entries = append(entries, entry)
+		}
+		testutil.RespondJSON(w, http.StatusOK, entries)
+	})
+
+	for _, method := range []string{"POST", "GET"} {
+		method := method
+		for _, path := range []string{"a.txt", "dir/b.txt", "dir/sub/c.txt"} {
+			path := path
+			ms.On(method, "/storage/v1/object/files/"+path, func(w http.ResponseWriter, r *http.Request) {
+				f.mu.Lock()
+				defer f.mu.Unlock()
+				if method == "POST" {
+					data, _ := io.ReadAll(r.Body)
+					f.objects[path] = string(data)
+					testutil.RespondJSON(w, http.StatusOK, map[string]string{"Key": "files/" + path})
+					return
+				}
+				io.WriteString(w, f.objects[path])
+			})
+		}
+	}
+}
+
+func TestTransfer_RecursiveRoundTrip(t *testing.T) {
+	ms := testutil.NewMockServer()
+	defer ms.Close()
+
+	bucket := &fakeBucket{objects: make(map[string]string)}
+	bucket.register(ms)
+	client := NewClient(ms.URL(), "service-key")
+
+	src := t.TempDir()
+	testutil.CreateTestFile(t, src, "a.txt", "A")
+	testutil.CreateTestFile(t, src, filepath.Join("dir", "b.txt"), "B")
+	testutil.CreateTestFile(t, src, filepath.Join("dir", "sub", "c.txt"), "C")
+
+	tasks, err := PlanUpload(src, "files", "", true)
+	if err != nil {
+		t.Fatalf("PlanUpload failed: %v", err)
+	}
+
+	var reported []string
+	progress := func(done, total int, task Task, err error) {
+		if err != nil {
+			t.Errorf("%s: %v", task, err)
+		}
+		reported = append(reported, task.Path)
+		if total != 3 || done != len(reported) {
+			t.Errorf("progress %d/%d after %d tasks", done, total, len(reported))
+		}
+	}
+	if err := client.Transfer(tasks, 2, progress); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	want := map[string]string{"a.txt": "A", "dir/b.txt": "B", "dir/sub/c.txt": "C"}
+	if !reflect.DeepEqual(bucket.objects, want) {
+		t.Errorf("bucket contents = %v, want %v", bucket.objects, want)
+	}
+
+	paths, err := client.ListRecursive("files", "dir")
+	if err != nil {
+		t.Fatalf("ListRecursive failed: %v", err)
+	}
+	sort.Strings(paths)
+	if !reflect.DeepEqual(paths, []string{"dir/b.txt", "dir/sub/c.txt"}) {
+		t.Errorf("ListRecursive(dir) = %v", paths)
+	}
+
+	dest := t.TempDir()
+	tasks, err = client.PlanDownload("files", "dir", dest, true)
+	if err != nil {
+		t.Fatalf("PlanDownload failed: %v", err)
+	}
+	if err := client.Transfer(tasks, 4, nil); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+
+	if got := testutil.ReadFile(t, filepath.Join(dest, "sub", "c.txt")); got != "C" {
+		t.Errorf("downloaded sub/c.txt = %q, want C", got)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "a.txt")); err == nil {
+		t.Error("a.txt is outside the downloaded prefix")
+	}
+}
+
+// Regression test: object names containing ".." were written outside the download directory
+func TestPlanDownload_RejectsEscapingNames(t *testing.T) {
+	ms := testutil.NewMockServer()
+	defer ms.Close()
+
+	ms.On("POST", "/storage/v1/object/list/files", func(w http.ResponseWriter, r *http.Request) {
+		testutil.RespondJSON(w, http.StatusOK, []map[string]interface{}{
+			{"name": "ok.txt", "id": "1"},
+			{"name": "../../evil.txt", "id": "2"},
+		})
+	})
+	client := NewClient(ms.URL(), "service-key")
+
+	dest := t.TempDir()
+	if _, err := client.PlanDownload("files", "dir", dest, true); err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Errorf("PlanDownload error = %v, want a refusal", err)
+	}
+	if _, err := client.PlanDownload("files", "dir/..", dest, false); err == nil {
+		t.Error("PlanDownload should refuse to write to the parent of the destination")
+	}
+
+	for rel, ok := range map[string]bool{"a.txt": true, "sub/../b.txt": true, "../a.txt": false, "sub/../../a.txt": false, "": false} {
+		if _, err := localTarget(dest, rel); (err == nil) != ok {
+			t.Errorf("localTarget(%q) error = %v, want ok %v", rel, err, ok)
+		}
+	}
+}
+
+func TestPlanUpload_SingleFile(t *testing.T) {
+	dir := t.TempDir()
+	file := testutil.CreateTestFile(t, dir, "logo.png", "png")
+
+	tests := map[string]string{
+		"":               "logo.png",
+		"images/":        "images/logo.png",
+		"images/new.png": "images/new.png",
+	}
+	for dest, want := range tests {
+		tasks, err := PlanUpload(file, "public", dest, false)
+		if err != nil {
+			t.Fatalf("PlanUpload failed: %v", err)
+		}
+		if len(tasks) != 1 || tasks[0].Path != want {
+			t.Errorf("PlanUpload(dest %q) = %+v, want path %s", dest, tasks, want)
+		}
+	}
+
+	if _, err := PlanUpload(dir, "public", "", false); err == nil {
+		t.Error("expected an error for a directory without --recursive")
+	}
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RemotePrefix marks a storage path in cp arguments, e.g. ss:///avatars/user.png
+const RemotePrefix = "ss:///"
+
+// Task copies one file between the local disk and a bucket
+type Task struct {
+	Bucket    string
+	Path      string
+	LocalPath string
+	Upload    bool
+}
+
+// String describes the copy, e.g. "./a.png -> ss:///avatars/a.png"
+func (t Task) String() string {
+	remote := RemotePrefix + joinPath(t.Bucket, t.Path)
+	if t.Upload {
+		return t.LocalPath + " -> " + remote
+	}
+	return remote + " -> " + t.LocalPath
+}
+
+// IsRemote reports whether a cp argument refers to storage
+func IsRemote(arg string) bool {
+	return strings.HasPrefix(arg, RemotePrefix)
+}
+
+// ParsePath splits a storage path ("bucket/dir/file" or "ss:///bucket/dir/file") into bucket and object path
+func ParsePath(arg string) (string, string, error) {
+	trimmed := strings.Trim(strings.TrimPrefix(arg, RemotePrefix), "/")
+	bucket, objectPath, _ := strings.Cut(trimmed, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("invalid storage path '%s': a bucket is required", arg)
+	}
+	return bucket, objectPath, nil
+}
+
+// PlanUpload returns the tasks that copy a local file, or a directory when recursive,
+// into a bucket. A destination ending in "/" (or the bucket root) keeps the file name.
+func PlanUpload(localPath, bucket, dest string, recursive bool) ([]Task, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		target := strings.TrimLeft(dest, "/")
+		if target == "" || strings.HasSuffix(target, "/") {
+			target = joinPath(target, filepath.Base(localPath))
+		}
+		return []Task{{Bucket: bucket, Path: target, LocalPath: localPath, Upload: true}}, nil
+	}
+
+	if !recursive {
+		return nil, fmt.Errorf("%s is a directory (use --recursive)", localPath)
+	}
+
+	var tasks []Task
+	err = filepath.WalkDir(localPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(localPath, p)
+		if err != nil {
+			return err
+		}
+		tasks = append(tasks, Task{Bucket: bucket, Path: joinPath(dest, filepath.ToSlash(rel)), LocalPath: p, Upload: true})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", localPath, err)
+	}
+
+	return tasks, nil
+}
+
+// PlanDownload returns the tasks that copy an object, or every object under a prefix
+// when recursive, to the local disk
+func (c *Client) PlanDownload(bucket, objectPath, localPath string, recursive bool) ([]Task, error) {
+	if !recursive {
+		if objectPath == "" {
+			return nil, fmt.Errorf("a bucket can only be copied with --recursive")
+		}
+		target := localPath
+		if info, err := os.Stat(localPath); (err == nil && info.IsDir()) || strings.HasSuffix(localPath, string(os.PathSeparator)) {
+			var err error
+			if target, err = localTarget(localPath, path.Base(objectPath)); err != nil {
+				return nil, err
+			}
+		}
+		return []Task{{Bucket: bucket, Path: objectPath, LocalPath: target}}, nil
+	}
+
+	paths, err := c.ListRecursive(bucket, objectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.Trim(objectPath, "/")
+	tasks := make([]Task, 0, len(paths))
+	for _, p := range paths {
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, prefix), "/")
+		target, err := localTarget(localPath, rel)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, Task{Bucket: bucket, Path: p, LocalPath: target})
+	}
+	return tasks, nil
+}
+
+// localTarget returns where the object at rel (relative to the downloaded prefix) is
+// written. Object names come from the server, so names that would land outside localPath,
+// such as "../x", are rejected.
+func localTarget(localPath, rel string) (string, error) {
+	root := filepath.Clean(localPath)
+	target := filepath.Join(root, filepath.FromSlash(rel))
+
+	inside, err := filepath.Rel(root, target)
+	if err != nil || inside == "." || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("refusing to download '%s' outside %s", rel, localPath)
+	}
+	return target, nil
+}
+
+// Transfer runs tasks with up to jobs concurrent copies. progress is called after each
+// task, one call at a time. All tasks are attempted; the error reports how many failed.
+func (c *Client) Transfer(tasks []Task, jobs int, progress func(done, total int, task Task, err error)) error {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	queue := make(chan Task)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	done, failed := 0, 0
+
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range queue {
+				err := c.runTask(task)
+
+				mu.Lock()
+				done++
+				if err != nil {
+					failed++
+				}
+				if progress != nil {
+					progress(done, len(tasks), task, err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, task := range tasks {
+		queue <- task
+	}
+	close(queue)
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed to copy", failed, len(tasks))
+	}
+	return nil
+}
+
+// runTask copies a single file
+func (c *Client) runTask(task Task) error {
+	if task.Upload {
+		f, err := os.Open(task.LocalPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return c.Upload(task.Bucket, task.Path, f, mime.TypeByExtension(filepath.Ext(task.LocalPath)))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(task.LocalPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.Create(task.LocalPath)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Download(task.Bucket, task.Path, f); err != nil {
+		f.Close()
+		os.Remove(task.LocalPath)
+		return err
+	}
+	return f.Close()
+}
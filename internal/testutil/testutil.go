@@ -2,10 +2,14 @@ package testutil
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -136,3 +140,138 @@ func SetEnvVar(t *testing.T, key, value string) func() {
 		}
 	}
 }
+
+// FakeCommand puts an executable named name first on PATH for the rest of the test. Each
+// run records its arguments and prints output. The returned function reads the argument
+// vectors of the runs so far. Tests using it are skipped on Windows.
+func FakeCommand(t *testing.T, name, output string) func() [][]string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake executables need a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, name+".log")
+	outputPath := filepath.Join(dir, name+".out")
+	if err := os.WriteFile(outputPath, []byte(output), 0644); err != nil {
+		t.Fatalf("failed to write fake output: %v", err)
+	}
+
+	// One line per run, arguments separated by NUL bytes
+	script := fmt.Sprintf("#!/bin/sh\nprintf '%%s\\000' \"$@\" >> '%s'\nprintf '\\n' >> '%s'\ncat '%s'\n", logPath, logPath, outputPath)
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake %s: %v", name, err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return func() [][]string {
+		data, err := os.ReadFile(logPath)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			t.Fatalf("failed to read fake %s log: %v", name, err)
+		}
+
+		var calls [][]string
+		for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			args := []string{}
+			if line != "" {
+				args = strings.Split(strings.TrimSuffix(line, "\x00"), "\x00")
+			}
+			calls = append(calls, args)
+		}
+		return calls
+	}
+}
+
+// ExecCall is a command run through a RecordingExecutor
+type ExecCall struct {
+	Dir  string
+	Env  []string
+	Name string
+	Args []string
+}
+
+// String returns the command line, e.g. "docker compose ps -q"
+func (c ExecCall) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// execResponse is the canned result of the commands starting with prefix
+type execResponse struct {
+	prefix string
+	output string
+	err    error
+}
+
+// RecordingExecutor is a fake command executor (local.CommandExecutor) that records the
+// commands it is asked to run instead of running them. Commands succeed with no output
+// unless a response is registered with On.
+type RecordingExecutor struct {
+	mu        sync.Mutex
+	calls     []ExecCall
+	responses []execResponse
+}
+
+// On makes the commands whose command line starts with prefix return output and err.
+// Later registrations take precedence.
+func (e *RecordingExecutor) On(prefix, output string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.responses = append(e.responses, execResponse{prefix: prefix, output: output, err: err})
+}
+
+// Run records a command and returns its registered error
+func (e *RecordingExecutor) Run(dir, name string, args ...string) error {
+	_, err := e.record(dir, nil, name, args)
+	return err
+}
+
+// Output records a command and returns its registered output and error
+func (e *RecordingExecutor) Output(dir, name string, args ...string) ([]byte, error) {
+	return e.record(dir, nil, name, args)
+}
+
+// CombinedOutput records a command and returns its registered output and error
+func (e *RecordingExecutor) CombinedOutput(dir, name string, args ...string) ([]byte, error) {
+	return e.record(dir, nil, name, args)
+}
+
+// RunInteractive records a command and returns its registered error
+func (e *RecordingExecutor) RunInteractive(env []string, name string, args ...string) error {
+	_, err := e.record("", env, name, args)
+	return err
+}
+
+// Calls returns the commands run so far
+func (e *RecordingExecutor) Calls() []ExecCall {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]ExecCall(nil), e.calls...)
+}
+
+// CommandLines returns the command lines run so far
+func (e *RecordingExecutor) CommandLines() []string {
+	var lines []string
+	for _, call := range e.Calls() {
+		lines = append(lines, call.String())
+	}
+	return lines
+}
+
+func (e *RecordingExecutor) record(dir string, env []string, name string, args []string) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	call := ExecCall{Dir: dir, Env: append([]string(nil), env...), Name: name, Args: append([]string(nil), args...)}
+	e.calls = append(e.calls, call)
+
+	line := call.String()
+	for i := len(e.responses) - 1; i >= 0; i-- {
+		if strings.HasPrefix(line, e.responses[i].prefix) {
+			return []byte(e.responses[i].output), e.responses[i].err
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,126 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "super-secret-jwt-token-with-at-least-32-characters"
+
+func TestBuildClaims(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	claims, err := BuildClaims(MintOptions{
+		Role:      "authenticated",
+		Subject:   "8d0fd2b3-9ca7-4d9e-a95f-9e13dded323e",
+		ExpiresIn: time.Hour,
+		Claims:    map[string]interface{}{"aal": "aal2", "aud": "custom"},
+		Now:       now,
+	})
+	if err != nil {
+		t.Fatalf("BuildClaims failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"role": "authenticated",
+		"sub":  "8d0fd2b3-9ca7-4d9e-a95f-9e13dded323e",
+		"iss":  Issuer,
+		"iat":  now.Unix(),
+		"exp":  now.Add(time.Hour).Unix(),
+		"aal":  "aal2",
+		"aud":  "custom",
+	}
+	for key, value := range want {
+		if claims[key] != value {
+			t.Errorf("claim %s = %v, want %v", key, claims[key], value)
+		}
+	}
+}
+
+func TestBuildClaims_Invalid(t *testing.T) {
+	if _, err := BuildClaims(MintOptions{ExpiresIn: time.Hour}); err == nil {
+		t.Error("expected error for missing role")
+	}
+	if _, err := BuildClaims(MintOptions{Role: "anon"}); err == nil {
+		t.Error("expected error for missing expiry")
+	}
+}
+
+func TestMintAndVerify(t *testing.T) {
+	signed, err := Mint(testSecret, MintOptions{Role: "authenticated", Subject: "user-1", ExpiresIn: time.Hour})
+	if err != nil {
+		t.Fatalf("Mint failed: %v", err)
+	}
+
+	decoded, err := Verify(signed, testSecret)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !decoded.Verified {
+		t.Error("expected token to be verified")
+	}
+	if decoded.Claims["sub"] != "user-1" || decoded.Header["alg"] != "HS256" {
+		t.Errorf("unexpected token: %+v", decoded)
+	}
+
+	decoded, err = Verify(signed, "another-secret")
+	if err == nil {
+		t.Fatal("expected error for wrong secret")
+	}
+	if decoded == nil || decoded.Verified || decoded.Claims["role"] != "authenticated" {
+		t.Errorf("expected unverified claims to be returned, got %+v", decoded)
+	}
+}
+
+func TestVerify_Expired(t *testing.T) {
+	signed, err := Mint(testSecret, MintOptions{Role: "anon", ExpiresIn: time.Minute, Now: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("Mint failed: %v", err)
+	}
+
+	_, err = Verify(signed, testSecret)
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expected expiry error, got %v", err)
+	}
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	if _, err := Decode("not-a-jwt"); err == nil {
+		t.Error("expected error for malformed token")
+	}
+}
+
+func TestParseClaim(t *testing.T) {
+	tests := []struct {
+		arg     string
+		key     string
+		value   interface{}
+		wantErr bool
+	}{
+		{arg: "aal=aal2", key: "aal", value: "aal2"},
+		{arg: "level=3", key: "level", value: float64(3)},
+		{arg: "admin=true", key: "admin", value: true},
+		{arg: "empty=", key: "empty", value: ""},
+		{arg: "url=https://x.dev/?a=b", key: "url", value: "https://x.dev/?a=b"},
+		{arg: "novalue", wantErr: true},
+		{arg: "=value", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		key, value, err := ParseClaim(tt.arg)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseClaim(%q) expected error", tt.arg)
+			}
+			continue
+		}
+		if err != nil || key != tt.key || value != tt.value {
+			t.Errorf("ParseClaim(%q) = %q, %v, %v; want %q, %v", tt.arg, key, value, err, tt.key, tt.value)
+		}
+	}
+
+	_, value, _ := ParseClaim(`app_metadata={"tier":"pro"}`)
+	if m, ok := value.(map[string]interface{}); !ok || m["tier"] != "pro" {
+		t.Errorf("expected JSON object claim, got %#v", value)
+	}
+}
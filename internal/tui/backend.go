@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/provider"
+)
+
+// Health values shown in the dashboard
+const (
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+	HealthUnknown   = "-"
+)
+
+// Backend performs the dashboard's side effects through the providers of its contexts
+type Backend struct {
+	// Contexts are listed in this order
+	Contexts []string
+
+	// Open returns the provider of a context
+	Open func(context string) (provider.InstanceProvider, error)
+
+	// Timeout bounds listing the instances of one context
+	Timeout time.Duration
+
+	// Health probes a running instance; nil skips health checks
+	Health func(instance provider.Instance) string
+
+	// OpenURL opens a URL in the browser
+	OpenURL func(url string) error
+
+	// Audit records a completed lifecycle action; nil skips auditing
+	Audit func(row Row, action string, err error, duration time.Duration)
+}
+
+// refresh lists the instances of every context and checks the health of the running ones
+func (b *Backend) refresh() Msg {
+	results := provider.ListAcrossContexts(b.Contexts, b.Open, b.Timeout)
+
+	msg := RefreshedMsg{}
+	for _, result := range results {
+		if result.Err != nil {
+			msg.Errors = append(msg.Errors, fmt.Sprintf("%s: %v", result.Context, result.Err))
+			continue
+		}
+
+		instances := result.Instances
+		sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+		for _, instance := range instances {
+			msg.Rows = append(msg.Rows, Row{Context: result.Context, Instance: instance, Health: HealthUnknown})
+		}
+	}
+
+	if b.Health != nil {
+		var wg sync.WaitGroup
+		for i := range msg.Rows {
+			if msg.Rows[i].Instance.Status != "running" {
+				continue
+			}
+			wg.Add(1)
+			go func(row *Row) {
+				defer wg.Done()
+				row.Health = b.Health(row.Instance)
+			}(&msg.Rows[i])
+		}
+		wg.Wait()
+	}
+
+	return msg
+}
+
+// action returns a command that runs a lifecycle action on an instance
+func (b *Backend) action(row Row, action string) Cmd {
+	return func() Msg {
+		started := time.Now()
+		err := b.runAction(row, action)
+		if b.Audit != nil {
+			b.Audit(row, action, err, time.Since(started))
+		}
+		return ActionDoneMsg{Action: action, Ref: row.Ref(), Err: err}
+	}
+}
+
+// runAction calls the provider method of a lifecycle action
+func (b *Backend) runAction(row Row, action string) error {
+	p, err := b.Open(row.Context)
+	if err != nil {
+		return err
+	}
+
+	name := row.Instance.Name
+	switch action {
+	case ActionStart:
+		return p.StartInstance(name)
+	case ActionStop:
+		return p.StopInstance(name)
+	case ActionRestart:
+		return p.RestartInstance(name)
+	case ActionDelete:
+		return p.DeleteInstance(name)
+	}
+	return fmt.Errorf("unknown action '%s'", action)
+}
+
+// logs returns a command that fetches the most recent logs of an instance
+func (b *Backend) logs(row Row, lines int) Cmd {
+	return func() Msg {
+		p, err := b.Open(row.Context)
+		if err != nil {
+			return LogsMsg{Ref: row.Ref(), Err: err}
+		}
+
+		logs, err := p.GetLogs(row.Instance.Name, lines)
+		return LogsMsg{Ref: row.Ref(), Logs: logs, Err: err}
+	}
+}
+
+// openStudio returns a command that opens an instance's Studio in the browser
+func (b *Backend) openStudio(row Row) Cmd {
+	return func() Msg {
+		url := row.Instance.StudioURL
+		if url == "" {
+			return ActionDoneMsg{Action: ActionOpen, Ref: row.Ref(), Err: fmt.Errorf("instance has no Studio URL")}
+		}
+		if b.OpenURL == nil {
+			return ActionDoneMsg{Action: ActionOpen, Ref: row.Ref(), Err: fmt.Errorf("cannot open %s", url)}
+		}
+		return ActionDoneMsg{Action: ActionOpen, Ref: row.Ref(), Err: b.OpenURL(url)}
+	}
+}
+
+// CheckHealth reports whether an instance's API gateway answers without a server error
+func CheckHealth(instance provider.Instance) string {
+	url := instance.GatewayURL()
+	if url == "" {
+		return HealthUnknown
+	}
+
+	client := &http.Client{Timeout: 3 * time.Second}
+	resp, err := client.Get(url + "/rest/v1/")
+	if err != nil {
+		return HealthUnhealthy
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return HealthUnhealthy
+	}
+	return HealthHealthy
+}
+
+// OpenBrowser opens a URL with the platform's default handler
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+
+	// Browser output would corrupt the dashboard
+	cmd.Stdout, cmd.Stderr = nil, nil
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", url, err)
+	}
+	go cmd.Wait()
+	return nil
+}
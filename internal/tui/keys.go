@@ -0,0 +1,70 @@
+package tui
+
+// escapeKeys maps the escape sequences terminals send for special keys
+var escapeKeys = map[string]string{
+	"\x1b[A":  "up",
+	"\x1b[B":  "down",
+	"\x1b[C":  "right",
+	"\x1b[D":  "left",
+	"\x1bOA":  "up",
+	"\x1bOB":  "down",
+	"\x1b[H":  "home",
+	"\x1b[F":  "end",
+	"\x1b[1~": "home",
+	"\x1b[4~": "end",
+	"\x1b[5~": "pgup",
+	"\x1b[6~": "pgdown",
+}
+
+// ParseKeys decodes the bytes read from a terminal in raw mode into key names.
+// Unknown escape sequences are dropped.
+func ParseKeys(data []byte) []string {
+	var keys []string
+
+	for i := 0; i < len(data); {
+		b := data[i]
+
+		if b == 0x1b {
+			if i+1 == len(data) {
+				keys = append(keys, "esc")
+				break
+			}
+			if data[i+1] == '[' || data[i+1] == 'O' {
+				// A sequence ends with its first byte in the range @ to ~
+				j := i + 2
+				for j < len(data) && (data[j] < 0x40 || data[j] > 0x7e) {
+					j++
+				}
+				if j < len(data) {
+					j++
+				}
+				if key, ok := escapeKeys[string(data[i:j])]; ok {
+					keys = append(keys, key)
+				}
+				i = j
+				continue
+			}
+			keys = append(keys, "esc")
+			i++
+			continue
+		}
+
+		switch {
+		case b == '\r' || b == '\n':
+			keys = append(keys, "enter")
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, "backspace")
+		case b == '\t':
+			keys = append(keys, "tab")
+		case b < 0x20:
+			keys = append(keys, "ctrl+"+string(rune('a'+b-1)))
+		case b >= 0x80:
+			// Non-ASCII input has no bindings
+		default:
+			keys = append(keys, string(rune(b)))
+		}
+		i++
+	}
+
+	return keys
+}
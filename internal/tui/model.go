@@ -0,0 +1,384 @@
+// Package tui implements 'supactl ui', a full-screen dashboard of the instances of
+// several contexts. It follows the Elm architecture: Model.Update turns messages
+// (key presses, provider results) into a new model and commands, and Model.View
+// renders the model. Only Run touches the terminal, so the model is tested directly.
+package tui
+
+import (
+	"fmt"
+
+	"github.com/qubitquilt/supactl/internal/provider"
+)
+
+// Lifecycle actions triggered from the dashboard
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+	ActionDelete  = "delete"
+	ActionOpen    = "open"
+)
+
+// logLines is the number of log lines fetched for the logs pane
+const logLines = 200
+
+// Row is an instance shown in the dashboard
+type Row struct {
+	Context  string
+	Instance provider.Instance
+	Health   string
+}
+
+// Ref returns the context/instance reference of the row
+func (r Row) Ref() string {
+	return r.Context + "/" + r.Instance.Name
+}
+
+// Msg is an event handled by Model.Update
+type Msg interface{}
+
+// KeyMsg is a key press, named like "up", "enter", "esc", "ctrl+c" or the typed character
+type KeyMsg struct {
+	Key string
+}
+
+// TickMsg triggers a periodic refresh
+type TickMsg struct{}
+
+// ResizeMsg reports the terminal size
+type ResizeMsg struct {
+	Width, Height int
+}
+
+// RefreshedMsg carries the instances of every context; Errors lists the contexts that failed
+type RefreshedMsg struct {
+	Rows   []Row
+	Errors []string
+}
+
+// ActionDoneMsg reports the result of an action on an instance
+type ActionDoneMsg struct {
+	Action string
+	Ref    string
+	Err    error
+}
+
+// LogsMsg carries the logs of an instance for the logs pane
+type LogsMsg struct {
+	Ref  string
+	Logs string
+	Err  error
+}
+
+// Cmd performs a side effect, such as a provider call, and returns its result as a message
+type Cmd func() Msg
+
+// batchMsg runs several commands concurrently
+type batchMsg []Cmd
+
+// Batch combines commands; nil commands are dropped
+func Batch(cmds ...Cmd) Cmd {
+	var batch batchMsg
+	for _, cmd := range cmds {
+		if cmd != nil {
+			batch = append(batch, cmd)
+		}
+	}
+	switch len(batch) {
+	case 0:
+		return nil
+	case 1:
+		return batch[0]
+	}
+	return func() Msg { return batch }
+}
+
+// Model is the state of the dashboard
+type Model struct {
+	backend *Backend
+
+	rows          []Row
+	contextErrors []string
+	cursor        int
+	loading       bool
+
+	// pending holds the action in flight per instance reference
+	pending map[string]string
+
+	// confirmDelete is the reference of the instance awaiting delete confirmation
+	confirmDelete string
+
+	// logsRef is the instance shown in the logs pane, empty when it is closed
+	logsRef string
+	logs    string
+
+	status   string
+	width    int
+	height   int
+	quitting bool
+}
+
+// New creates the dashboard model
+func New(backend *Backend) Model {
+	return Model{
+		backend: backend,
+		pending: make(map[string]string),
+		loading: true,
+		width:   80,
+		height:  24,
+	}
+}
+
+// Init returns the command that loads the instances
+func (m Model) Init() Cmd {
+	return m.backend.refresh
+}
+
+// Rows returns the instances shown
+func (m Model) Rows() []Row {
+	return m.rows
+}
+
+// Selected returns the instance under the cursor
+func (m Model) Selected() (Row, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return Row{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// Status returns the message shown in the status line
+func (m Model) Status() string {
+	return m.status
+}
+
+// LogsOpen reports whether the logs pane is shown
+func (m Model) LogsOpen() bool {
+	return m.logsRef != ""
+}
+
+// Quitting reports whether the user asked to quit
+func (m Model) Quitting() bool {
+	return m.quitting
+}
+
+// Update handles a message and returns the new model and the command to run next, if any
+func (m Model) Update(msg Msg) (Model, Cmd) {
+	switch msg := msg.(type) {
+	case KeyMsg:
+		return m.handleKey(msg.Key)
+
+	case ResizeMsg:
+		// Some terminals do not report a size; keep the default then
+		if msg.Width > 0 && msg.Height > 0 {
+			m.width, m.height = msg.Width, msg.Height
+		}
+		return m, nil
+
+	case TickMsg:
+		var cmds []Cmd
+		if !m.loading {
+			m.loading = true
+			cmds = append(cmds, m.backend.refresh)
+		}
+		if row, ok := m.rowByRef(m.logsRef); ok {
+			cmds = append(cmds, m.backend.logs(row, logLines))
+		}
+		return m, Batch(cmds...)
+
+	case RefreshedMsg:
+		m.loading = false
+		m.contextErrors = msg.Errors
+		m.setRows(msg.Rows)
+		return m, nil
+
+	case ActionDoneMsg:
+		m.pending = copyPending(m.pending)
+		delete(m.pending, msg.Ref)
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Failed to %s %s: %v", msg.Action, msg.Ref, msg.Err)
+			return m, nil
+		}
+		if msg.Action == ActionOpen {
+			m.status = fmt.Sprintf("Opened Studio of %s", msg.Ref)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("%s %s", doneStatus[msg.Action], msg.Ref)
+		if msg.Action == ActionDelete && m.logsRef == msg.Ref {
+			m.logsRef, m.logs = "", ""
+		}
+		m.loading = true
+		return m, m.backend.refresh
+
+	case LogsMsg:
+		if msg.Ref != m.logsRef {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.logs = fmt.Sprintf("Failed to fetch logs: %v", msg.Err)
+		} else {
+			m.logs = msg.Logs
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleKey applies a key binding
+func (m Model) handleKey(key string) (Model, Cmd) {
+	if m.confirmDelete != "" {
+		ref := m.confirmDelete
+		m.confirmDelete = ""
+		row, ok := m.rowByRef(ref)
+		if key != "y" || !ok {
+			m.status = "Delete cancelled"
+			return m, nil
+		}
+		return m.startAction(row, ActionDelete)
+	}
+
+	switch key {
+	case "q", "ctrl+c":
+		m.quitting = true
+		return m, nil
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		if len(m.rows) > 0 {
+			m.cursor = len(m.rows) - 1
+		}
+
+	case "esc":
+		m.logsRef, m.logs = "", ""
+
+	case "R", "ctrl+r":
+		if m.loading {
+			return m, nil
+		}
+		m.loading = true
+		return m, m.backend.refresh
+
+	case "s", "x", "r", "d", "l", "o", "enter":
+		row, ok := m.Selected()
+		if !ok {
+			return m, nil
+		}
+		switch key {
+		case "s":
+			return m.startAction(row, ActionStart)
+		case "x":
+			return m.startAction(row, ActionStop)
+		case "r":
+			return m.startAction(row, ActionRestart)
+		case "d":
+			if _, busy := m.pending[row.Ref()]; busy {
+				return m, nil
+			}
+			m.confirmDelete = row.Ref()
+			m.status = fmt.Sprintf("Delete %s and all its data? (y/N)", row.Ref())
+		case "l", "enter":
+			if m.logsRef == row.Ref() {
+				m.logsRef, m.logs = "", ""
+				return m, nil
+			}
+			m.logsRef, m.logs = row.Ref(), "Loading logs..."
+			return m, m.backend.logs(row, logLines)
+		case "o":
+			return m, m.backend.openStudio(row)
+		}
+	}
+
+	return m, nil
+}
+
+// startAction marks an action as in flight and returns the command running it.
+// An instance runs one action at a time.
+func (m Model) startAction(row Row, action string) (Model, Cmd) {
+	if current, busy := m.pending[row.Ref()]; busy {
+		m.status = fmt.Sprintf("%s: %s in progress", row.Ref(), current)
+		return m, nil
+	}
+
+	m.pending = copyPending(m.pending)
+	m.pending[row.Ref()] = action
+
+	m.status = fmt.Sprintf("%s: %s...", row.Ref(), action)
+	return m, m.backend.action(row, action)
+}
+
+// copyPending copies the actions in flight, so earlier models are not changed
+func copyPending(pending map[string]string) map[string]string {
+	c := make(map[string]string, len(pending)+1)
+	for ref, action := range pending {
+		c[ref] = action
+	}
+	return c
+}
+
+// setRows replaces the rows, keeping the cursor on the same instance if it still exists
+func (m *Model) setRows(rows []Row) {
+	selected, hadSelection := m.Selected()
+	m.rows = rows
+
+	if hadSelection {
+		for i, row := range rows {
+			if row.Ref() == selected.Ref() {
+				m.cursor = i
+				return
+			}
+		}
+	}
+	if m.cursor >= len(rows) {
+		m.cursor = len(rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// rowByRef finds a row by its reference
+func (m Model) rowByRef(ref string) (Row, bool) {
+	if ref == "" {
+		return Row{}, false
+	}
+	for _, row := range m.rows {
+		if row.Ref() == ref {
+			return row, true
+		}
+	}
+	return Row{}, false
+}
+
+// pendingStatus is the status shown while an action is in flight
+var pendingStatus = map[string]string{
+	ActionStart:   "starting...",
+	ActionStop:    "stopping...",
+	ActionRestart: "restarting...",
+	ActionDelete:  "deleting...",
+}
+
+// doneStatus describes a completed action
+var doneStatus = map[string]string{
+	ActionStart:   "Started",
+	ActionStop:    "Stopped",
+	ActionRestart: "Restarted",
+	ActionDelete:  "Deleted",
+}
+
+// statusOf returns the status shown for a row, which is the action in flight if any
+func (m Model) statusOf(row Row) string {
+	if action, ok := m.pending[row.Ref()]; ok {
+		return pendingStatus[action]
+	}
+	return row.Instance.Status
+}
@@ -0,0 +1,358 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qubitquilt/supactl/internal/provider"
+)
+
+// fakeProvider is an in-memory InstanceProvider that records the calls it receives
+type fakeProvider struct {
+	mu        sync.Mutex
+	instances map[string]*provider.Instance
+	calls     []string
+	failStart error
+}
+
+func newFakeProvider(names ...string) *fakeProvider {
+	p := &fakeProvider{instances: make(map[string]*provider.Instance)}
+	for _, name := range names {
+		p.instances[name] = &provider.Instance{Name: name, Status: "stopped", StudioURL: "http://studio/" + name}
+	}
+	return p
+}
+
+func (p *fakeProvider) record(call string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, call)
+}
+
+func (p *fakeProvider) setStatus(name, status string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	inst, ok := p.instances[name]
+	if !ok {
+		return fmt.Errorf("instance '%s' not found", name)
+	}
+	inst.Status = status
+	return nil
+}
+
+func (p *fakeProvider) ListInstances() ([]provider.Instance, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var instances []provider.Instance
+	for _, inst := range p.instances {
+		instances = append(instances, *inst)
+	}
+	return instances, nil
+}
+
+func (p *fakeProvider) GetInstance(name string) (*provider.Instance, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if inst, ok := p.instances[name]; ok {
+		c := *inst
+		return &c, nil
+	}
+	return nil, fmt.Errorf("instance '%s' not found", name)
+}
+
+func (p *fakeProvider) CreateInstance(name string) (*provider.Instance, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *fakeProvider) DeleteInstance(name string) error {
+	p.record("delete " + name)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.instances, name)
+	return nil
+}
+
+func (p *fakeProvider) StartInstance(name string) error {
+	p.record("start " + name)
+	if p.failStart != nil {
+		return p.failStart
+	}
+	return p.setStatus(name, "running")
+}
+
+func (p *fakeProvider) StopInstance(name string) error {
+	p.record("stop " + name)
+	return p.setStatus(name, "stopped")
+}
+
+func (p *fakeProvider) RestartInstance(name string) error {
+	p.record("restart " + name)
+	return p.setStatus(name, "running")
+}
+
+func (p *fakeProvider) GetLogs(name string, lines int) (string, error) {
+	p.record(fmt.Sprintf("logs %s %d", name, lines))
+	return "line 1\nline 2\n", nil
+}
+
+func (p *fakeProvider) ProviderType() string {
+	return provider.ProviderTypeLocal
+}
+
+// newTestBackend serves the given providers by context name; other contexts fail to open
+func newTestBackend(providers map[string]*fakeProvider, contexts ...string) *Backend {
+	return &Backend{
+		Contexts: contexts,
+		Open: func(context string) (provider.InstanceProvider, error) {
+			if p, ok := providers[context]; ok {
+				return p, nil
+			}
+			return nil, fmt.Errorf("context '%s' is unreachable", context)
+		},
+		Timeout: time.Second,
+		Health: func(instance provider.Instance) string {
+			return HealthHealthy
+		},
+	}
+}
+
+// drive runs a command and every command that follows from it synchronously, like Run
+// does in the background, and returns the final model
+func drive(t *testing.T, m Model, cmd Cmd) Model {
+	t.Helper()
+	queue := []Cmd{cmd}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if next == nil {
+			continue
+		}
+
+		msg := next()
+		if batch, ok := msg.(batchMsg); ok {
+			queue = append(queue, batch...)
+			continue
+		}
+
+		var follow Cmd
+		m, follow = m.Update(msg)
+		queue = append(queue, follow)
+	}
+	return m
+}
+
+// press sends keys to the model, running the resulting commands
+func press(t *testing.T, m Model, keys ...string) Model {
+	t.Helper()
+	for _, key := range keys {
+		var cmd Cmd
+		m, cmd = m.Update(KeyMsg{Key: key})
+		m = drive(t, m, cmd)
+	}
+	return m
+}
+
+func refs(rows []Row) []string {
+	var result []string
+	for _, row := range rows {
+		result = append(result, row.Ref())
+	}
+	return result
+}
+
+func TestModel_Refresh(t *testing.T) {
+	local := newFakeProvider("beta", "alpha")
+	local.instances["beta"].Status = "running"
+	backend := newTestBackend(map[string]*fakeProvider{"local": local}, "local", "prod")
+
+	m := New(backend)
+	m = drive(t, m, m.Init())
+
+	if got, want := refs(m.Rows()), []string{"local/alpha", "local/beta"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("rows = %v, want %v", got, want)
+	}
+	if m.Rows()[0].Health != HealthUnknown || m.Rows()[1].Health != HealthHealthy {
+		t.Errorf("only running instances should be health checked: %+v", m.Rows())
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "prod: context 'prod' is unreachable") {
+		t.Errorf("view should report the unreachable context:\n%s", view)
+	}
+	if !strings.Contains(view, "http://studio/alpha") {
+		t.Errorf("view should list the instances:\n%s", view)
+	}
+}
+
+func TestModel_Navigation(t *testing.T) {
+	backend := newTestBackend(map[string]*fakeProvider{"local": newFakeProvider("a", "b", "c")}, "local")
+	m := New(backend)
+	m = drive(t, m, m.Init())
+
+	m = press(t, m, "down", "j", "down")
+	if row, _ := m.Selected(); row.Instance.Name != "c" {
+		t.Errorf("cursor should stop at the last row, selected %s", row.Ref())
+	}
+	m = press(t, m, "up", "g")
+	if row, _ := m.Selected(); row.Instance.Name != "a" {
+		t.Errorf("g should select the first row, selected %s", row.Ref())
+	}
+
+	// The cursor follows its instance when rows are added before it
+	m = press(t, m, "G")
+	m, _ = m.Update(RefreshedMsg{Rows: append([]Row{{Context: "local", Instance: provider.Instance{Name: "0"}}}, m.Rows()...)})
+	if row, _ := m.Selected(); row.Instance.Name != "c" {
+		t.Errorf("cursor should stay on c after a refresh, selected %s", row.Ref())
+	}
+
+	m = press(t, m, "q")
+	if !m.Quitting() {
+		t.Error("q should quit")
+	}
+}
+
+func TestModel_Actions(t *testing.T) {
+	local := newFakeProvider("app")
+	backend := newTestBackend(map[string]*fakeProvider{"local": local}, "local")
+	m := New(backend)
+	m = drive(t, m, m.Init())
+
+	// The pending action is shown until the provider answers
+	m, cmd := m.Update(KeyMsg{Key: "s"})
+	if !strings.Contains(m.View(), "starting...") {
+		t.Errorf("view should show the action in flight:\n%s", m.View())
+	}
+	if again, second := m.Update(KeyMsg{Key: "x"}); second != nil || !strings.Contains(again.Status(), "in progress") {
+		t.Error("a second action should be refused while one is in flight")
+	}
+	m = drive(t, m, cmd)
+
+	if m.Status() != "Started local/app" || m.Rows()[0].Instance.Status != "running" {
+		t.Errorf("status = %q, rows = %+v", m.Status(), m.Rows())
+	}
+
+	m = press(t, m, "r", "x")
+	if want := []string{"start app", "restart app", "stop app"}; !reflect.DeepEqual(local.calls, want) {
+		t.Errorf("calls = %v, want %v", local.calls, want)
+	}
+
+	local.failStart = errors.New("port in use")
+	m = press(t, m, "s")
+	if m.Status() != "Failed to start local/app: port in use" {
+		t.Errorf("status = %q", m.Status())
+	}
+}
+
+func TestModel_Delete(t *testing.T) {
+	local := newFakeProvider("app", "other")
+	backend := newTestBackend(map[string]*fakeProvider{"local": local}, "local")
+	var audited []string
+	backend.Audit = func(row Row, action string, err error, duration time.Duration) {
+		audited = append(audited, fmt.Sprintf("%s %s %v", action, row.Ref(), err))
+	}
+	m := New(backend)
+	m = drive(t, m, m.Init())
+
+	m = press(t, m, "d", "n")
+	if m.Status() != "Delete cancelled" || len(local.calls) != 0 {
+		t.Errorf("delete should be cancelled, status %q, calls %v", m.Status(), local.calls)
+	}
+
+	m = press(t, m, "d")
+	if !strings.Contains(m.Status(), "(y/N)") {
+		t.Errorf("delete should ask for confirmation, status %q", m.Status())
+	}
+	m = press(t, m, "y")
+	if got, want := refs(m.Rows()), []string{"local/other"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+	if want := []string{"delete local/app <nil>"}; !reflect.DeepEqual(audited, want) {
+		t.Errorf("audited = %v, want %v", audited, want)
+	}
+}
+
+func TestModel_Logs(t *testing.T) {
+	local := newFakeProvider("app")
+	backend := newTestBackend(map[string]*fakeProvider{"local": local}, "local")
+	m := New(backend)
+	m = drive(t, m, m.Init())
+
+	m = press(t, m, "l")
+	if !m.LogsOpen() || !strings.Contains(m.View(), "line 2") {
+		t.Errorf("logs pane should show the logs:\n%s", m.View())
+	}
+
+	// Ticks tail the logs while the pane is open
+	m, cmd := m.Update(TickMsg{})
+	m = drive(t, m, cmd)
+	if want := []string{"logs app 200", "logs app 200"}; !reflect.DeepEqual(local.calls, want) {
+		t.Errorf("calls = %v, want %v", local.calls, want)
+	}
+
+	m = press(t, m, "esc")
+	if m.LogsOpen() {
+		t.Error("esc should close the logs pane")
+	}
+	_, cmd = m.Update(TickMsg{})
+	m = drive(t, m, cmd)
+	if len(local.calls) != 2 {
+		t.Errorf("logs should not be fetched with the pane closed, calls %v", local.calls)
+	}
+}
+
+func TestModel_OpenStudio(t *testing.T) {
+	backend := newTestBackend(map[string]*fakeProvider{"local": newFakeProvider("app")}, "local")
+	var opened []string
+	backend.OpenURL = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+
+	m := New(backend)
+	m = drive(t, m, m.Init())
+	m = press(t, m, "o")
+
+	if !reflect.DeepEqual(opened, []string{"http://studio/app"}) || m.Status() != "Opened Studio of local/app" {
+		t.Errorf("opened = %v, status = %q", opened, m.Status())
+	}
+}
+
+func TestView_FitsTerminal(t *testing.T) {
+	backend := newTestBackend(map[string]*fakeProvider{"local": newFakeProvider("a", "b", "c", "d", "e", "f", "g", "h")}, "local")
+	m := New(backend)
+	m = drive(t, m, m.Init())
+	m, _ = m.Update(ResizeMsg{Width: 40, Height: 8})
+	m = press(t, m, "G", "l")
+
+	lines := strings.Split(m.View(), "\n")
+	if len(lines) > 8 {
+		t.Errorf("view has %d lines, want at most 8", len(lines))
+	}
+	if !strings.Contains(m.View(), "local/h") {
+		t.Errorf("selected row should stay visible:\n%s", m.View())
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := map[string][]string{
+		"q":            {"q"},
+		"jk":           {"j", "k"},
+		"\x1b[A\x1b[B": {"up", "down"},
+		"\x1b":         {"esc"},
+		"\r":           {"enter"},
+		"\x03":         {"ctrl+c"},
+		"\x1b[5~G":     {"pgup", "G"},
+		"\x1b[99zq":    {"q"},
+	}
+
+	for input, want := range tests {
+		if got := ParseKeys([]byte(input)); !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseKeys(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Terminal control sequences
+const (
+	enterAltScreen = "\033[?1049h\033[?25l"
+	exitAltScreen  = "\033[?25h\033[?1049l"
+	cursorHome     = "\033[H"
+	clearLine      = "\033[K"
+	clearBelow     = "\033[J"
+)
+
+// Run shows the dashboard on the terminal until the user quits. Instances are
+// refreshed every interval, and the logs pane is tailed at the same rate.
+func Run(backend *Backend, interval time.Duration) error {
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return fmt.Errorf("the dashboard needs an interactive terminal")
+	}
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return fmt.Errorf("failed to configure terminal: %w", err)
+	}
+	defer term.Restore(in, state)
+
+	fmt.Fprint(os.Stdout, enterAltScreen)
+	defer fmt.Fprint(os.Stdout, exitAltScreen)
+
+	msgs := make(chan Msg, 64)
+	go readKeys(os.Stdin, msgs)
+
+	run := func(cmd Cmd) { go runCmd(cmd, msgs) }
+
+	m := New(backend)
+	width, height, _ := term.GetSize(out)
+	m, _ = m.Update(ResizeMsg{Width: width, Height: height})
+	run(m.Init())
+	render(os.Stdout, m)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var msg Msg
+		select {
+		case msg = <-msgs:
+		case <-ticker.C:
+			msg = TickMsg{}
+		}
+
+		// Terminals report resizes with SIGWINCH, which is not portable; polling the size is
+		if w, h, err := term.GetSize(out); err == nil && (w != width || h != height) {
+			width, height = w, h
+			m, _ = m.Update(ResizeMsg{Width: width, Height: height})
+		}
+
+		var cmd Cmd
+		m, cmd = m.Update(msg)
+		if m.Quitting() {
+			return nil
+		}
+		if cmd != nil {
+			run(cmd)
+		}
+		render(os.Stdout, m)
+	}
+}
+
+// runCmd runs a command and delivers its result; batches run their commands concurrently
+func runCmd(cmd Cmd, msgs chan<- Msg) {
+	if cmd == nil {
+		return
+	}
+
+	msg := cmd()
+	if batch, ok := msg.(batchMsg); ok {
+		for _, c := range batch {
+			go runCmd(c, msgs)
+		}
+		return
+	}
+	if msg != nil {
+		msgs <- msg
+	}
+}
+
+// readKeys sends the keys typed on the terminal until it is closed
+func readKeys(r io.Reader, msgs chan<- Msg) {
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		for _, key := range ParseKeys(buf[:n]) {
+			msgs <- KeyMsg{Key: key}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// render redraws the screen in place. Raw mode needs explicit carriage returns.
+func render(w io.Writer, m Model) {
+	frame := strings.ReplaceAll(m.View(), "\n", clearLine+"\r\n")
+	fmt.Fprint(w, cursorHome+frame+clearLine+clearBelow)
+}
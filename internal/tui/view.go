@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// ANSI sequences used by the view
+const (
+	styleReverse = "\033[7m"
+	styleBold    = "\033[1m"
+	styleDim     = "\033[2m"
+	styleReset   = "\033[0m"
+)
+
+// helpLine lists the key bindings
+const helpLine = "↑/↓ move · s start · x stop · r restart · d delete · l logs · o studio · q quit"
+
+// View renders the model as lines of at most the terminal width
+func (m Model) View() string {
+	var lines []string
+
+	title := fmt.Sprintf("supactl ui  %d instance(s)", len(m.rows))
+	if m.loading {
+		title += "  refreshing..."
+	}
+	lines = append(lines, styleBold+truncate(title, m.width)+styleReset)
+
+	table := m.tableLines()
+	header, body := table[0], table[1:]
+	lines = append(lines, styleDim+truncate("  "+header, m.width)+styleReset)
+
+	for _, e := range m.contextErrors {
+		lines = append(lines, truncate("! "+e, m.width))
+	}
+
+	// The footer has a blank line, the status line and the help line
+	available := m.height - len(lines) - 3
+	tableHeight, logsHeight := available, 0
+	if m.LogsOpen() {
+		tableHeight = available / 2
+		if tableHeight < 3 {
+			tableHeight = 3
+		}
+		logsHeight = available - tableHeight - 1
+	}
+
+	if len(m.rows) == 0 && !m.loading {
+		lines = append(lines, "  No instances found.")
+	}
+	start, end := window(m.cursor, len(body), tableHeight)
+	for i := start; i < end; i++ {
+		line := truncate("  "+body[i], m.width)
+		if i == m.cursor {
+			line = styleReverse + truncate("> "+body[i], m.width) + styleReset
+		}
+		lines = append(lines, line)
+	}
+
+	if m.LogsOpen() {
+		lines = append(lines, styleDim+truncate(fmt.Sprintf("── logs: %s (l/esc to close) ", m.logsRef)+strings.Repeat("─", m.width), m.width)+styleReset)
+		lines = append(lines, lastLines(m.logs, logsHeight, m.width)...)
+	}
+
+	for len(lines) < m.height-2 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, truncate(m.status, m.width))
+	lines = append(lines, styleDim+truncate(helpLine, m.width)+styleReset)
+
+	return strings.Join(lines, "\n")
+}
+
+// tableLines returns the aligned header and rows of the instance table
+func (m Model) tableLines() []string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tNAME\tSTATUS\tHEALTH\tSTUDIO-URL")
+	for _, row := range m.rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", row.Context, row.Instance.Name, m.statusOf(row), row.Health, row.Instance.StudioURL)
+	}
+	w.Flush()
+
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+}
+
+// window returns the range of rows to show so the cursor stays visible
+func window(cursor, total, height int) (int, int) {
+	if height <= 0 {
+		return 0, 0
+	}
+	if total <= height {
+		return 0, total
+	}
+
+	start := cursor - height/2
+	if start < 0 {
+		start = 0
+	}
+	if start+height > total {
+		start = total - height
+	}
+	return start, start + height
+}
+
+// logReplacer removes carriage returns and escape sequences that would break the layout
+var logReplacer = strings.NewReplacer("\t", "    ", "\r", "", "\033", "")
+
+// lastLines returns the last n lines of text, truncated to width
+func lastLines(text string, n, width int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, line := range lines {
+		lines[i] = truncate(logReplacer.Replace(line), width)
+	}
+	return lines
+}
+
+// truncate shortens a line to width runes
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,247 @@
+package typegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/qubitquilt/supactl/internal/pgschema"
+)
+
+// goScalars maps Postgres base types to Go types. Unknown types become interface{}.
+// Only timestamptz maps to time.Time: PostgREST returns the other date and time types
+// in formats encoding/json cannot parse into it.
+var goScalars = map[string]string{
+	"boolean":                     "bool",
+	"smallint":                    "int16",
+	"integer":                     "int32",
+	"bigint":                      "int64",
+	"real":                        "float32",
+	"double precision":            "float64",
+	"numeric":                     "float64",
+	"oid":                         "uint32",
+	"json":                        "json.RawMessage",
+	"jsonb":                       "json.RawMessage",
+	"text":                        "string",
+	"character varying":           "string",
+	"character":                   "string",
+	`"char"`:                      "string",
+	"name":                        "string",
+	"citext":                      "string",
+	"uuid":                        "string",
+	"date":                        "string",
+	"time without time zone":      "string",
+	"time with time zone":         "string",
+	"timestamp without time zone": "string",
+	"timestamp with time zone":    "time.Time",
+	"interval":                    "string",
+	"bytea":                       "string",
+	"money":                       "string",
+	"inet":                        "string",
+	"cidr":                        "string",
+	"macaddr":                     "string",
+	"tsvector":                    "string",
+	"tsquery":                     "string",
+	"xml":                         "string",
+	"bit":                         "string",
+	"bit varying":                 "string",
+}
+
+// goInitialisms are name parts written in upper case, following Go naming conventions
+var goInitialisms = map[string]bool{
+	"api": true, "db": true, "http": true, "https": true, "id": true, "ip": true,
+	"json": true, "jwt": true, "sql": true, "ui": true, "uri": true, "url": true, "uuid": true,
+}
+
+// goWriter generates one Go struct per table, view and function argument list
+type goWriter struct {
+	schema  *pgschema.Schema
+	schemas map[string]bool
+	imports map[string]bool
+	out     bytes.Buffer
+}
+
+func writeGo(w io.Writer, s *pgschema.Schema, opts Options) error {
+	g := &goWriter{schema: s, schemas: make(map[string]bool), imports: make(map[string]bool)}
+	for _, name := range opts.Schemas {
+		g.schemas[name] = true
+	}
+
+	for _, name := range opts.Schemas {
+		g.writeSchema(name, objectsIn(s, name))
+	}
+
+	var file bytes.Buffer
+	file.WriteString("// Code generated by supactl gen types. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&file, "package %s\n\n", opts.Package)
+	if len(g.imports) > 0 {
+		var imports []string
+		for path := range g.imports {
+			imports = append(imports, strconv.Quote(path))
+		}
+		sort.Strings(imports)
+		fmt.Fprintf(&file, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	file.Write(g.out.Bytes())
+
+	source, err := format.Source(file.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	_, err = w.Write(source)
+	return err
+}
+
+func (g *goWriter) writeSchema(name string, objects schemaObjects) {
+	prefix := ""
+	if name != "public" {
+		prefix = goName(name)
+	}
+
+	for _, e := range objects.Enums {
+		typeName := prefix + goName(e.Name)
+		fmt.Fprintf(&g.out, "// %s is the %s.%s enum\ntype %s string\n\n", typeName, e.Schema, e.Name, typeName)
+		if len(e.Values) > 0 {
+			g.out.WriteString("const (\n")
+			for _, v := range e.Values {
+				fmt.Fprintf(&g.out, "%s%s %s = %s\n", typeName, goName(v), typeName, strconv.Quote(v))
+			}
+			g.out.WriteString(")\n\n")
+		}
+	}
+
+	for _, t := range objects.Tables {
+		typeName := prefix + goName(t.Name)
+		columns := sortedColumns(t.Columns)
+
+		fmt.Fprintf(&g.out, "// %s is a row of the %s.%s table\ntype %s struct {\n", typeName, t.Schema, t.Name, typeName)
+		for _, c := range columns {
+			g.field(c.Name, g.typeOf(parseType(g.schema, c.Type), !c.NotNull), false)
+		}
+		g.out.WriteString("}\n\n")
+
+		fmt.Fprintf(&g.out, "// %sInsert is a row to insert into the %s.%s table\ntype %sInsert struct {\n", typeName, t.Schema, t.Name, typeName)
+		for _, c := range columns {
+			if c.Generated {
+				continue
+			}
+			optional := insertOptional(c)
+			g.field(c.Name, g.typeOf(parseType(g.schema, c.Type), optional), optional)
+		}
+		g.out.WriteString("}\n\n")
+
+		fmt.Fprintf(&g.out, "// %sUpdate holds the columns to change in the %s.%s table\ntype %sUpdate struct {\n", typeName, t.Schema, t.Name, typeName)
+		for _, c := range columns {
+			if !c.Generated {
+				g.field(c.Name, g.typeOf(parseType(g.schema, c.Type), true), true)
+			}
+		}
+		g.out.WriteString("}\n\n")
+	}
+
+	for _, v := range objects.Views {
+		typeName := prefix + goName(v.Name)
+		fmt.Fprintf(&g.out, "// %s is a row of the %s.%s view\ntype %s struct {\n", typeName, v.Schema, v.Name, typeName)
+		for _, c := range sortedColumns(v.Columns) {
+			g.field(c.Name, g.typeOf(parseType(g.schema, c.Type), !c.NotNull), false)
+		}
+		g.out.WriteString("}\n\n")
+	}
+
+	overloads := make(map[string]int)
+	for _, f := range objects.Functions {
+		typeName := prefix + goName(f.Name)
+		overloads[f.Name]++
+		if n := overloads[f.Name]; n > 1 {
+			typeName += strconv.Itoa(n)
+		}
+
+		if inputs := f.Inputs(); len(inputs) > 0 {
+			fmt.Fprintf(&g.out, "// %sArgs are the arguments of the %s function\ntype %sArgs struct {\n", typeName, f.Signature(), typeName)
+			for _, p := range inputs {
+				g.field(p.Name, g.typeOf(parseType(g.schema, p.Type), p.HasDefault), p.HasDefault)
+			}
+			g.out.WriteString("}\n\n")
+		}
+
+		if outputs := f.Outputs(); len(outputs) > 0 {
+			fmt.Fprintf(&g.out, "// %sResult is a row returned by the %s function\ntype %sResult struct {\n", typeName, f.Signature(), typeName)
+			for _, p := range outputs {
+				g.field(p.Name, g.typeOf(parseType(g.schema, p.Type), true), false)
+			}
+			g.out.WriteString("}\n\n")
+		}
+	}
+}
+
+// field writes a struct field with its JSON tag
+func (g *goWriter) field(column, typ string, omitEmpty bool) {
+	tag := column
+	if omitEmpty {
+		tag += ",omitempty"
+	}
+	fmt.Fprintf(&g.out, "%s %s `json:%s`\n", goName(column), typ, strconv.Quote(tag))
+}
+
+// typeOf maps a Postgres type to Go. Nullable scalars become pointers; slices,
+// json.RawMessage and interface{} can already hold null.
+func (g *goWriter) typeOf(t pgType, nullable bool) string {
+	var typ string
+	switch {
+	case t.Enum != nil && g.schemas[t.Enum.Schema]:
+		typ = goName(t.Enum.Name)
+		if t.Enum.Schema != "public" {
+			typ = goName(t.Enum.Schema) + typ
+		}
+	case t.Enum != nil:
+		typ = "string"
+	default:
+		scalar, ok := goScalars[t.Base]
+		if !ok {
+			scalar = "interface{}"
+		}
+		typ = scalar
+	}
+
+	if pkg, _, found := strings.Cut(typ, "."); found {
+		g.imports[map[string]string{"json": "encoding/json", "time": "time"}[pkg]] = true
+	}
+
+	if t.Dims > 0 {
+		return strings.Repeat("[]", t.Dims) + typ
+	}
+	if nullable && typ != "json.RawMessage" && typ != "interface{}" {
+		return "*" + typ
+	}
+	return typ
+}
+
+// goName converts a Postgres identifier to an exported Go name, e.g. user_id -> UserID
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, part := range parts {
+		if goInitialisms[strings.ToLower(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	result := b.String()
+	if result == "" || !unicode.IsLetter([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
@@ -0,0 +1,161 @@
+package typegen
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/qubitquilt/supactl/internal/pgschema"
+)
+
+// Supported output languages
+const (
+	LangTypeScript = "typescript"
+	LangGo         = "go"
+)
+
+// Options controls code generation
+type Options struct {
+	// Lang is LangTypeScript or LangGo
+	Lang string
+
+	// Schemas are the Postgres schemas to generate types for, in output order
+	Schemas []string
+
+	// Package is the Go package name (Go only)
+	Package string
+}
+
+// Generate writes the types of the tables, views, enums and functions of the selected schemas
+func Generate(w io.Writer, s *pgschema.Schema, opts Options) error {
+	if len(opts.Schemas) == 0 {
+		opts.Schemas = []string{"public"}
+	}
+
+	switch opts.Lang {
+	case LangTypeScript, "ts":
+		return writeTypeScript(w, s, opts)
+	case LangGo:
+		if opts.Package == "" {
+			opts.Package = "database"
+		}
+		return writeGo(w, s, opts)
+	default:
+		return fmt.Errorf("unsupported language '%s' (expected '%s' or '%s')", opts.Lang, LangTypeScript, LangGo)
+	}
+}
+
+// pgType is a column or parameter type reduced to its base name and array dimensions
+type pgType struct {
+	Base string
+	Dims int
+
+	// Enum is set when the base type is one of the introspected enums
+	Enum *pgschema.Enum
+}
+
+// typeModifiers matches length and precision modifiers such as (255) or (10,2)
+var typeModifiers = regexp.MustCompile(`\(\d+(,\s*\d+)?\)`)
+
+// parseType parses a type as printed by format_type, e.g. "character varying(20)[]"
+func parseType(s *pgschema.Schema, typ string) pgType {
+	t := pgType{Base: strings.TrimSpace(typ)}
+	for strings.HasSuffix(t.Base, "[]") {
+		t.Base = strings.TrimSuffix(t.Base, "[]")
+		t.Dims++
+	}
+	t.Base = strings.Join(strings.Fields(typeModifiers.ReplaceAllString(t.Base, "")), " ")
+	t.Enum = findEnum(s, t.Base)
+	return t
+}
+
+// findEnum resolves a type name to an enum. format_type only qualifies names
+// that are not on the search path, so unqualified names are looked up in public first.
+func findEnum(s *pgschema.Schema, name string) *pgschema.Enum {
+	schema, typeName, qualified := strings.Cut(name, ".")
+	if !qualified {
+		schema, typeName = "public", name
+	}
+	schema, typeName = unquote(schema), unquote(typeName)
+
+	var fallback *pgschema.Enum
+	for _, e := range s.Enums {
+		if e.Name != typeName {
+			continue
+		}
+		if e.Schema == schema {
+			return e
+		}
+		if !qualified && (fallback == nil || e.Schema < fallback.Schema) {
+			fallback = e
+		}
+	}
+	return fallback
+}
+
+func unquote(name string) string {
+	if len(name) >= 2 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		return strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+	}
+	return name
+}
+
+// schemaObjects are the objects of one schema, sorted by name
+type schemaObjects struct {
+	Tables    []*pgschema.Table
+	Views     []*pgschema.View
+	Enums     []*pgschema.Enum
+	Functions []*pgschema.Function
+}
+
+// objectsIn collects the objects of a schema. Procedures and trigger functions
+// cannot be called through the API and are skipped.
+func objectsIn(s *pgschema.Schema, schema string) schemaObjects {
+	var objects schemaObjects
+	for _, t := range s.Tables {
+		if t.Schema == schema {
+			objects.Tables = append(objects.Tables, t)
+		}
+	}
+	for _, v := range s.Views {
+		if v.Schema == schema {
+			objects.Views = append(objects.Views, v)
+		}
+	}
+	for _, e := range s.Enums {
+		if e.Schema == schema {
+			objects.Enums = append(objects.Enums, e)
+		}
+	}
+	for _, f := range s.Functions {
+		if f.Schema == schema && !f.Procedure && f.Returns != "trigger" && f.Returns != "event_trigger" {
+			objects.Functions = append(objects.Functions, f)
+		}
+	}
+
+	sort.Slice(objects.Tables, func(i, j int) bool { return objects.Tables[i].Name < objects.Tables[j].Name })
+	sort.Slice(objects.Views, func(i, j int) bool { return objects.Views[i].Name < objects.Views[j].Name })
+	sort.Slice(objects.Enums, func(i, j int) bool { return objects.Enums[i].Name < objects.Enums[j].Name })
+	sort.Slice(objects.Functions, func(i, j int) bool {
+		if objects.Functions[i].Name != objects.Functions[j].Name {
+			return objects.Functions[i].Name < objects.Functions[j].Name
+		}
+		return objects.Functions[i].Arguments < objects.Functions[j].Arguments
+	})
+
+	return objects
+}
+
+// sortedColumns returns columns in table order
+func sortedColumns(columns []pgschema.Column) []pgschema.Column {
+	sorted := append([]pgschema.Column(nil), columns...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Position < sorted[j].Position })
+	return sorted
+}
+
+// insertOptional reports whether a column may be omitted on insert
+func insertOptional(c pgschema.Column) bool {
+	return !c.NotNull || c.Default != "" || c.Identity || c.Generated
+}
@@ -0,0 +1,115 @@
+package typegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/qubitquilt/supactl/internal/pgschema"
+)
+
+// update rewrites the golden files: go test ./internal/typegen -update
+var update = flag.Bool("update", false, "update golden files")
+
+func loadFixture(t *testing.T) *pgschema.Schema {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "schema.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	s := pgschema.NewSchema()
+	if err := json.Unmarshal(data, s); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	return s
+}
+
+func TestGenerate_Golden(t *testing.T) {
+	tests := []struct {
+		golden string
+		opts   Options
+	}{
+		{"types.ts.golden", Options{Lang: LangTypeScript, Schemas: []string{"public", "billing"}}},
+		{"types.go.golden", Options{Lang: LangGo, Schemas: []string{"public", "billing"}, Package: "database"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Generate(&out, loadFixture(t), tt.opts); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+					t.Fatalf("failed to write golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			if out.String() != string(want) {
+				t.Errorf("output does not match %s (run with -update to accept changes):\n%s", path, out.String())
+			}
+		})
+	}
+}
+
+func TestGenerate_UnknownLanguage(t *testing.T) {
+	err := Generate(&bytes.Buffer{}, pgschema.NewSchema(), Options{Lang: "rust"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported language") {
+		t.Errorf("expected unsupported language error, got %v", err)
+	}
+}
+
+func TestParseType(t *testing.T) {
+	s := loadFixture(t)
+
+	tests := []struct {
+		typ      string
+		wantBase string
+		wantDims int
+		wantEnum string
+	}{
+		{"character varying(255)", "character varying", 0, ""},
+		{"timestamp(3) with time zone", "timestamp with time zone", 0, ""},
+		{"numeric(10,2)[]", "numeric", 1, ""},
+		{"task_status[][]", "task_status", 2, "task_status"},
+		{"billing.invoice_status", "billing.invoice_status", 0, "invoice_status"},
+		{`public."task_status"`, `public."task_status"`, 0, "task_status"},
+	}
+
+	for _, tt := range tests {
+		got := parseType(s, tt.typ)
+		enum := ""
+		if got.Enum != nil {
+			enum = got.Enum.Name
+		}
+		if got.Base != tt.wantBase || got.Dims != tt.wantDims || enum != tt.wantEnum {
+			t.Errorf("parseType(%q) = %q, %d, enum %q; want %q, %d, enum %q", tt.typ, got.Base, got.Dims, enum, tt.wantBase, tt.wantDims, tt.wantEnum)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"user_id":      "UserID",
+		"Display Name": "DisplayName",
+		"avatar_url":   "AvatarURL",
+		"2fa_enabled":  "X2faEnabled",
+		"in_progress":  "InProgress",
+	}
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
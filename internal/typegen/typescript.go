@@ -0,0 +1,331 @@
+package typegen
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/qubitquilt/supactl/internal/pgschema"
+)
+
+// tsJSON is the type used for json and jsonb values
+const tsJSON = `export type Json =
+  | string
+  | number
+  | boolean
+  | null
+  | { [key: string]: Json | undefined }
+  | Json[]
+`
+
+// tsIdentifier matches property names that need no quoting
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsScalars maps Postgres base types to TypeScript types. Unknown types become unknown.
+var tsScalars = map[string]string{
+	"boolean":                     "boolean",
+	"smallint":                    "number",
+	"integer":                     "number",
+	"bigint":                      "number",
+	"real":                        "number",
+	"double precision":            "number",
+	"numeric":                     "number",
+	"oid":                         "number",
+	"json":                        "Json",
+	"jsonb":                       "Json",
+	"text":                        "string",
+	"character varying":           "string",
+	"character":                   "string",
+	`"char"`:                      "string",
+	"name":                        "string",
+	"citext":                      "string",
+	"uuid":                        "string",
+	"date":                        "string",
+	"time without time zone":      "string",
+	"time with time zone":         "string",
+	"timestamp without time zone": "string",
+	"timestamp with time zone":    "string",
+	"interval":                    "string",
+	"bytea":                       "string",
+	"money":                       "string",
+	"inet":                        "string",
+	"cidr":                        "string",
+	"macaddr":                     "string",
+	"tsvector":                    "string",
+	"tsquery":                     "string",
+	"xml":                         "string",
+	"bit":                         "string",
+	"bit varying":                 "string",
+	"void":                        "undefined",
+}
+
+// tsWriter generates a TypeScript Database type in the shape used by supabase-js
+type tsWriter struct {
+	schema  *pgschema.Schema
+	schemas map[string]bool
+	out     strings.Builder
+	depth   int
+}
+
+func writeTypeScript(w io.Writer, s *pgschema.Schema, opts Options) error {
+	g := &tsWriter{schema: s, schemas: make(map[string]bool)}
+	for _, name := range opts.Schemas {
+		g.schemas[name] = true
+	}
+
+	g.out.WriteString("// Code generated by supactl gen types. DO NOT EDIT.\n\n")
+	g.out.WriteString(tsJSON)
+	g.out.WriteString("\n")
+
+	g.open("export type Database = {")
+	for _, name := range opts.Schemas {
+		g.writeSchema(name, objectsIn(s, name))
+	}
+	g.close("}")
+
+	_, err := io.WriteString(w, g.out.String())
+	return err
+}
+
+func (g *tsWriter) writeSchema(name string, objects schemaObjects) {
+	g.open(tsKey(name) + ": {")
+
+	g.open("Tables: {")
+	if len(objects.Tables) == 0 {
+		g.line("[_ in never]: never")
+	}
+	for _, t := range objects.Tables {
+		columns := sortedColumns(t.Columns)
+		g.open(tsKey(t.Name) + ": {")
+
+		g.open("Row: {")
+		for _, c := range columns {
+			g.line("%s: %s", tsKey(c.Name), g.columnType(c))
+		}
+		g.close("}")
+
+		g.open("Insert: {")
+		for _, c := range columns {
+			switch {
+			case c.Generated:
+				g.line("%s?: never", tsKey(c.Name))
+			case insertOptional(c):
+				g.line("%s?: %s", tsKey(c.Name), g.columnType(c))
+			default:
+				g.line("%s: %s", tsKey(c.Name), g.columnType(c))
+			}
+		}
+		g.close("}")
+
+		g.open("Update: {")
+		for _, c := range columns {
+			if c.Generated {
+				g.line("%s?: never", tsKey(c.Name))
+			} else {
+				g.line("%s?: %s", tsKey(c.Name), g.columnType(c))
+			}
+		}
+		g.close("}")
+
+		g.close("}")
+	}
+	g.close("}")
+
+	g.open("Views: {")
+	if len(objects.Views) == 0 {
+		g.line("[_ in never]: never")
+	}
+	for _, v := range objects.Views {
+		g.open(tsKey(v.Name) + ": {")
+		g.open("Row: {")
+		for _, c := range sortedColumns(v.Columns) {
+			g.line("%s: %s", tsKey(c.Name), g.columnType(c))
+		}
+		g.close("}")
+		g.close("}")
+	}
+	g.close("}")
+
+	g.open("Functions: {")
+	if len(objects.Functions) == 0 {
+		g.line("[_ in never]: never")
+	}
+	for i := 0; i < len(objects.Functions); {
+		// Overloads share a name and are written as a union
+		j := i
+		for j < len(objects.Functions) && objects.Functions[j].Name == objects.Functions[i].Name {
+			j++
+		}
+		overloads := objects.Functions[i:j]
+
+		if len(overloads) == 1 {
+			g.open(tsKey(overloads[0].Name) + ": {")
+			g.writeFunction(overloads[0])
+			g.close("}")
+		} else {
+			g.line("%s:", tsKey(overloads[0].Name))
+			g.depth++
+			for _, f := range overloads {
+				g.open("| {")
+				g.writeFunction(f)
+				g.close("}")
+			}
+			g.depth--
+		}
+		i = j
+	}
+	g.close("}")
+
+	g.open("Enums: {")
+	if len(objects.Enums) == 0 {
+		g.line("[_ in never]: never")
+	}
+	for _, e := range objects.Enums {
+		g.line("%s: %s", tsKey(e.Name), tsUnion(e.Values))
+	}
+	g.close("}")
+
+	g.close("}")
+}
+
+// writeFunction writes the Args and Returns members of a function
+func (g *tsWriter) writeFunction(f *pgschema.Function) {
+	inputs := f.Inputs()
+	if len(inputs) == 0 {
+		g.line("Args: Record<PropertyKey, never>")
+	} else {
+		g.open("Args: {")
+		for _, p := range inputs {
+			optional := ""
+			if p.HasDefault {
+				optional = "?"
+			}
+			g.line("%s%s: %s", tsKey(p.Name), optional, g.typeOf(parseType(g.schema, p.Type)))
+		}
+		g.close("}")
+	}
+
+	outputs := f.Outputs()
+	if len(outputs) == 0 {
+		returns := g.typeOf(parseType(g.schema, f.Returns))
+		if f.ReturnsSet && returns != "undefined" {
+			returns = tsArray(returns)
+		}
+		g.line("Returns: %s", returns)
+		return
+	}
+
+	g.open("Returns: {")
+	for _, p := range outputs {
+		g.line("%s: %s | null", tsKey(p.Name), g.typeOf(parseType(g.schema, p.Type)))
+	}
+	if f.ReturnsSet {
+		g.close("}[]")
+	} else {
+		g.close("}")
+	}
+}
+
+// columnType returns the type of a column, adding null for nullable columns
+func (g *tsWriter) columnType(c pgschema.Column) string {
+	typ := g.typeOf(parseType(g.schema, c.Type))
+	if !c.NotNull {
+		typ += " | null"
+	}
+	return typ
+}
+
+// typeOf maps a Postgres type to TypeScript. Enums, tables and views of the generated
+// schemas are referenced through the Database type.
+func (g *tsWriter) typeOf(t pgType) string {
+	var typ string
+	switch {
+	case t.Enum != nil && g.schemas[t.Enum.Schema]:
+		typ = fmt.Sprintf("Database[%s][\"Enums\"][%s]", strconv.Quote(t.Enum.Schema), strconv.Quote(t.Enum.Name))
+	case t.Enum != nil:
+		typ = tsUnion(t.Enum.Values)
+	default:
+		if row := g.rowReference(t.Base); row != "" {
+			typ = row
+		} else if scalar, ok := tsScalars[t.Base]; ok {
+			typ = scalar
+		} else {
+			typ = "unknown"
+		}
+	}
+
+	for i := 0; i < t.Dims; i++ {
+		typ = tsArray(typ)
+	}
+	return typ
+}
+
+// rowReference returns the Row type of a table or view used as a composite type, if any
+func (g *tsWriter) rowReference(name string) string {
+	schema, relation, qualified := strings.Cut(name, ".")
+	if !qualified {
+		schema, relation = "public", name
+	}
+	schema, relation = unquote(schema), unquote(relation)
+	if !g.schemas[schema] {
+		return ""
+	}
+
+	key := pgschema.QuoteIdent(schema) + "." + pgschema.QuoteIdent(relation)
+	section := ""
+	if _, ok := g.schema.Tables[key]; ok {
+		section = "Tables"
+	} else if _, ok := g.schema.Views[key]; ok {
+		section = "Views"
+	} else {
+		return ""
+	}
+
+	return fmt.Sprintf("Database[%s][%q][%s][\"Row\"]", strconv.Quote(schema), section, strconv.Quote(relation))
+}
+
+func (g *tsWriter) open(text string) {
+	g.line("%s", text)
+	g.depth++
+}
+
+func (g *tsWriter) close(text string) {
+	g.depth--
+	g.line("%s", text)
+}
+
+func (g *tsWriter) line(format string, args ...interface{}) {
+	g.out.WriteString(strings.Repeat("  ", g.depth))
+	fmt.Fprintf(&g.out, format, args...)
+	g.out.WriteString("\n")
+}
+
+// tsKey returns a property name, quoted if it is not a plain identifier
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsUnion returns a union of string literals
+func tsUnion(values []string) string {
+	if len(values) == 0 {
+		return "never"
+	}
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, " | ")
+}
+
+// tsArray returns the array type of typ, parenthesizing unions
+func tsArray(typ string) string {
+	if strings.Contains(typ, " | ") {
+		return "(" + typ + ")[]"
+	}
+	return typ + "[]"
+}